@@ -0,0 +1,303 @@
+// Command conformance exports the outputs of this library as BbsBlsSignature2020 conformance vectors,
+// and runs the conformance vectors of other implementations. The exports are deterministic test data:
+// they must never be signed with a production key.
+//
+//	go run ./cmd/conformance export -credential unsigned.json -frame frame.json -context https://w3id.org/citizenship/v1=context.json -out vectors
+//	go run ./cmd/conformance run -dir vectors -bbs-dir vectors/external/bbs
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	jsonldbbs "github.com/hyperledger-labs/jsonld-vc-bbs-go"
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+)
+
+// the test key pair of the examples, never to be used outside of tests
+const (
+	defaultPublicKeyHex  = "98ae11750ca7abb2e8ad1a9e55bc7226c5475a961f2bf4867285be12a519f6ddb5671f999ffd0f3ee6a6b2ea16f6cfa90086c14307bfc4e8e07d9c703603177e96874d8cba268d6d01a34cd8b418a4ffcc3ce5376b339d049cadeba06f959399"
+	defaultPrivateKeyHex = "13e86bd1a774b4609108a920c2886394e76c8db8502fbc380d1a21f8be835cef"
+)
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		log.Fatal("usage: conformance export|run [flags]")
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "export":
+		err = export(os.Args[2:])
+	case "run":
+		err = run(os.Args[2:])
+	default:
+		err = fmt.Errorf("unknown command %q, expected export or run", os.Args[1])
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// export Sign a credential, derive a proof from it, and write them as a valid vector and as tampered vectors.
+func export(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	name := flags.String("name", "permanent-resident-card", "name of the vector, and of its files")
+	credentialPath := flags.String("credential", "", "unsigned credential (required)")
+	framePath := flags.String("frame", "", "frame of the derived proof (required)")
+	out := flags.String("out", "vectors", "output directory")
+	publicKeyHex := flags.String("public-key", defaultPublicKeyHex, "BBS+ public key, in hex")
+	privateKeyHex := flags.String("private-key", defaultPrivateKeyHex, "BBS+ private key, in hex")
+	ciphersuite := flags.String("ciphersuite", "", "BBS ciphersuite: BBS+ (default), BLS12-381-SHA-256 or BLS12-381-SHAKE-256")
+	seed := flags.String("seed", "jsonld-vc-bbs-go", "seed of the random factors of the signature and of the derived proof, hashed with the inputs for a key other than the test key")
+	created := flags.String("created", "2024-01-01T00:00:00Z", "creation time of the proof")
+	nonce := flags.String("nonce", base64.StdEncoding.EncodeToString([]byte("conformance")), "nonce of the derived proof, in base64")
+	contexts := contextFlag{}
+	flags.Var(contexts, "context", "additional context, as url=path (repeatable)")
+	_ = flags.Parse(args)
+
+	if *credentialPath == "" || *framePath == "" {
+		return fmt.Errorf("-credential and -frame are required")
+	}
+	publicKey, err := hex.DecodeString(*publicKeyHex)
+	if err != nil {
+		return fmt.Errorf("public key: %w", err)
+	}
+	privateKey, err := hex.DecodeString(*privateKeyHex)
+	if err != nil {
+		return fmt.Errorf("private key: %w", err)
+	}
+	createdTime, err := time.Parse(c.ProofTimestampFormat, *created)
+	if err != nil {
+		return fmt.Errorf("created: %w", err)
+	}
+	nonceBytes, err := base64.StdEncoding.DecodeString(*nonce)
+	if err != nil {
+		return fmt.Errorf("nonce: %w", err)
+	}
+	var credential model.JsonLdCredentialNoProof
+	if err := readJSON(*credentialPath, &credential); err != nil {
+		return err
+	}
+	var frame model.JsonLdFrame
+	if err := readJSON(*framePath, &frame); err != nil {
+		return err
+	}
+
+	randomSeed, err := exportSeed([]byte(*seed), privateKey, credential, frame, nonceBytes)
+	if err != nil {
+		return err
+	}
+	options := &model.SignatureSuiteOptions{
+		Contexts:    contexts,
+		Clock:       func() time.Time { return createdTime },
+		Rand:        &seededReader{seed: randomSeed},
+		Ciphersuite: model.BbsCiphersuite(*ciphersuite),
+	}
+	vector, err := jsonldbbs.ExportConformanceVector(context.Background(), *name, publicKey, privateKey, credential, frame, nonceBytes, options)
+	if err != nil {
+		return err
+	}
+	vector.Description = "Signed credential and derived proof."
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		return err
+	}
+	if err := writeVector(*out, vector); err != nil {
+		return err
+	}
+
+	// the signature covers the proof options: a modified creation time must be rejected
+	tamperedSignature := *vector
+	tamperedSignature.Name = vector.Name + "-tampered-signature"
+	tamperedSignature.Description = "The creation time of the signature has been modified."
+	tamperedSignature.SignedCredential = tamperProof(vector.SignedCredential, c.CredentialFieldCreated, "2000-01-01T00:00:00Z")
+	tamperedSignature.SignatureValid = false
+	tamperedSignature.Frame, tamperedSignature.Nonce, tamperedSignature.DerivedCredential = nil, "", nil
+	if err := writeVector(*out, &tamperedSignature); err != nil {
+		return err
+	}
+
+	// the derived proof is bound to the nonce of the verifier: another nonce must be rejected
+	tamperedProof := *vector
+	tamperedProof.Name = vector.Name + "-tampered-nonce"
+	tamperedProof.Description = "The nonce of the derived proof has been replaced."
+	tamperedProof.DerivedCredential = tamperProof(vector.DerivedCredential, c.CredentialFieldNonce, base64.StdEncoding.EncodeToString([]byte("another nonce")))
+	tamperedProof.DerivedProofValid = false
+	tamperedProof.Frame = nil
+
+	return writeVector(*out, &tamperedProof)
+}
+
+// run Run the conformance vectors and the BBS vectors of directories, and fail if a check does not have the expected outcome.
+func run(args []string) error {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	dir := flags.String("dir", "vectors", "directory of the conformance vectors")
+	bbsDir := flags.String("bbs-dir", "", "directory of the BBS vectors over raw messages (optional)")
+	_ = flags.Parse(args)
+
+	vectors, err := jsonldbbs.LoadConformanceVectors(*dir)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, vector := range vectors {
+		results, err := jsonldbbs.RunConformanceVector(context.Background(), vector, nil)
+		if err != nil {
+			return err
+		}
+		failed += printResults(results)
+	}
+	if *bbsDir != "" {
+		bbsVectors, err := jsonldbbs.LoadBbsVectors(*bbsDir)
+		if err != nil {
+			return err
+		}
+		for _, vector := range bbsVectors {
+			results, err := jsonldbbs.RunBbsVector(vector)
+			if err != nil {
+				return err
+			}
+			failed += printResults(results)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d checks failed", failed)
+	}
+
+	return nil
+}
+
+// printResults Print the results of the checks of a vector, and return the number of failed checks.
+func printResults(results []*model.ConformanceResult) int {
+	failed := 0
+	for _, result := range results {
+		status := "PASS"
+		if !result.Passed {
+			status = "FAIL"
+			failed++
+		}
+		if result.Error != nil {
+			fmt.Printf("%s %s %s (%s)\n", status, result.Vector, result.Check, result.Error)
+		} else {
+			fmt.Printf("%s %s %s\n", status, result.Vector, result.Check)
+		}
+	}
+
+	return failed
+}
+
+// exportSeed Compute the seed of the random factors of an export.
+// The private key of the test key pair is public, so that its vectors use the seed as is, and are reproducible.
+// With any other key, random factors reused across different messages would disclose the key and allow forgeries:
+// the seed is hashed with the key and the inputs, and the vectors must still never be signed with a production key.
+func exportSeed(seed, privateKey []byte, credential model.JsonLdCredentialNoProof, frame model.JsonLdFrame, nonce []byte) ([]byte, error) {
+	if hex.EncodeToString(privateKey) == defaultPrivateKeyHex {
+		return seed, nil
+	}
+
+	inputs, err := json.Marshal([]interface{}{credential, frame})
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.New()
+	for _, data := range [][]byte{seed, privateKey, inputs, nonce} {
+		hash.Write(binary.BigEndian.AppendUint64(nil, uint64(len(data))))
+		hash.Write(data)
+	}
+
+	return hash.Sum(nil), nil
+}
+
+// tamperProof Copy a signed credential, replacing a field of its proof.
+func tamperProof(credential model.JsonLdCredential, field string, value interface{}) model.JsonLdCredential {
+	data, _ := json.Marshal(credential)
+	var tampered model.JsonLdCredential
+	_ = json.Unmarshal(data, &tampered)
+	tampered[c.CredentialFieldProof].(map[string]interface{})[field] = value
+
+	return tampered
+}
+
+func writeVector(dir string, vector *model.ConformanceVector) error {
+	data, err := json.MarshalIndent(vector, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, vector.Name+".json"), append(data, '\n'), 0o644)
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// A contextFlag collects the contexts given as url=path.
+type contextFlag map[string]map[string]interface{}
+
+func (f contextFlag) String() string {
+	urls := make([]string, 0, len(f))
+	for url := range f {
+		urls = append(urls, url)
+	}
+
+	return strings.Join(urls, ",")
+}
+
+func (f contextFlag) Set(value string) error {
+	url, path, found := strings.Cut(value, "=")
+	if !found {
+		return fmt.Errorf("expected url=path, got %q", value)
+	}
+	var document map[string]interface{}
+	if err := readJSON(path, &document); err != nil {
+		return err
+	}
+	f[url] = document
+
+	return nil
+}
+
+// A seededReader is a deterministic source of randomness: the SHA-256 hashes of a seed and a counter.
+// It makes the exported vectors reproducible, and must never be used outside of tests.
+type seededReader struct {
+	seed    []byte
+	counter uint64
+	buffer  []byte
+}
+
+func (r *seededReader) Read(p []byte) (int, error) {
+	for n := 0; n < len(p); {
+		if len(r.buffer) == 0 {
+			block := sha256.Sum256(binary.BigEndian.AppendUint64(append([]byte(nil), r.seed...), r.counter))
+			r.buffer = block[:]
+			r.counter++
+		}
+		copied := copy(p[n:], r.buffer)
+		r.buffer = r.buffer[copied:]
+		n += copied
+	}
+
+	return len(p), nil
+}
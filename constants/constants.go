@@ -15,6 +15,7 @@ const (
 
 const (
 	ContextCredentialV1           = "https://www.w3.org/2018/credentials/v1"
+	ContextCredentialV2           = "https://www.w3.org/ns/credentials/v2"
 	ContextSecurityBbsV1          = "https://w3id.org/security/bbs/v1"
 	ContextVCRevocationList2020V1 = "https://w3id.org/vc-revocation-list-2020/v1"
 	ContextCitizenshipV1          = "https://w3id.org/citizenship/v1"
@@ -22,3 +23,62 @@ const (
 )
 
 const ProofTimestampFormat = "2006-01-02T15:04:05Z"
+
+const (
+	KeystoreVersion         = 1
+	KeystoreKDFScrypt       = "scrypt"
+	KeystoreKDFArgon2id     = "argon2id"
+	KeystoreCipherAES256GCM = "aes-256-gcm"
+)
+
+const (
+	ContextDidV1      = "https://www.w3.org/ns/did/v1"
+	ContextMultikeyV1 = "https://w3id.org/security/multikey/v1"
+)
+
+const (
+	DidMethodKey = "key"
+	DidMethodWeb = "web"
+)
+
+const (
+	VerificationMethodTypeBls12381G2Key2020 = "Bls12381G2Key2020"
+	VerificationMethodTypeMultikey          = "Multikey"
+)
+
+const (
+	ProofPurposeAssertionMethod      = "assertionMethod"
+	ProofPurposeAuthentication       = "authentication"
+	ProofPurposeCapabilityInvocation = "capabilityInvocation"
+	ProofPurposeCapabilityDelegation = "capabilityDelegation"
+)
+
+// HolderBindingMarker Message signed, and always disclosed, before the hidden secret of a credential bound to its holder.
+// It is not a valid N-Quad, so that it cannot be confused with a statement of the credential.
+const HolderBindingMarker = "holder-binding:bbs-bls-2020:v1"
+
+const HolderSecretSize = 32
+
+// PredicateEncodingMarker Prefix of the message signed before the integer encoding of a claim,
+// disclosed to prove a predicate on the hidden claim. It is not a valid N-Quad either.
+const PredicateEncodingMarker = "predicate-encoding:bbs-bls-2020:v1"
+
+// ClaimLinkMarker Prefix of the serialization of the links of a linked proof, bound to the challenge of its proofs
+// so that the links cannot be removed nor relabeled once the proofs are generated.
+const ClaimLinkMarker = "claim-link:bbs-bls-2020:v1"
+
+// PredicateRangeBits The size of the ranges proven by the predicate proofs: the difference between the claim and the bound
+// must be lower than 2^PredicateRangeBits.
+const PredicateRangeBits = 64
+
+const (
+	XsdInteger  = "http://www.w3.org/2001/XMLSchema#integer"
+	XsdDate     = "http://www.w3.org/2001/XMLSchema#date"
+	XsdDateTime = "http://www.w3.org/2001/XMLSchema#dateTime"
+)
+
+// DefaultRareValueThreshold The frequency among the holders under which a disclosed value is reported by the privacy linter.
+const DefaultRareValueThreshold = 0.05
+
+// PseudonymDomain Domain separation tag of the hash of the verifier identifiers to the bases of the pseudonyms.
+const PseudonymDomain = "pseudonym:bbs-bls-2020:v1"
@@ -1,14 +1,23 @@
 package constants
 
 const (
-	CredentialFieldIssuer             = "issuer"
-	CredentialFieldContext            = "@context"
-	CredentialFieldType               = "type"
-	CredentialFieldCreated            = "created"
-	CredentialFieldProof              = "proof"
-	CredentialFieldProofPurpose       = "proofPurpose"
-	CredentialFieldVerificationMethod = "verificationMethod"
-	CredentialFieldProofValue         = "proofValue"
-	CredentialFieldCredentialSubject  = "credentialSubject"
-	CredentialFieldNonce              = "nonce"
+	CredentialFieldIssuer               = "issuer"
+	CredentialFieldContext              = "@context"
+	CredentialFieldType                 = "type"
+	CredentialFieldCreated              = "created"
+	CredentialFieldProof                = "proof"
+	CredentialFieldProofPurpose         = "proofPurpose"
+	CredentialFieldVerificationMethod   = "verificationMethod"
+	CredentialFieldProofValue           = "proofValue"
+	CredentialFieldCredentialSubject    = "credentialSubject"
+	CredentialFieldNonce                = "nonce"
+	CredentialFieldId                   = "id"
+	CredentialFieldIssuanceDate         = "issuanceDate"
+	CredentialFieldValidFrom            = "validFrom"
+	CredentialFieldExpirationDate       = "expirationDate"
+	CredentialFieldHolder               = "holder"
+	CredentialFieldVerifiableCredential = "verifiableCredential"
+	CredentialFieldPredicates           = "predicates"
+	CredentialFieldPseudonym            = "pseudonym"
+	CredentialFieldCiphersuite          = "ciphersuite"
 )
@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"log"
+	"time"
 
 	jsonldbbs "github.com/hyperledger-labs/jsonld-vc-bbs-go"
 	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
@@ -88,9 +89,16 @@ func main() {
 		log.Fatalf("Error %s", err.Error())
 	}
 
+	// issue a nonce for the holder
+	nonceManager := jsonldbbs.NewNonceManager(nil, 5*time.Minute)
+	nonceBytes, err := nonceManager.Issue("session")
+	if err != nil {
+		log.Fatalf("Error %s", err.Error())
+	}
+
 	// selectively disclose credential
 	sigProofSuite := jsonldbbs.NewJsonLDBBSSignatureProofSuite2020(publicKey, nil)
-	proof, err := sigProofSuite.DeriveProof(signedCred, frameDocument, []byte("nonce"))
+	proof, err := sigProofSuite.DeriveProof(signedCred, frameDocument, nonceBytes)
 	if err != nil {
 		log.Fatalf("Error %s", err.Error())
 	}
@@ -101,7 +109,10 @@ func main() {
 	log.Printf("Selective disclosed credential: %s\n", serializedProof)
 
 	// verify disclosed credential
-	result = sigProofSuite.VerifyProof(proof)
+	result = sigProofSuite.VerifyProofWithOptions(proof, &model.VerifyProofOptions{
+		NonceChecker: nonceManager,
+		SessionID:    "session",
+	})
 	if !result.Success {
 		log.Fatalf("Error %s", result.Error.Error())
 	}
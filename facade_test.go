@@ -0,0 +1,261 @@
+package jsonldbbs_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	jsonldbbs "github.com/hyperledger-labs/jsonld-vc-bbs-go"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type FacadeTestSuite struct {
+	suite.Suite
+	contexts   jsonldbbs.Option
+	publicKey  []byte
+	privateKey []byte
+	credential model.JsonLdCredentialNoProof
+	frame      model.JsonLdFrame
+}
+
+func TestFacadeTestSuite(t *testing.T) {
+	suite.Run(t, new(FacadeTestSuite))
+}
+
+func (s *FacadeTestSuite) SetupTest() {
+	var err error
+	s.publicKey, err = hex.DecodeString("98ae11750ca7abb2e8ad1a9e55bc7226c5475a961f2bf4867285be12a519f6ddb5671f999ffd0f3ee6a6b2ea16f6cfa90086c14307bfc4e8e07d9c703603177e96874d8cba268d6d01a34cd8b418a4ffcc3ce5376b339d049cadeba06f959399")
+	s.Require().NoError(err)
+	s.privateKey, err = hex.DecodeString("13e86bd1a774b4609108a920c2886394e76c8db8502fbc380d1a21f8be835cef")
+	s.Require().NoError(err)
+
+	s.contexts = jsonldbbs.WithContexts(map[string]map[string]interface{}{
+		"https://w3id.org/citizenship/v1": s.readJSON("internal/core/testdata/customResidentCardContext.json"),
+		"https://w3id.org/security/v1":    s.readJSON("internal/core/testdata/securityV1Context.json"),
+	})
+	s.credential = s.readJSON("internal/core/testdata/unsignedCredential.json")
+	s.frame = s.readJSON("internal/core/testdata/frame.json")
+}
+
+func (s *FacadeTestSuite) readJSON(path string) map[string]interface{} {
+	var document map[string]interface{}
+	documentBytes, err := os.ReadFile(path)
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(documentBytes, &document))
+
+	return document
+}
+
+func (s *FacadeTestSuite) TestIssueAndPresent() {
+	ctx := context.Background()
+	issuer, err := jsonldbbs.NewIssuer(ctx, jsonldbbs.WithKeyPair(s.publicKey, s.privateKey), s.contexts)
+	s.Require().NoError(err)
+	secret, err := jsonldbbs.NewHolderSecret()
+	s.Require().NoError(err)
+	holder, err := jsonldbbs.NewHolder(
+		jsonldbbs.WithIssuerPublicKey(issuer.PublicKey()),
+		jsonldbbs.WithHolderSecret(secret),
+		jsonldbbs.WithHolderID("did:example:holder"),
+		s.contexts,
+	)
+	s.Require().NoError(err)
+	nonces := jsonldbbs.NewNonceManager(jsonldbbs.NewInMemoryNonceStore(), time.Minute)
+	verifier, err := jsonldbbs.NewVerifier(
+		jsonldbbs.WithIssuerPublicKey(issuer.PublicKey()),
+		jsonldbbs.WithNonceChecker(nonces),
+		s.contexts,
+	)
+	s.Require().NoError(err)
+
+	// a credential signed for anyone
+	signed, err := issuer.Issue(ctx, s.credential)
+	s.Require().NoError(err)
+	result := holder.Verify(ctx, signed)
+	s.True(result.Success, result.Error)
+	result = verifier.VerifyCredential(ctx, signed)
+	s.True(result.Success, result.Error)
+
+	// a credential bound to the holder secret
+	issuerNonce := []byte("issuer nonce")
+	messagesCount, err := issuer.PrepareHolderBinding(ctx, s.credential)
+	s.Require().NoError(err)
+	request, blinding, err := holder.RequestBinding(messagesCount, issuerNonce)
+	s.Require().NoError(err)
+	blinded, err := issuer.IssueHolderBound(ctx, s.credential, request, issuerNonce)
+	s.Require().NoError(err)
+	bound, err := holder.CompleteBinding(ctx, blinded, blinding)
+	s.Require().NoError(err)
+
+	nonce, err := nonces.Issue("session")
+	s.Require().NoError(err)
+	presentation, err := holder.Present(ctx, nonce,
+		jsonldbbs.Disclosure{Credential: signed, Frame: s.frame},
+		jsonldbbs.Disclosure{Credential: bound, Frame: s.frame},
+	)
+	s.Require().NoError(err)
+	s.Equal("did:example:holder", presentation.Holder)
+	s.Len(presentation.VerifiableCredential, 2)
+
+	options := &model.VerifyProofOptions{SessionID: "session"}
+	result = verifier.VerifyPresentation(ctx, presentation, options)
+	s.True(result.Success, result.Error)
+
+	// the nonce is consumed once for the whole presentation
+	result = verifier.VerifyPresentation(ctx, presentation, options)
+	s.False(result.Success)
+	s.ErrorIs(result.Error, model.ErrNonceNotFound)
+}
+
+func (s *FacadeTestSuite) TestPresentUndisclosedIssuanceDate() {
+	ctx := context.Background()
+	issuer, err := jsonldbbs.NewIssuer(ctx, jsonldbbs.WithKeyPair(s.publicKey, s.privateKey), s.contexts)
+	s.Require().NoError(err)
+	holder, err := jsonldbbs.NewHolder(jsonldbbs.WithIssuerPublicKey(issuer.PublicKey()), s.contexts)
+	s.Require().NoError(err)
+	verifier, err := jsonldbbs.NewVerifier(jsonldbbs.WithIssuerPublicKey(issuer.PublicKey()), s.contexts)
+	s.Require().NoError(err)
+
+	signed, err := issuer.Issue(ctx, s.credential)
+	s.Require().NoError(err)
+	frame := model.JsonLdFrame{}
+	for key, value := range s.frame {
+		frame[key] = value
+	}
+	delete(frame, "issuanceDate")
+	presentation, err := holder.Present(ctx, []byte("nonce"), jsonldbbs.Disclosure{Credential: signed, Frame: frame})
+	s.Require().NoError(err)
+	s.Require().Len(presentation.VerifiableCredential, 1)
+	s.Empty(presentation.VerifiableCredential[0].IssuanceDate)
+
+	result := verifier.VerifyPresentation(ctx, presentation, nil)
+	s.True(result.Success, result.Error)
+}
+
+func (s *FacadeTestSuite) TestDeriveTamperedCredential() {
+	ctx := context.Background()
+	issuer, err := jsonldbbs.NewIssuer(ctx, jsonldbbs.WithKeyPair(s.publicKey, s.privateKey), s.contexts)
+	s.Require().NoError(err)
+	secret, err := jsonldbbs.NewHolderSecret()
+	s.Require().NoError(err)
+	holder, err := jsonldbbs.NewHolder(jsonldbbs.WithIssuerPublicKey(s.publicKey), jsonldbbs.WithHolderSecret(secret), s.contexts)
+	s.Require().NoError(err)
+
+	// a credential that does not verify is not taken for a credential bound to the holder
+	signed, err := issuer.Issue(ctx, s.credential)
+	s.Require().NoError(err)
+	signed["issuanceDate"] = "2020-01-01T00:00:00Z"
+	_, err = holder.Derive(ctx, signed, s.frame, []byte("nonce"), nil)
+	s.ErrorContains(err, "with and without the holder secret")
+}
+
+func (s *FacadeTestSuite) TestNonceMismatch() {
+	ctx := context.Background()
+	issuer, err := jsonldbbs.NewIssuer(ctx, jsonldbbs.WithKeyPair(s.publicKey, s.privateKey), s.contexts)
+	s.Require().NoError(err)
+	holder, err := jsonldbbs.NewHolder(jsonldbbs.WithIssuerPublicKey(s.publicKey), s.contexts)
+	s.Require().NoError(err)
+	verifier, err := jsonldbbs.NewVerifier(jsonldbbs.WithIssuerPublicKey(s.publicKey), s.contexts)
+	s.Require().NoError(err)
+
+	signed, err := issuer.Issue(ctx, s.credential)
+	s.Require().NoError(err)
+	first, err := holder.Present(ctx, []byte("first nonce"), jsonldbbs.Disclosure{Credential: signed, Frame: s.frame})
+	s.Require().NoError(err)
+	second, err := holder.Present(ctx, []byte("second nonce"), jsonldbbs.Disclosure{Credential: signed, Frame: s.frame})
+	s.Require().NoError(err)
+
+	first.VerifiableCredential = append(first.VerifiableCredential, second.VerifiableCredential...)
+	result := verifier.VerifyPresentation(ctx, first, nil)
+	s.False(result.Success)
+	s.ErrorIs(result.Error, model.ErrNonceMismatch)
+}
+
+func (s *FacadeTestSuite) TestCiphersuite() {
+	ctx := context.Background()
+	ciphersuite := jsonldbbs.WithCiphersuite(model.BbsCiphersuiteBLS12381Sha256)
+	issuer, err := jsonldbbs.NewIssuer(ctx, jsonldbbs.WithKeyPair(s.publicKey, s.privateKey), ciphersuite, s.contexts)
+	s.Require().NoError(err)
+	holder, err := jsonldbbs.NewHolder(jsonldbbs.WithIssuerPublicKey(s.publicKey), ciphersuite, s.contexts)
+	s.Require().NoError(err)
+	verifier, err := jsonldbbs.NewVerifier(jsonldbbs.WithIssuerPublicKey(s.publicKey), ciphersuite, s.contexts)
+	s.Require().NoError(err)
+
+	signed, err := issuer.Issue(ctx, s.credential)
+	s.Require().NoError(err)
+	result := verifier.VerifyCredential(ctx, signed)
+	s.True(result.Success, result.Error)
+	presentation, err := holder.Present(ctx, []byte("nonce"), jsonldbbs.Disclosure{Credential: signed, Frame: s.frame})
+	s.Require().NoError(err)
+	result = verifier.VerifyPresentation(ctx, presentation, nil)
+	s.True(result.Success, result.Error)
+
+	// a verifier of the BBS+ scheme
+	verifier, err = jsonldbbs.NewVerifier(jsonldbbs.WithIssuerPublicKey(s.publicKey), s.contexts)
+	s.Require().NoError(err)
+	s.False(verifier.VerifyCredential(ctx, signed).Success)
+	s.False(verifier.VerifyPresentation(ctx, presentation, nil).Success)
+
+	_, err = jsonldbbs.NewVerifier(jsonldbbs.WithIssuerPublicKey(s.publicKey), jsonldbbs.WithCiphersuite("BBS-2"))
+	s.ErrorIs(err, model.ErrUnknownCiphersuite)
+}
+
+func (s *FacadeTestSuite) TestExportedTypes() {
+	ctx := context.Background()
+	options := &model.SignatureSuiteOptions{}
+	var signatureSuite *jsonldbbs.SignatureSuite2020 = jsonldbbs.NewJsonLDBBSSignatureSuite2020(s.publicKey, s.privateKey, options)
+	var proofSuite *jsonldbbs.SignatureProofSuite2020 = jsonldbbs.NewJsonLDBBSSignatureProofSuite2020(s.publicKey, options)
+	s.NotNil(proofSuite)
+	s.Equal(s.publicKey, signatureSuite.PublicKey())
+
+	var store *jsonldbbs.InMemoryNonceStore = jsonldbbs.NewInMemoryNonceStore()
+	var nonceManager *jsonldbbs.NonceManager = jsonldbbs.NewNonceManager(store, time.Minute)
+	nonce, err := nonceManager.Issue("session")
+	s.Require().NoError(err)
+	s.NoError(nonceManager.ConsumeNonce("session", nonce))
+
+	var signer *jsonldbbs.InMemorySigner = jsonldbbs.NewInMemorySigner(s.publicKey, s.privateKey)
+	defer signer.Destroy()
+	var handler *jsonldbbs.SignerHandler = jsonldbbs.NewSignerHandler(signer)
+	var httpSigner *jsonldbbs.HTTPSigner = jsonldbbs.NewHTTPSigner("http://localhost", nil)
+	s.NotNil(handler)
+	s.NotNil(httpSigner)
+
+	var resolver *jsonldbbs.MethodDidResolver = jsonldbbs.NewDidResolver(nil)
+	var builder *jsonldbbs.DidDocumentBuilder
+	builder, err = jsonldbbs.NewDidKeyDocumentBuilder(model.DidDocumentKey{PublicKey: s.publicKey})
+	s.Require().NoError(err)
+	document, err := builder.Build()
+	s.Require().NoError(err)
+	resolved, err := resolver.Resolve(ctx, document.ID)
+	s.Require().NoError(err)
+	s.Equal(document.ID, resolved.ID)
+}
+
+func (s *FacadeTestSuite) TestInvalidConfiguration() {
+	ctx := context.Background()
+	_, err := jsonldbbs.NewIssuer(ctx, s.contexts)
+	s.ErrorIs(err, model.ErrNoSigner)
+	_, err = jsonldbbs.NewIssuer(ctx, jsonldbbs.WithKeyPair(s.publicKey, nil))
+	s.Error(err)
+	_, err = jsonldbbs.NewHolder(s.contexts)
+	s.Error(err)
+	_, err = jsonldbbs.NewVerifier(s.contexts)
+	s.Error(err)
+
+	// the trusted issuer ids are asserted by the credentials without issuer binding
+	trusted := &model.VerificationPolicy{Rules: []model.PolicyRule{{TrustedIssuers: []string{"did:example:issuer"}}}}
+	_, err = jsonldbbs.NewVerifier(jsonldbbs.WithIssuerPublicKey(s.publicKey), jsonldbbs.WithoutIssuerBinding(), jsonldbbs.WithPolicy(trusted))
+	s.ErrorIs(err, model.ErrTrustedIssuersWithoutBinding)
+	trusted.Rules[0].TrustedKeys, trusted.Rules[0].TrustedIssuers = trusted.Rules[0].TrustedIssuers, nil
+	_, err = jsonldbbs.NewVerifier(jsonldbbs.WithIssuerPublicKey(s.publicKey), jsonldbbs.WithoutIssuerBinding(), jsonldbbs.WithPolicy(trusted))
+	s.NoError(err)
+
+	holder, err := jsonldbbs.NewHolder(jsonldbbs.WithIssuerPublicKey(s.publicKey))
+	s.Require().NoError(err)
+	_, _, err = holder.RequestBinding(10, []byte("issuer nonce"))
+	s.ErrorIs(err, jsonldbbs.ErrNoHolderSecret)
+}
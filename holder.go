@@ -0,0 +1,213 @@
+package jsonldbbs
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"sync"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+)
+
+// ErrNoHolderSecret The holder has been created without a holder secret.
+var ErrNoHolderSecret = errors.New("no holder secret configured")
+
+// A Holder keeps the credentials signed by an issuer and presents proofs derived from them to the verifiers.
+// A Holder is safe for concurrent use, provided that its document loader is.
+type Holder struct {
+	publicKey      []byte
+	secret         model.HolderSecret // nil if the credentials are not bound to the holder
+	randMu         sync.Mutex         // serializes the reads of rand by the concurrent binding requests
+	rand           io.Reader          // the source of the blinding factors of the binding requests
+	id             string
+	signatureSuite *core.SignatureSuite2020
+	proofSuite     *core.SignatureProofSuite2020
+}
+
+// A Disclosure selects the claims of a credential to disclose in a presentation.
+type Disclosure struct {
+	Credential model.JsonLdCredential
+	Frame      model.JsonLdFrame
+	Options    *model.DeriveProofOptions // nullable
+}
+
+// NewHolder creates new holder
+// arguments:
+//
+//	options ...Option WithIssuerPublicKey is required, WithHolderSecret for the credentials bound to the holder
+//
+// returns:
+//
+//	holder *Holder
+//	err error
+func NewHolder(options ...Option) (*Holder, error) {
+	cfg, err := newConfig(options)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.publicKey == nil {
+		return nil, errors.New("the public key of the issuer is required")
+	}
+
+	rng := cfg.suiteOptions.Rand
+	if rng == nil {
+		rng = rand.Reader
+	}
+
+	return &Holder{
+		publicKey:      cfg.publicKey,
+		secret:         cfg.holderSecret,
+		rand:           rng,
+		id:             cfg.holderID,
+		signatureSuite: core.NewSignatureSuite2020(cfg.publicKey, nil, &cfg.suiteOptions),
+		proofSuite:     core.NewSignatureProofSuite2020(cfg.publicKey, &cfg.suiteOptions),
+	}, nil
+}
+
+// Verify verifies a credential received from the issuer, not bound to the holder.
+// The credentials bound to the holder are verified by CompleteBinding.
+// arguments:
+//
+//	ctx context.Context
+//	credential model.JsonLdCredential
+//
+// returns:
+//
+//	result *model.VerificationResult
+func (h *Holder) Verify(ctx context.Context, credential model.JsonLdCredential) *model.VerificationResult {
+	return h.signatureSuite.VerifyContext(ctx, credential)
+}
+
+// RequestBinding commits to the holder secret in order to obtain a credential bound to it.
+// arguments:
+//
+//	messagesCount int returned by the PrepareHolderBinding of the issuer
+//	issuerNonce []byte sent by the issuer
+//
+// returns:
+//
+//	request *model.HolderBindingRequest to send to the issuer
+//	blinding []byte to keep private until the credential is completed
+//	err error
+func (h *Holder) RequestBinding(messagesCount int, issuerNonce []byte) (*model.HolderBindingRequest, []byte, error) {
+	if h.secret == nil {
+		return nil, nil, ErrNoHolderSecret
+	}
+
+	h.randMu.Lock()
+	defer h.randMu.Unlock()
+
+	return core.NewHolderBindingRequestWithRand(h.rand, h.secret, h.publicKey, messagesCount, issuerNonce)
+}
+
+// CompleteBinding unblinds the signature of a credential bound to the holder, and verifies it.
+// arguments:
+//
+//	ctx context.Context
+//	credential model.JsonLdCredential received from the issuer
+//	blinding []byte returned by RequestBinding
+//
+// returns:
+//
+//	signedCredential model.JsonLdCredential
+//	err error
+func (h *Holder) CompleteBinding(ctx context.Context, credential model.JsonLdCredential, blinding []byte) (model.JsonLdCredential, error) {
+	if h.secret == nil {
+		return nil, ErrNoHolderSecret
+	}
+
+	return h.signatureSuite.CompleteHolderBinding(ctx, credential, h.secret, blinding)
+}
+
+// Derive derives a proof disclosing the claims of a credential selected by a frame.
+// The holder secret is used for the credentials signed over it, i.e. bound to the holder,
+// and for the pseudonyms, unless the options provide another secret.
+// arguments:
+//
+//	ctx context.Context
+//	credential model.JsonLdCredential
+//	frame model.JsonLdFrame
+//	nonce []byte the challenge of the verifier
+//	options *model.DeriveProofOptions nullable
+//
+// returns:
+//
+//	derivedCredential model.JsonLdCredential
+//	err error
+func (h *Holder) Derive(
+	ctx context.Context,
+	credential model.JsonLdCredential,
+	frame model.JsonLdFrame,
+	nonce []byte,
+	options *model.DeriveProofOptions,
+) (model.JsonLdCredential, error) {
+	options, err := h.deriveOptions(ctx, credential, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.proofSuite.DeriveProofWithOptions(ctx, credential, frame, nonce, options)
+}
+
+// Present derives proofs from credentials and wraps them in a presentation.
+// arguments:
+//
+//	ctx context.Context
+//	nonce []byte the challenge of the verifier, shared by all the derived proofs
+//	disclosures ...Disclosure
+//
+// returns:
+//
+//	presentation *model.VerifiablePresentation
+//	err error
+func (h *Holder) Present(ctx context.Context, nonce []byte, disclosures ...Disclosure) (*model.VerifiablePresentation, error) {
+	presentation := &model.VerifiablePresentation{
+		Context: []interface{}{c.ContextCredentialV1},
+		Type:    []string{"VerifiablePresentation"},
+		Holder:  h.id,
+	}
+	for _, disclosure := range disclosures {
+		derived, err := h.Derive(ctx, disclosure.Credential, disclosure.Frame, nonce, disclosure.Options)
+		if err != nil {
+			return nil, err
+		}
+		credential, err := model.VerifiableCredentialFromMap(derived)
+		if err != nil {
+			return nil, err
+		}
+		presentation.VerifiableCredential = append(presentation.VerifiableCredential, credential)
+	}
+
+	return presentation, nil
+}
+
+// deriveOptions Add the holder secret to the options of a derivation, if the credential is bound to it
+// or if a pseudonym is requested.
+func (h *Holder) deriveOptions(ctx context.Context, credential model.JsonLdCredential, options *model.DeriveProofOptions) (*model.DeriveProofOptions, error) {
+	if h.secret == nil || options != nil && options.HolderSecret != nil {
+		return options, nil
+	}
+
+	bound := options != nil && options.VerifierID != ""
+	if !bound {
+		var err error
+		bound, err = h.signatureSuite.IsHolderBound(ctx, credential, h.secret)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !bound {
+		return options, nil
+	}
+
+	withSecret := model.DeriveProofOptions{}
+	if options != nil {
+		withSecret = *options
+	}
+	withSecret.HolderSecret = h.secret
+
+	return &withSecret, nil
+}
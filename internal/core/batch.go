@@ -0,0 +1,193 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+
+	ml "github.com/IBM/mathlib"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/hyperledger/aries-bbs-go/bbs"
+)
+
+// forEachConcurrently Call process for every index in [0, count) using at most concurrency goroutines.
+// Once the context is done, no new index is processed: cancel is called for every remaining index instead.
+//
+//	ctx context.Context
+//	count int The number of items to process.
+//	options *model.BatchOptions nullable
+//	process func(i int) The function processing a single item.
+//	cancel func(i int) The function marking a single item as cancelled.
+func forEachConcurrently(ctx context.Context, count int, options *model.BatchOptions, process, cancel func(i int)) {
+	concurrency := runtime.GOMAXPROCS(0)
+	if options != nil && options.Concurrency > 0 {
+		concurrency = options.Concurrency
+	}
+	concurrency = min(concurrency, count)
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				// the context may be done while the index is dispatched
+				if ctx.Err() != nil {
+					cancel(i)
+					continue
+				}
+				process(i)
+			}
+		}()
+	}
+
+	for i := 0; i < count; i++ {
+		if ctx.Err() != nil {
+			cancel(i)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			cancel(i)
+		case indexes <- i:
+		}
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// cancelledResult Return the verification result of an item that has not been processed because the context is done.
+func cancelledResult(ctx context.Context) *model.VerificationResult {
+	return &model.VerificationResult{
+		Success: false,
+		Error:   fmt.Errorf("batch processing interrupted: %w", ctx.Err()),
+	}
+}
+
+// A batchSignatureVerifier verifies many BBS+ signatures created with the same public key.
+// The generators derived from the public key are computed once per number of messages.
+type batchSignatureVerifier struct {
+	curve     *ml.Curve
+	lib       *bbs.BBSLib
+	publicKey *bbs.PublicKey
+
+	mu         sync.Mutex
+	generators map[int]*bbs.PublicKeyWithGenerators
+}
+
+// A batchSignature is a parsed BBS+ signature together with the commitment B to the signed messages.
+type batchSignature struct {
+	signature *bbs.Signature
+	b         *ml.G1
+}
+
+// newBatchSignatureVerifier initializes and returns batchSignatureVerifier.
+//
+//	publicKey []byte The BBS+ public key.
+func newBatchSignatureVerifier(publicKey []byte) (*batchSignatureVerifier, error) {
+	curve := newBBSCurve()
+	lib := bbs.NewBBSLib(curve)
+
+	pubKey, err := lib.UnmarshalPublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+
+	return &batchSignatureVerifier{
+		curve:      curve,
+		lib:        lib,
+		publicKey:  pubKey,
+		generators: make(map[int]*bbs.PublicKeyWithGenerators),
+	}, nil
+}
+
+// prepare Parse a signature and compute the commitment to the signed messages.
+//
+//	messages []*bbs.SignatureMessage The signed messages.
+//	signatureBytes []byte The BBS+ signature.
+//
+// returns:
+//
+//	signature *batchSignature
+//	err error
+func (v *batchSignatureVerifier) prepare(messages []*bbs.SignatureMessage, signatureBytes []byte) (*batchSignature, error) {
+	signature, err := v.lib.ParseSignature(signatureBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse signature: %w", err)
+	}
+
+	generators, err := v.getGenerators(len(messages))
+	if err != nil {
+		return nil, err
+	}
+
+	return &batchSignature{
+		signature: signature,
+		b:         bbs.ComputeB(signature.S, messages, generators, v.curve),
+	}, nil
+}
+
+// verify Verify a set of prepared signatures at once.
+// Every signature i satisfies e(A_i, W) * e(e_i * A_i - B_i, g2) = 1. The equations are combined with
+// random coefficients r_i, so that only two pairings are computed for the whole batch:
+// e(sum(r_i * A_i), W) * e(sum(r_i * (e_i * A_i - B_i)), g2) = 1
+//
+//	signatures []*batchSignature
+//
+// returns:
+//
+//	err error if at least one of the signatures is invalid
+func (v *batchSignatureVerifier) verify(signatures []*batchSignature) error {
+	if len(signatures) == 0 {
+		return nil
+	}
+
+	var sumA, sumB *ml.G1
+	for _, item := range signatures {
+		r, err := randomZr(v.curve, rand.Reader)
+		if err != nil {
+			return err
+		}
+
+		a := item.signature.A.Mul(bbs.FrToRepr(r))
+		b := item.signature.A.Mul(bbs.FrToRepr(item.signature.E.Mul(r)))
+		b.Sub(item.b.Mul(bbs.FrToRepr(r)))
+
+		if sumA == nil {
+			sumA, sumB = a, b
+		} else {
+			sumA.Add(a)
+			sumB.Add(b)
+		}
+	}
+
+	// the pairing engine normalizes its inputs in place -> use copies of the shared points
+	pairing := v.curve.Pairing2(v.publicKey.PointG2.Copy(), sumA, v.curve.GenG2.Copy(), sumB)
+	if !v.curve.FExp(pairing).IsUnity() {
+		return errors.New("invalid BLS12-381 signature")
+	}
+
+	return nil
+}
+
+// getGenerators Return the generators for a given number of messages, computing them only once.
+func (v *batchSignatureVerifier) getGenerators(messagesCount int) (*bbs.PublicKeyWithGenerators, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if generators, ok := v.generators[messagesCount]; ok {
+		return generators, nil
+	}
+
+	generators, err := v.publicKey.ToPublicKeyWithGenerators(messagesCount)
+	if err != nil {
+		return nil, fmt.Errorf("build generators from public key: %w", err)
+	}
+	v.generators[messagesCount] = generators
+
+	return generators, nil
+}
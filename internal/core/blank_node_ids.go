@@ -0,0 +1,91 @@
+package core
+
+import (
+	"sort"
+	"strings"
+)
+
+// The blank nodes of a credential are labelled by the canonicalization, and their labels depend on the whole graph:
+// once claims are hidden, the blank nodes of the framed credential could be labelled differently.
+// The credential is therefore framed with its blank nodes replaced by IRIs holding their canonical labels,
+// e.g. '_:c14n0' is framed as 'urn:bnid:_:c14n0', and the verifier restores the blank nodes before checking the proof.
+
+// blankNodeIDPrefix Prefix of the IRIs standing for the blank nodes of a credential.
+const blankNodeIDPrefix = "urn:bnid:"
+
+// skolemizeBlankNodes Replace the blank nodes of normalized statements with IRIs holding their canonical labels.
+//
+//	statements []string The normalized statements.
+//
+// returns:
+//
+//	skolemized []string
+func skolemizeBlankNodes(statements []string) []string {
+	skolemized := make([]string, len(statements))
+	for i, statement := range statements {
+		skolemized[i] = mapStatementTerms(statement, func(term string) string {
+			if strings.HasPrefix(term, "_:") {
+				return "<" + blankNodeIDPrefix + term + ">"
+			}
+			return term
+		})
+	}
+
+	return skolemized
+}
+
+// restoreBlankNodes Replace the IRIs standing for blank nodes in normalized statements with the blank nodes,
+// the statements being sorted again as the statements of the original credential.
+//
+//	statements []string The normalized statements of a framed credential.
+//
+// returns:
+//
+//	restored []string
+func restoreBlankNodes(statements []string) []string {
+	restored := make([]string, len(statements))
+	for i, statement := range statements {
+		restored[i] = mapStatementTerms(statement, func(term string) string {
+			if strings.HasPrefix(term, "<"+blankNodeIDPrefix+"_:") && strings.HasSuffix(term, ">") {
+				return term[len(blankNodeIDPrefix)+1 : len(term)-1]
+			}
+			return term
+		})
+	}
+	sort.Strings(restored)
+
+	return restored
+}
+
+// mapStatementTerms Map the terms of a N-Quads statement, the literals being kept whole.
+func mapStatementTerms(statement string, mapping func(term string) string) string {
+	var mapped strings.Builder
+	for rest := statement; rest != ""; {
+		end := termEnd(rest)
+		mapped.WriteString(mapping(rest[:end]))
+		if end < len(rest) {
+			mapped.WriteByte(rest[end])
+			end++
+		}
+		rest = rest[end:]
+	}
+
+	return mapped.String()
+}
+
+// termEnd Index of the space ending the first term of a statement, the length of the statement for the last term.
+func termEnd(statement string) int {
+	i := 0
+	if strings.HasPrefix(statement, `"`) {
+		for i = 1; i < len(statement) && statement[i] != '"'; i++ {
+			if statement[i] == '\\' {
+				i++
+			}
+		}
+	}
+	if end := strings.IndexByte(statement[min(i, len(statement)):], ' '); end >= 0 {
+		return i + end
+	}
+
+	return len(statement)
+}
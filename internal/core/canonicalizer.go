@@ -0,0 +1,433 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/piprate/json-gold/ld"
+)
+
+// canonicalizationCheckInterval is the number of canonicalization steps between two checks of the context.
+const canonicalizationCheckInterval = 64
+
+// A canonicalizer implements the URDNA2015 RDF dataset canonicalization algorithm
+// (https://www.w3.org/TR/rdf-canon/).
+// Unlike the implementation of json-gold, the context and the work budget are checked while hashing the blank nodes,
+// so that the canonicalization of adversarial inputs can be interrupted.
+type canonicalizer struct {
+	ctx             context.Context
+	limits          *model.ResourceLimits
+	steps           int
+	blankNodes      map[string]*blankNodeInfo
+	canonicalIssuer *identifierIssuer
+}
+
+// A blankNodeInfo contains the quads referencing a blank node and its first degree hash, once computed.
+type blankNodeInfo struct {
+	quads []*ld.Quad
+	hash  string
+}
+
+// canonicalize Canonicalize an RDF dataset with URDNA2015.
+//
+//	ctx context.Context If the context is done, the canonicalization is interrupted.
+//	dataset *ld.RDFDataset The dataset to canonicalize. It is not modified.
+//	limits *model.ResourceLimits The limits on the number of N-Quads, blank nodes and canonicalization steps.
+//
+// returns:
+//
+//	statements []string The sorted canonical N-Quads, without line terminator.
+//	err error
+func canonicalize(ctx context.Context, dataset *ld.RDFDataset, limits *model.ResourceLimits) ([]string, error) {
+	c := &canonicalizer{
+		ctx:             ctx,
+		limits:          limits,
+		blankNodes:      make(map[string]*blankNodeInfo),
+		canonicalIssuer: newIdentifierIssuer("_:c14n"),
+	}
+
+	return c.canonicalize(dataset)
+}
+
+func (c *canonicalizer) canonicalize(dataset *ld.RDFDataset) ([]string, error) {
+	if err := c.ctx.Err(); err != nil {
+		return nil, fmt.Errorf("canonicalization interrupted: %w", err)
+	}
+
+	// 1. Collect the quads and, for every blank node, the quads referencing it
+	quads := make([]*ld.Quad, 0)
+	for graphName, triples := range dataset.Graphs {
+		for _, triple := range triples {
+			quad := *triple
+			if graphName != "@default" {
+				if strings.HasPrefix(graphName, "_:") {
+					quad.Graph = ld.NewBlankNode(graphName)
+				} else {
+					quad.Graph = ld.NewIRI(graphName)
+				}
+			}
+			quads = append(quads, &quad)
+
+			for _, node := range []ld.Node{quad.Subject, quad.Object, quad.Graph} {
+				if node != nil && ld.IsBlankNode(node) {
+					info, ok := c.blankNodes[node.GetValue()]
+					if !ok {
+						info = &blankNodeInfo{}
+						c.blankNodes[node.GetValue()] = info
+					}
+					info.quads = append(info.quads, &quad)
+				}
+			}
+		}
+	}
+
+	if c.limits.MaxNQuads > 0 && len(quads) > c.limits.MaxNQuads {
+		return nil, fmt.Errorf("%w: %d N-Quads, limit %d", model.ErrTooManyNQuads, len(quads), c.limits.MaxNQuads)
+	}
+	if c.limits.MaxBlankNodes > 0 && len(c.blankNodes) > c.limits.MaxBlankNodes {
+		return nil, fmt.Errorf("%w: %d blank nodes, limit %d", model.ErrTooManyBlankNodes, len(c.blankNodes), c.limits.MaxBlankNodes)
+	}
+
+	// 2. Issue canonical identifiers for the blank nodes with a unique first degree hash
+	nonNormalized := make([]string, 0, len(c.blankNodes))
+	for id := range c.blankNodes {
+		nonNormalized = append(nonNormalized, id)
+	}
+	sort.Strings(nonNormalized)
+
+	var hashToBlankNodes map[string][]string
+	simple := true
+	for simple {
+		simple = false
+		hashToBlankNodes = make(map[string][]string)
+
+		for _, id := range nonNormalized {
+			if err := c.checkpoint(); err != nil {
+				return nil, err
+			}
+			hash := c.hashFirstDegreeQuads(id)
+			hashToBlankNodes[hash] = append(hashToBlankNodes[hash], id)
+		}
+
+		for _, hash := range sortedKeys(hashToBlankNodes) {
+			ids := hashToBlankNodes[hash]
+			if len(ids) > 1 {
+				continue
+			}
+
+			c.canonicalIssuer.getID(ids[0])
+			nonNormalized = removeString(nonNormalized, ids[0])
+			delete(hashToBlankNodes, hash)
+			simple = true
+		}
+	}
+
+	// 3. Issue canonical identifiers for the remaining blank nodes using the N-degree hashes
+	for _, hash := range sortedKeys(hashToBlankNodes) {
+		hashPaths := make(map[string][]*identifierIssuer)
+
+		for _, id := range hashToBlankNodes[hash] {
+			if c.canonicalIssuer.hasID(id) {
+				continue
+			}
+
+			issuer := newIdentifierIssuer("_:b")
+			issuer.getID(id)
+
+			pathHash, pathIssuer, err := c.hashNDegreeQuads(id, issuer)
+			if err != nil {
+				return nil, err
+			}
+			hashPaths[pathHash] = append(hashPaths[pathHash], pathIssuer)
+		}
+
+		for _, pathHash := range sortedKeys(hashPaths) {
+			for _, issuer := range hashPaths[pathHash] {
+				for _, existing := range issuer.order {
+					c.canonicalIssuer.getID(existing)
+				}
+			}
+		}
+	}
+
+	// 4. Serialize the quads replacing the blank nodes with their canonical identifiers
+	lines := make([]string, len(quads))
+	for i, quad := range quads {
+		lines[i] = serializeNQuad(quad, func(node ld.Node) string {
+			return c.canonicalIssuer.getID(node.GetValue())
+		})
+	}
+	sort.Strings(lines)
+
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\n")
+	}
+
+	return lines, nil
+}
+
+// hashFirstDegreeQuads Hash the quads referencing a blank node, replacing the blank nodes with '_:a' or '_:z'.
+func (c *canonicalizer) hashFirstDegreeQuads(id string) string {
+	info := c.blankNodes[id]
+	if info.hash != "" {
+		return info.hash
+	}
+
+	nquads := make([]string, len(info.quads))
+	for i, quad := range info.quads {
+		nquads[i] = serializeNQuad(quad, func(node ld.Node) string {
+			if node.GetValue() == id {
+				return "_:a"
+			}
+			return "_:z"
+		})
+	}
+	sort.Strings(nquads)
+
+	info.hash = hashStrings(nquads...)
+
+	return info.hash
+}
+
+// hashRelatedBlankNode Hash a blank node related to the one being hashed by the N-degree algorithm.
+func (c *canonicalizer) hashRelatedBlankNode(related string, quad *ld.Quad, issuer *identifierIssuer, position string) string {
+	var id string
+	if c.canonicalIssuer.hasID(related) {
+		id = c.canonicalIssuer.getID(related)
+	} else if issuer.hasID(related) {
+		id = issuer.getID(related)
+	} else {
+		id = c.hashFirstDegreeQuads(related)
+	}
+
+	if position == "g" {
+		return hashStrings(position, id)
+	}
+
+	return hashStrings(position, "<", quad.Predicate.GetValue(), ">", id)
+}
+
+// hashNDegreeQuads Hash a blank node taking into account all the blank nodes it is related to.
+// The number of permutations explored can be exponential in the number of related blank nodes,
+// therefore the context is checked for every permutation.
+func (c *canonicalizer) hashNDegreeQuads(id string, issuer *identifierIssuer) (string, *identifierIssuer, error) {
+	hashToRelated := make(map[string][]string)
+	for _, quad := range c.blankNodes[id].quads {
+		for i, node := range []ld.Node{quad.Subject, quad.Object, quad.Graph} {
+			if node != nil && ld.IsBlankNode(node) && node.GetValue() != id {
+				related := node.GetValue()
+				hash := c.hashRelatedBlankNode(related, quad, issuer, []string{"s", "o", "g"}[i])
+				hashToRelated[hash] = append(hashToRelated[hash], related)
+			}
+		}
+	}
+
+	md := sha256.New()
+	for _, hash := range sortedKeys(hashToRelated) {
+		md.Write([]byte(hash))
+
+		chosenPath := ""
+		var chosenIssuer *identifierIssuer
+
+		permutator := ld.NewPermutator(hashToRelated[hash])
+	permutations:
+		for permutator.HasNext() {
+			if err := c.checkpoint(); err != nil {
+				return "", nil, err
+			}
+
+			permutation := permutator.Next()
+			issuerCopy := issuer.clone()
+			path := ""
+			recursionList := make([]string, 0)
+
+			for _, related := range permutation {
+				if c.canonicalIssuer.hasID(related) {
+					path += c.canonicalIssuer.getID(related)
+				} else {
+					if !issuerCopy.hasID(related) {
+						recursionList = append(recursionList, related)
+					}
+					path += issuerCopy.getID(related)
+				}
+
+				if chosenPath != "" && len(path) >= len(chosenPath) && path > chosenPath {
+					continue permutations
+				}
+			}
+
+			for _, related := range recursionList {
+				resultHash, resultIssuer, err := c.hashNDegreeQuads(related, issuerCopy)
+				if err != nil {
+					return "", nil, err
+				}
+
+				path += issuerCopy.getID(related)
+				path += "<" + resultHash + ">"
+				issuerCopy = resultIssuer
+
+				if chosenPath != "" && len(path) >= len(chosenPath) && path > chosenPath {
+					continue permutations
+				}
+			}
+
+			if chosenPath == "" || path < chosenPath {
+				chosenPath = path
+				chosenIssuer = issuerCopy
+			}
+		}
+
+		md.Write([]byte(chosenPath))
+		issuer = chosenIssuer
+	}
+
+	return hex.EncodeToString(md.Sum(nil)), issuer, nil
+}
+
+// checkpoint Count a canonicalization step, check the work budget and periodically check whether the context is done.
+func (c *canonicalizer) checkpoint() error {
+	c.steps++
+	if c.limits.MaxCanonicalizationSteps > 0 && c.steps > c.limits.MaxCanonicalizationSteps {
+		return fmt.Errorf("%w: limit %d steps", model.ErrCanonicalizationBudgetExceeded, c.limits.MaxCanonicalizationSteps)
+	}
+
+	if c.steps%canonicalizationCheckInterval != 0 {
+		return nil
+	}
+
+	if err := c.ctx.Err(); err != nil {
+		return fmt.Errorf("canonicalization interrupted: %w", err)
+	}
+
+	return nil
+}
+
+// An identifierIssuer issues blank node identifiers, keeping track of the order in which they have been issued.
+type identifierIssuer struct {
+	prefix   string
+	counter  int
+	existing map[string]string
+	order    []string
+}
+
+func newIdentifierIssuer(prefix string) *identifierIssuer {
+	return &identifierIssuer{
+		prefix:   prefix,
+		existing: make(map[string]string),
+	}
+}
+
+func (ii *identifierIssuer) getID(oldID string) string {
+	if id, ok := ii.existing[oldID]; ok {
+		return id
+	}
+
+	id := fmt.Sprintf("%s%d", ii.prefix, ii.counter)
+	ii.counter++
+	ii.existing[oldID] = id
+	ii.order = append(ii.order, oldID)
+
+	return id
+}
+
+func (ii *identifierIssuer) hasID(oldID string) bool {
+	_, ok := ii.existing[oldID]
+
+	return ok
+}
+
+func (ii *identifierIssuer) clone() *identifierIssuer {
+	existing := make(map[string]string, len(ii.existing))
+	for key, value := range ii.existing {
+		existing[key] = value
+	}
+
+	return &identifierIssuer{
+		prefix:   ii.prefix,
+		counter:  ii.counter,
+		existing: existing,
+		order:    append([]string(nil), ii.order...),
+	}
+}
+
+// serializeNQuad Serialize a quad in N-Quads format, labelling the blank nodes with the provided function.
+func serializeNQuad(quad *ld.Quad, blankNodeLabel func(node ld.Node) string) string {
+	var sb strings.Builder
+
+	writeNode := func(node ld.Node) {
+		switch {
+		case ld.IsIRI(node):
+			sb.WriteString("<" + escapeNQuad(node.GetValue()) + ">")
+		case ld.IsBlankNode(node):
+			sb.WriteString(blankNodeLabel(node))
+		default:
+			literal := node.(*ld.Literal)
+			sb.WriteString("\"" + escapeNQuad(literal.GetValue()) + "\"")
+			if literal.Datatype == ld.RDFLangString {
+				sb.WriteString("@" + literal.Language)
+			} else if literal.Datatype != ld.XSDString {
+				sb.WriteString("^^<" + escapeNQuad(literal.Datatype) + ">")
+			}
+		}
+	}
+
+	writeNode(quad.Subject)
+	sb.WriteString(" ")
+	writeNode(quad.Predicate)
+	sb.WriteString(" ")
+	writeNode(quad.Object)
+	if quad.Graph != nil {
+		sb.WriteString(" ")
+		writeNode(quad.Graph)
+	}
+	sb.WriteString(" .\n")
+
+	return sb.String()
+}
+
+var nquadEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"\"", "\\\"",
+	"\n", "\\n",
+	"\r", "\\r",
+	"\t", "\\t",
+)
+
+func escapeNQuad(value string) string {
+	return nquadEscaper.Replace(value)
+}
+
+// hashStrings Return the hex encoded SHA-256 hash of the concatenation of the values.
+func hashStrings(values ...string) string {
+	md := sha256.New()
+	for _, value := range values {
+		md.Write([]byte(value))
+	}
+
+	return hex.EncodeToString(md.Sum(nil))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func removeString(values []string, value string) []string {
+	for i, v := range values {
+		if v == value {
+			return append(values[:i], values[i+1:]...)
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,139 @@
+package core_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/piprate/json-gold/ld"
+	"github.com/stretchr/testify/suite"
+)
+
+// The canonicalizer of the library is compared with the URDNA2015 implementation of json-gold.
+type CanonicalizerTestSuite struct {
+	suite.Suite
+}
+
+func TestCanonicalizerTestSuite(t *testing.T) {
+	suite.Run(t, new(CanonicalizerTestSuite))
+}
+
+func (s *CanonicalizerTestSuite) TestTestdataCredentials() {
+	normalizer := core.NewNormalizer(offlineOptions(s.T()))
+	for _, file := range []string{
+		"unsignedCredential.json",
+		"signedCredential.json",
+		"multipleSignedCredential.json",
+		"unsignedPermanentResidentCard.json",
+		"unsignedProof.json",
+		"derivedProof.json",
+	} {
+		documentBytes, err := os.ReadFile("testdata/" + file)
+		s.Require().NoError(err)
+		var document map[string]interface{}
+		s.Require().NoError(json.Unmarshal(documentBytes, &document))
+
+		actual, err := normalizer.NormalizeDocument(document)
+		s.Require().NoError(err, file)
+
+		options := referenceOptions()
+		options.DocumentLoader = normalizer.DocumentLoader()
+		expected, err := ld.NewJsonLdProcessor().Normalize(document, options)
+		s.Require().NoError(err, file)
+		s.Equal(splitNQuads(expected.(string)), actual, file)
+	}
+}
+
+func (s *CanonicalizerTestSuite) TestRandomBlankNodeGraphs() {
+	for seed := int64(0); seed < 300; seed++ {
+		nquads := randomBlankNodeGraph(rand.New(rand.NewSource(seed)))
+		s.compareNQuads(nquads, fmt.Sprintf("seed %d:\n%s", seed, nquads))
+	}
+}
+
+func (s *CanonicalizerTestSuite) TestSymmetricBlankNodeGraphs() {
+	// rings of indistinguishable blank nodes, alone or side by side, require the N-degree hashes
+	for size := 1; size <= 6; size++ {
+		s.compareNQuads(blankNodeRing("a", size), fmt.Sprintf("ring of %d", size))
+		s.compareNQuads(blankNodeRing("a", size)+blankNodeRing("b", size), fmt.Sprintf("two rings of %d", size))
+		s.compareNQuads(blankNodeRing("a", size)+blankNodeRing("b", size+1), fmt.Sprintf("rings of %d and %d", size, size+1))
+	}
+}
+
+// compareNQuads Check that both implementations canonicalize N-Quads to the same statements.
+func (s *CanonicalizerTestSuite) compareNQuads(nquads, description string) {
+	actual, err := core.CanonicalizeNQuads(nquads)
+	s.Require().NoError(err, description)
+
+	options := referenceOptions()
+	options.InputFormat = "application/n-quads"
+	expected, err := ld.NewJsonLdProcessor().Normalize(nquads, options)
+	s.Require().NoError(err, description)
+	s.Equal(splitNQuads(expected.(string)), actual, description)
+}
+
+// referenceOptions Return the options of the URDNA2015 canonicalization of json-gold.
+func referenceOptions() *ld.JsonLdOptions {
+	options := ld.NewJsonLdOptions("")
+	options.Format = "application/n-quads"
+	options.Algorithm = ld.AlgorithmURDNA2015
+
+	return options
+}
+
+// splitNQuads Split serialized N-Quads into statements without line terminator.
+func splitNQuads(nquads string) []string {
+	statements := strings.Split(strings.TrimSuffix(nquads, "\n"), "\n")
+	if len(statements) == 1 && statements[0] == "" {
+		return []string{}
+	}
+
+	return statements
+}
+
+// randomBlankNodeGraph Generate a dataset whose blank nodes are linked at random, with few distinct predicates and
+// objects so that many blank nodes share their first degree hash. The blank nodes have arbitrary labels.
+func randomBlankNodeGraph(r *rand.Rand) string {
+	blankNodes := make([]string, 1+r.Intn(8))
+	for i, label := range r.Perm(len(blankNodes)) {
+		blankNodes[i] = fmt.Sprintf("_:b%d", label*7+r.Intn(7))
+	}
+	predicates := []string{"<urn:p:0>", "<urn:p:1>"}
+	objects := []string{"<urn:o:0>", `"literal"`, `"2024-05-01"^^<http://www.w3.org/2001/XMLSchema#date>`, `"tag"@en`}
+	graphs := []string{"", "<urn:g:0>", "_:g"}
+
+	quads := make(map[string]bool)
+	for count := r.Intn(3 * len(blankNodes)); len(quads) < count; {
+		subject := blankNodes[r.Intn(len(blankNodes))]
+		object := blankNodes[r.Intn(len(blankNodes))]
+		if r.Intn(3) == 0 {
+			object = objects[r.Intn(len(objects))]
+		}
+		quad := subject + " " + predicates[r.Intn(len(predicates))] + " " + object
+		if graph := graphs[r.Intn(len(graphs))]; graph != "" && r.Intn(4) == 0 {
+			quad += " " + graph
+		}
+		quads[quad+" .\n"] = true
+	}
+
+	var nquads strings.Builder
+	for quad := range quads {
+		nquads.WriteString(quad)
+	}
+
+	return nquads.String()
+}
+
+// blankNodeRing Generate a ring of blank nodes linked by the same predicate.
+func blankNodeRing(prefix string, size int) string {
+	var nquads strings.Builder
+	for i := 0; i < size; i++ {
+		fmt.Fprintf(&nquads, "_:%s%d <urn:p:next> _:%s%d .\n", prefix, i, prefix, (i+1)%size)
+	}
+
+	return nquads.String()
+}
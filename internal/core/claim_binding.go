@@ -0,0 +1,288 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+)
+
+// The claims of a subject are modeled as a Go struct: the JSON names of its fields are the JSON-LD terms,
+// and their `jsonld` tags hold the IRIs of the terms, optionally followed by the type of their values:
+//
+//	type Resident struct {
+//		ID        string    `json:"id"`
+//		GivenName string    `json:"givenName" jsonld:"http://schema.org/givenName"`
+//		BirthDate time.Time `json:"birthDate" jsonld:"http://schema.org/birthDate,type=http://www.w3.org/2001/XMLSchema#dateTime"`
+//	}
+//
+// The values of time.Time fields are typed as xsd:dateTime unless another type is provided.
+// When extracting a disclosed subject, the pointer, slice and map fields and the fields tagged with omitempty
+// are optional, the other ones must be disclosed.
+
+// jsonldTag is the name of the struct tag holding the IRI of a term.
+const jsonldTag = "jsonld"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Issue Sign a credential whose subject is a Go struct.
+//
+//	ctx context.Context
+//	suite *SignatureSuite2020
+//	subject T a struct, or a pointer to a struct
+//	options *model.IssueOptions nullable
+//
+// returns:
+//
+//	signedCredential model.JsonLdCredential
+//	err error
+func Issue[T any](ctx context.Context, suite *SignatureSuite2020, subject T, options *model.IssueOptions) (model.JsonLdCredential, error) {
+	credential, err := newSubjectCredential(subject, options, suite.clock())
+	if err != nil {
+		return nil, err
+	}
+	unsigned, err := credential.ToMap()
+	if err != nil {
+		return nil, err
+	}
+
+	signed, _, err := suite.SignContext(ctx, unsigned)
+
+	return signed, err
+}
+
+// SubjectContext Generate the JSON-LD context defining the terms of a Go struct from their `jsonld` tags.
+//
+// returns:
+//
+//	context map[string]interface{} the term definitions, to be embedded or published as {"@context": context}
+//	err error wrapping model.ErrInvalidClaimBinding
+func SubjectContext[T any]() (map[string]interface{}, error) {
+	terms := make(map[string]interface{})
+	if err := collectSubjectTerms(reflect.TypeOf((*T)(nil)).Elem(), terms, make(map[reflect.Type]bool)); err != nil {
+		return nil, err
+	}
+
+	return terms, nil
+}
+
+// ExtractSubject Decode the only subject of a credential, e.g. a verified derived credential, into a Go struct.
+//
+//	credential model.JsonLdCredential
+//
+// returns:
+//
+//	subject T
+//	err error wrapping model.ErrClaimNotDisclosed if a required claim is not disclosed
+func ExtractSubject[T any](credential model.JsonLdCredential) (T, error) {
+	var subject T
+	subjects, err := ExtractSubjects[T](credential)
+	if err != nil {
+		return subject, err
+	}
+	if len(subjects) != 1 {
+		return subject, fmt.Errorf("credential has %d subjects, 1 expected", len(subjects))
+	}
+
+	return subjects[0], nil
+}
+
+// ExtractSubjects Decode the subjects of a credential into Go structs.
+//
+//	credential model.JsonLdCredential
+//
+// returns:
+//
+//	subjects []T
+//	err error wrapping model.ErrClaimNotDisclosed if a required claim is not disclosed
+func ExtractSubjects[T any](credential model.JsonLdCredential) ([]T, error) {
+	var values []interface{}
+	switch v := credential[c.CredentialFieldCredentialSubject].(type) {
+	case map[string]interface{}:
+		values = []interface{}{v}
+	case []interface{}:
+		values = v
+	default:
+		return nil, fmt.Errorf("credential doesn't contain '%s'", c.CredentialFieldCredentialSubject)
+	}
+
+	structType := reflect.TypeOf((*T)(nil)).Elem()
+	subjects := make([]T, len(values))
+	for i, value := range values {
+		claims, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("'%s' %d is not an object", c.CredentialFieldCredentialSubject, i)
+		}
+		if err := decodeSubject(claims, structType, &subjects[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return subjects, nil
+}
+
+// newSubjectCredential Create the unsigned credential about a subject from the template of the options,
+// issued now if there is no template.
+func newSubjectCredential(subject interface{}, options *model.IssueOptions, now time.Time) (*model.VerifiableCredential, error) {
+	if reflect.TypeOf(subject) == nil {
+		return nil, fmt.Errorf("%w: nil subject", model.ErrInvalidClaimBinding)
+	}
+	data, err := json.Marshal(subject)
+	if err != nil {
+		return nil, err
+	}
+	credentialSubject := &model.CredentialSubject{}
+	if err := json.Unmarshal(data, credentialSubject); err != nil {
+		return nil, fmt.Errorf("%w: subject is not a struct: %s", model.ErrInvalidClaimBinding, err.Error())
+	}
+
+	credential := &model.VerifiableCredential{
+		Context:      []interface{}{c.ContextCredentialV1, c.ContextSecurityBbsV1},
+		Type:         []string{"VerifiableCredential"},
+		IssuanceDate: now.UTC().Format(c.ProofTimestampFormat),
+	}
+	if options != nil && options.Template != nil {
+		template := *options.Template
+		template.Context = append([]interface{}{}, template.Context...)
+		credential = &template
+	}
+	credential.CredentialSubject = []*model.CredentialSubject{credentialSubject}
+	credential.Proof = nil
+
+	if options != nil && options.GenerateContext {
+		terms := make(map[string]interface{})
+		if err := collectSubjectTerms(reflect.TypeOf(subject), terms, make(map[reflect.Type]bool)); err != nil {
+			return nil, err
+		}
+		credential.Context = append(credential.Context, terms)
+	}
+
+	return credential, nil
+}
+
+// collectSubjectTerms Collect the term definitions of the fields of a struct type, and of the nested structs.
+func collectSubjectTerms(t reflect.Type, terms map[string]interface{}, visited map[reflect.Type]bool) error {
+	t = elementType(t)
+	if t.Kind() != reflect.Struct || t == timeType || visited[t] {
+		return nil
+	}
+	visited[t] = true
+
+	for _, field := range reflect.VisibleFields(t) {
+		name, _, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+		if field.Anonymous && elementType(field.Type).Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			continue // the fields of an embedded struct are visible fields
+		}
+		if err := collectSubjectTerms(field.Type, terms, visited); err != nil {
+			return err
+		}
+
+		tag, ok := field.Tag.Lookup(jsonldTag)
+		if !ok || name == c.CredentialFieldId || name == c.CredentialFieldType {
+			continue
+		}
+		definition, err := termDefinition(field, tag)
+		if err != nil {
+			return err
+		}
+		if existing, ok := terms[name]; ok && !reflect.DeepEqual(existing, definition) {
+			return fmt.Errorf("%w: term '%s' defined twice", model.ErrInvalidClaimBinding, name)
+		}
+		terms[name] = definition
+	}
+
+	return nil
+}
+
+// termDefinition Build the definition of a term from the `jsonld` tag of a field: its IRI, or an object with its type.
+func termDefinition(field reflect.StructField, tag string) (interface{}, error) {
+	parts := strings.Split(tag, ",")
+	iri := parts[0]
+	if !strings.Contains(iri, ":") {
+		return nil, fmt.Errorf("%w: field %s: '%s' is not an IRI", model.ErrInvalidClaimBinding, field.Name, iri)
+	}
+
+	var valueType string
+	if elementType(field.Type) == timeType {
+		valueType = c.XsdDateTime
+	}
+	for _, option := range parts[1:] {
+		key, value, found := strings.Cut(option, "=")
+		if !found || key != "type" || value == "" {
+			return nil, fmt.Errorf("%w: field %s: unknown option '%s'", model.ErrInvalidClaimBinding, field.Name, option)
+		}
+		valueType = value
+	}
+
+	if valueType == "" {
+		return iri, nil
+	}
+
+	return map[string]interface{}{"@id": iri, "@type": valueType}, nil
+}
+
+// decodeSubject Decode the claims of a subject into a struct, checking that the required claims are disclosed.
+// A single value is accepted for a slice field, since the framing compacts the arrays of one value.
+func decodeSubject(claims map[string]interface{}, structType reflect.Type, target interface{}) error {
+	claims = deepCopyMap(claims)
+	if t := elementType(structType); t.Kind() == reflect.Struct {
+		for _, field := range reflect.VisibleFields(t) {
+			name, omitEmpty, skip := jsonFieldName(field)
+			if skip || field.Anonymous && field.Tag.Get("json") == "" {
+				continue
+			}
+			value, disclosed := claims[name]
+			kind := field.Type.Kind()
+			if !disclosed {
+				if !omitEmpty && kind != reflect.Pointer && kind != reflect.Slice && kind != reflect.Map && kind != reflect.Interface {
+					return fmt.Errorf("%w: '%s'", model.ErrClaimNotDisclosed, name)
+				}
+				continue
+			}
+			if _, isArray := value.([]interface{}); kind == reflect.Slice && field.Type.Elem().Kind() != reflect.Uint8 && !isArray {
+				claims[name] = []interface{}{value}
+			}
+		}
+	}
+
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, target)
+}
+
+// jsonFieldName Retrieve the JSON name of a struct field, whether it is omitted when empty and whether it is skipped.
+func jsonFieldName(field reflect.StructField) (string, bool, bool) {
+	if !field.IsExported() {
+		return "", false, true
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+
+	return name, strings.Contains(","+options+",", ",omitempty,"), false
+}
+
+// elementType Retrieve the type of the values of a pointer, slice or array type.
+func elementType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+
+	return t
+}
@@ -0,0 +1,138 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type resident struct {
+	ID        string    `json:"id"`
+	GivenName *string   `json:"givenName,omitempty" jsonld:"http://schema.org/givenName"`
+	BirthDate time.Time `json:"birthDate" jsonld:"http://schema.org/birthDate"`
+	Nicknames []string  `json:"nicknames,omitempty" jsonld:"http://schema.org/alternateName"`
+	Address   *address  `json:"address,omitempty" jsonld:"http://schema.org/address"`
+}
+
+type address struct {
+	Country string `json:"country" jsonld:"http://schema.org/addressCountry"`
+}
+
+type ClaimBindingTestSuite struct {
+	suite.Suite
+	signatureSuite *core.SignatureSuite2020
+	proofSuite     *core.SignatureProofSuite2020
+	subject        resident
+}
+
+func TestClaimBindingTestSuite(t *testing.T) {
+	suite.Run(t, new(ClaimBindingTestSuite))
+}
+
+func (s *ClaimBindingTestSuite) SetupTest() {
+	options := offlineOptions(s.T())
+	publicKey := benchmarkPublicKey(s.T())
+	s.signatureSuite = core.NewSignatureSuite2020(publicKey, benchmarkPrivateKey(s.T()), options)
+	s.proofSuite = core.NewSignatureProofSuite2020(publicKey, options)
+
+	givenName := "Jace"
+	s.subject = resident{
+		ID:        "did:example:jace",
+		GivenName: &givenName,
+		BirthDate: time.Date(1990, 11, 22, 0, 0, 0, 0, time.UTC),
+		Nicknames: []string{"J"},
+		Address:   &address{Country: "Bahamas"},
+	}
+}
+
+func (s *ClaimBindingTestSuite) TestSubjectContext() {
+	terms, err := core.SubjectContext[resident]()
+	s.Require().NoError(err)
+	s.Equal(map[string]interface{}{
+		"givenName": "http://schema.org/givenName",
+		"birthDate": map[string]interface{}{"@id": "http://schema.org/birthDate", "@type": c.XsdDateTime},
+		"nicknames": "http://schema.org/alternateName",
+		"address":   "http://schema.org/address",
+		"country":   "http://schema.org/addressCountry",
+	}, terms)
+
+	type invalid struct {
+		Name string `json:"name" jsonld:"name"`
+	}
+	_, err = core.SubjectContext[invalid]()
+	s.ErrorIs(err, model.ErrInvalidClaimBinding)
+}
+
+func (s *ClaimBindingTestSuite) TestIssueAndExtract() {
+	ctx := context.Background()
+	// the default template has no id: hiding the address must not relabel the blank nodes of the credential
+	signed, err := core.Issue(ctx, s.signatureSuite, s.subject, &model.IssueOptions{GenerateContext: true})
+	s.Require().NoError(err)
+	s.NotContains(signed, "id")
+	result := s.signatureSuite.Verify(signed)
+	s.True(result.Success, result.Error)
+
+	// every claim is signed
+	issued, err := core.ExtractSubject[resident](signed)
+	s.Require().NoError(err)
+	s.Equal(s.subject, issued)
+
+	// the hidden claims are left empty
+	frame := model.JsonLdFrame{
+		"@context": signed["@context"],
+		"type":     "VerifiableCredential",
+		"credentialSubject": map[string]interface{}{
+			"@explicit": true,
+			"birthDate": map[string]interface{}{},
+			"nicknames": map[string]interface{}{},
+		},
+	}
+	derived, err := s.proofSuite.DeriveProof(signed, frame, []byte("verifier challenge"))
+	s.Require().NoError(err)
+	result = s.proofSuite.VerifyProof(derived)
+	s.True(result.Success, result.Error)
+	// the blank nodes are disclosed with their canonical labels, which do not identify the credential
+	s.Regexp(`^urn:bnid:_:c14n\d+$`, derived["id"])
+
+	disclosed, err := core.ExtractSubject[resident](derived)
+	s.Require().NoError(err)
+	s.Nil(disclosed.GivenName)
+	s.Nil(disclosed.Address)
+	s.Equal(s.subject.BirthDate, disclosed.BirthDate)
+	s.Equal([]string{"J"}, disclosed.Nicknames)
+
+	// the birth date is required
+	frame["credentialSubject"] = map[string]interface{}{"@explicit": true, "givenName": map[string]interface{}{}}
+	derived, err = s.proofSuite.DeriveProof(signed, frame, []byte("verifier challenge"))
+	s.Require().NoError(err)
+	_, err = core.ExtractSubject[resident](derived)
+	s.ErrorIs(err, model.ErrClaimNotDisclosed)
+}
+
+func (s *ClaimBindingTestSuite) TestTemplate() {
+	template, err := model.ParseVerifiableCredential([]byte(`{
+		"@context": ["https://www.w3.org/2018/credentials/v1", "https://w3id.org/security/bbs/v1"],
+		"type": ["VerifiableCredential"],
+		"issuer": "did:example:issuer",
+		"issuanceDate": "2024-01-01T00:00:00Z",
+		"expirationDate": "2034-01-01T00:00:00Z"
+	}`))
+	s.Require().NoError(err)
+
+	signed, err := core.Issue(context.Background(), s.signatureSuite, &s.subject, &model.IssueOptions{Template: template, GenerateContext: true})
+	s.Require().NoError(err)
+	s.Equal("2034-01-01T00:00:00Z", signed["expirationDate"])
+	s.Equal("did:example:issuer", signed["issuer"])
+	s.Len(template.Context, 2)
+
+	// without the generated context, the claims are not defined
+	signed, err = core.Issue(context.Background(), s.signatureSuite, s.subject, nil)
+	s.Require().NoError(err)
+	diagnostics := s.signatureSuite.Diagnose(context.Background(), signed, nil)
+	s.Contains(diagnostics.DroppedTerms, "credentialSubject.givenName")
+}
@@ -0,0 +1,202 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+
+	ml "github.com/IBM/mathlib"
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/hyperledger/aries-bbs-go/bbs"
+)
+
+// A combinedProof is a proof of knowledge of a signature generated together with proofs on its hidden messages:
+// range proofs of predicates on encoded claims, and the proof of the pseudonym of the holder.
+type combinedProof struct {
+	proofValue []byte
+	predicates []model.PredicateProof
+	pseudonym  *model.Pseudonym // nil if no pseudonym is requested
+}
+
+// deriveCombinedProof Generate the proof of knowledge of the signature of a credential together with the range proofs
+// of predicates on its hidden encoded claims and the proof of the pseudonym of the holder, with a shared challenge.
+//
+//	derivation *derivation
+//	predicates []model.Predicate
+//	verifierID string empty if no pseudonym is requested, else the credential must be bound to the holder
+//	nonceBytes []byte
+//
+// returns:
+//
+//	proof *combinedProof
+//	err error wrapping model.ErrPredicateClaimNotEncoded or model.ErrPredicateNotSatisfied
+func (s *SignatureProofSuite2020) deriveCombinedProof(
+	derivation *derivation,
+	predicates []model.Predicate,
+	verifierID string,
+	nonceBytes []byte,
+) (*combinedProof, error) {
+	curve := newBBSCurve()
+	lib := bbs.NewBBSLib(curve)
+
+	// 1. Disclose the descriptors of the encodings, and share the blinding factors of the hidden encodings
+	revealed := slices.Clone(derivation.revealed)
+	blindings := make(map[int]*ml.Zr)
+	predicateProofs := make([]model.PredicateProof, len(predicates))
+	differences := make([]*big.Int, len(predicates))
+	signs := make([]int, len(predicates))
+	for i, predicate := range predicates {
+		index := slices.IndexFunc(derivation.encoded, func(encoded *encodedClaim) bool { return encoded.claim == predicate.Claim })
+		if index < 0 {
+			return nil, fmt.Errorf("%w: '%s'", model.ErrPredicateClaimNotEncoded, predicate.Claim)
+		}
+		encoded := derivation.encoded[index]
+
+		upper, bound, err := predicateBound(encoded.datatype, predicate)
+		if err != nil {
+			return nil, err
+		}
+		sign, offset := rangeOffset(upper, bound)
+		difference := new(big.Int).Mul(big.NewInt(int64(sign)), encoded.value)
+		difference.Add(difference, offset)
+		if difference.Sign() < 0 || difference.BitLen() > c.PredicateRangeBits {
+			return nil, fmt.Errorf("%w: %s %s %s", model.ErrPredicateNotSatisfied, predicate.Claim, predicate.Operator, predicate.Value)
+		}
+		differences[i], signs[i] = difference, sign
+
+		message := len(derivation.statements) + 2*index + 1
+		if _, ok := blindings[message]; !ok {
+			if blindings[message], err = randomZr(curve, s.rand); err != nil {
+				return nil, err
+			}
+			revealed = append(revealed, message-1)
+		}
+		predicateProofs[i] = model.PredicateProof{Predicate: predicate, Datatype: encoded.datatype, Message: message}
+	}
+	slices.Sort(revealed)
+
+	// 1.1. Share the blinding factor of the holder secret, the last message
+	secretIndex := len(derivation.messages) - 1
+	if verifierID != "" {
+		blinding, err := randomZr(curve, s.rand)
+		if err != nil {
+			return nil, err
+		}
+		blindings[secretIndex] = blinding
+	}
+
+	// 2. Commit to the proof of knowledge of the signature, to the range proofs and to the pseudonym
+	generators, err := publicKeyGenerators(lib, s.publicKey, len(derivation.messages))
+	if err != nil {
+		return nil, err
+	}
+	committed, err := newProofOfKnowledge(curve, lib, s.rand, generators, derivation.signature, derivation.messages, revealed, blindings)
+	if err != nil {
+		return nil, err
+	}
+
+	challengeBytes := committed.ToBytes()
+	provers := make([]*rangeProver, len(predicates))
+	for i := range predicateProofs {
+		provers[i], err = newRangeProver(curve, s.rand, differences[i], signs[i], blindings[predicateProofs[i].Message])
+		if err != nil {
+			return nil, err
+		}
+		challengeBytes = append(challengeBytes, predicateChallengeBytes(&predicateProofs[i])...)
+		challengeBytes = append(challengeBytes, provers[i].challengeBytes()...)
+	}
+	var pseudonym *model.Pseudonym
+	if verifierID != "" {
+		pseudonym = newPseudonym(curve, verifierID, derivation.messages[secretIndex].FR, blindings[secretIndex])
+		challengeBytes = append(challengeBytes, pseudonymChallengeBytes(pseudonym)...)
+	}
+
+	// 3. Generate the proofs with the shared challenge
+	challenge := proofOfKnowledgeChallenge(curve, challengeBytes, nonceBytes)
+	proofValue, err := proofOfKnowledgeValue(committed.GenerateProof(challenge), len(derivation.messages), revealed)
+	if err != nil {
+		return nil, err
+	}
+	for i, prover := range provers {
+		predicateProofs[i].Proof = prover.generateProof(challenge)
+	}
+
+	return &combinedProof{
+		proofValue: proofValue,
+		predicates: predicateProofs,
+		pseudonym:  pseudonym,
+	}, nil
+}
+
+// verifyCombinedProof Verify the proof of knowledge of a derived proof together with its range proofs and its pseudonym.
+//
+//	publicKey []byte
+//	data *verificationData
+//
+// returns:
+//
+//	err error
+func verifyCombinedProof(publicKey []byte, data *verificationData) error {
+	curve := newBBSCurve()
+	lib := bbs.NewBBSLib(curve)
+
+	parsed, err := parseProofOfKnowledge(curve, lib, publicKey, data)
+	if err != nil {
+		return err
+	}
+
+	// 1. The descriptor preceding every encoding is disclosed
+	challengeBytes := parsed.challengeBytes()
+	ranges := make([]*rangeProof, len(data.predicates))
+	for i := range data.predicates {
+		predicate := &data.predicates[i]
+		descriptor, disclosed := parsed.revealed[predicate.Message-1]
+		if !disclosed || !descriptor.FR.Equals(bbs.FrFromOKM(encodingDescriptor(predicate.Claim, predicate.Datatype), curve)) {
+			return fmt.Errorf("%w: '%s'", model.ErrPredicateClaimNotEncoded, predicate.Claim)
+		}
+
+		ranges[i], err = parseRangeProof(curve, predicate.Proof)
+		if err != nil {
+			return err
+		}
+		challengeBytes = append(challengeBytes, predicateChallengeBytes(predicate)...)
+		challengeBytes = append(challengeBytes, ranges[i].challengeBytes()...)
+	}
+	if data.pseudonym != nil {
+		challengeBytes = append(challengeBytes, pseudonymChallengeBytes(data.pseudonym)...)
+	}
+
+	// 2. Verify the proofs with the shared challenge
+	challenge := proofOfKnowledgeChallenge(curve, challengeBytes, data.nonce)
+	if err := parsed.verify(challenge); err != nil {
+		return err
+	}
+	for i, predicate := range data.predicates {
+		response, err := hiddenMessageResponse(parsed.signatureProof, parsed.revealed, predicate.Message)
+		if err != nil {
+			return fmt.Errorf("predicate on '%s': %w", predicate.Claim, err)
+		}
+		upper, bound, err := predicateBound(predicate.Datatype, predicate.Predicate)
+		if err != nil {
+			return err
+		}
+		sign, offset := rangeOffset(upper, bound)
+		if err := ranges[i].verify(curve, challenge, response, sign, offset); err != nil {
+			return fmt.Errorf("predicate on '%s': %w", predicate.Claim, err)
+		}
+	}
+
+	// 3. The pseudonym is computed from the holder secret, the last message
+	if data.pseudonym != nil {
+		response, err := hiddenMessageResponse(parsed.signatureProof, parsed.revealed, parsed.generators.MessagesCount-1)
+		if err != nil {
+			return fmt.Errorf("pseudonym: %w", err)
+		}
+		if err := verifyPseudonym(curve, data.pseudonym, challenge, response); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,280 @@
+package core
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/multiformats/go-multibase"
+)
+
+// ConformanceImplementation The name of this implementation in the conformance vectors it exports.
+const ConformanceImplementation = "jsonld-vc-bbs-go"
+
+// conformanceNonce The nonce of the proofs derived from the vectors that have no nonce.
+var conformanceNonce = []byte("conformance")
+
+// LoadConformanceVectors Load the conformance vectors of the JSON files of a directory, sorted by file name.
+//
+//	dir string
+//
+// returns:
+//
+//	vectors []*model.ConformanceVector
+//	err error
+func LoadConformanceVectors(dir string) ([]*model.ConformanceVector, error) {
+	return loadVectors(dir, func(vector *model.ConformanceVector, name string) {
+		if vector.Name == "" {
+			vector.Name = name
+		}
+	})
+}
+
+// LoadBbsVectors Load the BBS vectors of the JSON files of a directory, sorted by file name.
+//
+//	dir string
+//
+// returns:
+//
+//	vectors []*model.BbsVector
+//	err error
+func LoadBbsVectors(dir string) ([]*model.BbsVector, error) {
+	return loadVectors(dir, func(vector *model.BbsVector, name string) {
+		if vector.Name == "" {
+			vector.Name = name
+		}
+	})
+}
+
+// loadVectors Parse the JSON files of a directory, sorted by file name, naming the vectors after their file.
+func loadVectors[T any](dir string, setName func(vector *T, name string)) ([]*T, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(paths)
+
+	vectors := make([]*T, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		vector := new(T)
+		if err := json.Unmarshal(data, vector); err != nil {
+			return nil, fmt.Errorf("parse conformance vector %s: %w", path, err)
+		}
+		setName(vector, filepath.Base(path))
+		vectors = append(vectors, vector)
+	}
+
+	return vectors, nil
+}
+
+// RunConformanceVector Run the checks of a conformance vector: verify its signed credential, derive a proof from it
+// if the vector has a frame, and verify its derived credential, if any.
+//
+//	ctx context.Context
+//	vector *model.ConformanceVector
+//	options *model.SignatureSuiteOptions nullable, the contexts and the ciphersuite of the vector are added to them
+//
+// returns:
+//
+//	results []*model.ConformanceResult one result per check run, in the order above
+//	err error if the vector is not well-formed
+func RunConformanceVector(ctx context.Context, vector *model.ConformanceVector, options *model.SignatureSuiteOptions) ([]*model.ConformanceResult, error) {
+	_, publicKey, err := multibase.Decode(string(multibase.Base58BTC) + vector.PublicKeyBase58)
+	if err != nil || vector.PublicKeyBase58 == "" {
+		return nil, fmt.Errorf("conformance vector %s has no valid publicKeyBase58", vector.Name)
+	}
+	nonce := conformanceNonce
+	if vector.Nonce != "" {
+		nonce, err = base64.StdEncoding.DecodeString(vector.Nonce)
+		if err != nil {
+			return nil, fmt.Errorf("The nonce is not in base64: %w", err)
+		}
+	}
+	if vector.SignedCredential == nil {
+		return nil, fmt.Errorf("conformance vector %s has no signed credential", vector.Name)
+	}
+
+	vectorOptions := model.SignatureSuiteOptions{}
+	if options != nil {
+		vectorOptions = *options
+	}
+	vectorOptions.Contexts = make(map[string]map[string]interface{})
+	if options != nil {
+		for url, context := range options.Contexts {
+			vectorOptions.Contexts[url] = context
+		}
+	}
+	for url, context := range vector.Contexts {
+		vectorOptions.Contexts[url] = context
+	}
+	if vector.Ciphersuite != "" {
+		vectorOptions.Ciphersuite = vector.Ciphersuite
+	}
+	signatureSuite := NewSignatureSuite2020(publicKey, nil, &vectorOptions)
+	proofSuite := NewSignatureProofSuite2020(publicKey, &vectorOptions)
+
+	// 1. Verify the signed credential
+	verified := signatureSuite.VerifyContext(ctx, deepCopyMap(vector.SignedCredential))
+	results := []*model.ConformanceResult{{
+		Vector: vector.Name,
+		Check:  model.ConformanceVerify,
+		Passed: verified.Success == vector.SignatureValid,
+		Error:  verified.Error,
+	}}
+
+	// 2. Derive a proof from the signed credential, and verify it
+	if vector.Frame != nil && vector.SignatureValid {
+		result := &model.ConformanceResult{Vector: vector.Name, Check: model.ConformanceDeriveProof}
+		derived, err := proofSuite.DeriveProofContext(ctx, deepCopyMap(vector.SignedCredential), deepCopyMap(vector.Frame), nonce)
+		if err != nil {
+			result.Error = err
+		} else {
+			verified := proofSuite.VerifyProofContext(ctx, derived, nil)
+			result.Passed, result.Error = verified.Success, verified.Error
+		}
+		results = append(results, result)
+	}
+
+	// 3. Verify the derived credential
+	if vector.DerivedCredential != nil {
+		verified := proofSuite.VerifyProofContext(ctx, deepCopyMap(vector.DerivedCredential), nil)
+		results = append(results, &model.ConformanceResult{
+			Vector: vector.Name,
+			Check:  model.ConformanceVerifyProof,
+			Passed: verified.Success == vector.DerivedProofValid,
+			Error:  verified.Error,
+		})
+	}
+
+	return results, nil
+}
+
+// RunBbsVector Verify the signature or the derived proof of a BBS vector over its raw messages.
+//
+//	vector *model.BbsVector
+//
+// returns:
+//
+//	results []*model.ConformanceResult one result per check run: verify for a signature, verifyProof for a proof
+//	err error if the vector is not well-formed
+func RunBbsVector(vector *model.BbsVector) ([]*model.ConformanceResult, error) {
+	publicKey, err := base64.StdEncoding.DecodeString(vector.PublicKey)
+	if err != nil || len(publicKey) == 0 {
+		return nil, fmt.Errorf("BBS vector %s has no valid publicKey", vector.Name)
+	}
+	messages := make([][]byte, len(vector.Messages))
+	for i, message := range vector.Messages {
+		messages[i] = []byte(message)
+	}
+	ciphersuite := vector.Ciphersuite
+	if ciphersuite == "" {
+		ciphersuite = model.BbsCiphersuiteBBSPlus
+	}
+	scheme := newSignatureScheme(ciphersuite, nil)
+
+	results := make([]*model.ConformanceResult, 0, 2)
+	if vector.Signature != "" {
+		signature, err := base64.StdEncoding.DecodeString(vector.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("BBS vector %s: the signature is not in base64: %w", vector.Name, err)
+		}
+		err = scheme.verify(publicKey, signature, messages)
+		results = append(results, &model.ConformanceResult{
+			Vector: vector.Name,
+			Check:  model.ConformanceVerify,
+			Passed: (err == nil) == vector.Valid,
+			Error:  err,
+		})
+	}
+	if vector.Proof != "" {
+		proof, err := base64.StdEncoding.DecodeString(vector.Proof)
+		if err != nil {
+			return nil, fmt.Errorf("BBS vector %s: the proof is not in base64: %w", vector.Name, err)
+		}
+		nonce, err := base64.StdEncoding.DecodeString(vector.Nonce)
+		if err != nil {
+			return nil, fmt.Errorf("BBS vector %s: the nonce is not in base64: %w", vector.Name, err)
+		}
+		revealed := make([][]byte, len(vector.Revealed))
+		for i, index := range vector.Revealed {
+			if index < 0 || index >= len(messages) {
+				return nil, fmt.Errorf("BBS vector %s: revealed index %d out of the %d messages", vector.Name, index, len(messages))
+			}
+			revealed[i] = messages[index]
+		}
+		err = scheme.verifyProof(publicKey, proof, revealed, nonce)
+		results = append(results, &model.ConformanceResult{
+			Vector: vector.Name,
+			Check:  model.ConformanceVerifyProof,
+			Passed: (err == nil) == vector.Valid,
+			Error:  err,
+		})
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("BBS vector %s has neither a signature nor a proof", vector.Name)
+	}
+
+	return results, nil
+}
+
+// ExportConformanceVector Sign a credential, derive a proof from it with a frame, and export them as a conformance vector
+// for other implementations. The clock and the source of randomness of the options make the vector reproducible.
+//
+//	ctx context.Context
+//	name string
+//	publicKey []byte
+//	privateKey []byte
+//	credential model.JsonLdCredentialNoProof
+//	frame model.JsonLdFrame
+//	nonce []byte
+//	options *model.SignatureSuiteOptions nullable, its contexts and its ciphersuite are embedded in the vector
+//
+// returns:
+//
+//	vector *model.ConformanceVector
+//	err error
+func ExportConformanceVector(
+	ctx context.Context,
+	name string,
+	publicKey, privateKey []byte,
+	credential model.JsonLdCredentialNoProof,
+	frame model.JsonLdFrame,
+	nonce []byte,
+	options *model.SignatureSuiteOptions,
+) (*model.ConformanceVector, error) {
+	signed, _, err := NewSignatureSuite2020(publicKey, privateKey, options).SignContext(ctx, credential)
+	if err != nil {
+		return nil, err
+	}
+	derived, err := NewSignatureProofSuite2020(publicKey, options).DeriveProofContext(ctx, deepCopyMap(signed), frame, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	vector := &model.ConformanceVector{
+		Name:              name,
+		Implementation:    ConformanceImplementation,
+		PublicKeyBase58:   encodeBase58(publicKey),
+		SignedCredential:  signed,
+		Frame:             frame,
+		Nonce:             base64.StdEncoding.EncodeToString(nonce),
+		DerivedCredential: derived,
+		SignatureValid:    true,
+		DerivedProofValid: true,
+	}
+	if options != nil {
+		vector.Contexts = options.Contexts
+		vector.Ciphersuite = options.Ciphersuite
+	}
+
+	return vector, nil
+}
@@ -0,0 +1,130 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/stretchr/testify/suite"
+)
+
+// the vectors exported by this library, kept as regression data, and the vectors published by other implementations
+const (
+	regressionVectorsDir = "testdata/vectors/regression"
+	externalVectorsDir   = "testdata/vectors/external"
+)
+
+type ConformanceTestSuite struct {
+	suite.Suite
+	vectors []*model.ConformanceVector
+}
+
+func TestConformanceTestSuite(t *testing.T) {
+	suite.Run(t, new(ConformanceTestSuite))
+}
+
+func (s *ConformanceTestSuite) SetupTest() {
+	var err error
+	s.vectors, err = core.LoadConformanceVectors(regressionVectorsDir)
+	s.Require().NoError(err)
+	s.Require().NotEmpty(s.vectors)
+}
+
+func (s *ConformanceTestSuite) TestVectors() {
+	for _, vector := range s.vectors {
+		results, err := core.RunConformanceVector(context.Background(), vector, nil)
+		s.Require().NoError(err, vector.Name)
+		s.NotEmpty(results)
+		for _, result := range results {
+			s.True(result.Passed, "%s %s: %v", result.Vector, result.Check, result.Error)
+		}
+	}
+}
+
+func (s *ConformanceTestSuite) TestExternalVectors() {
+	vectors, err := core.LoadConformanceVectors(externalVectorsDir)
+	s.Require().NoError(err)
+	if len(vectors) == 0 {
+		s.T().Skip("no BbsBlsSignature2020 credential published by another implementation in " + externalVectorsDir)
+	}
+	for _, vector := range vectors {
+		s.NotEqual(core.ConformanceImplementation, vector.Implementation, vector.Name)
+		s.NotEmpty(vector.Source, vector.Name)
+		s.NotEmpty(vector.License, vector.Name)
+		results, err := core.RunConformanceVector(context.Background(), vector, nil)
+		s.Require().NoError(err, vector.Name)
+		for _, result := range results {
+			s.True(result.Passed, "%s %s: %v", result.Vector, result.Check, result.Error)
+		}
+	}
+}
+
+func (s *ConformanceTestSuite) TestExternalBbsVectors() {
+	bbsVectors, err := core.LoadBbsVectors(externalVectorsDir + "/bbs")
+	s.Require().NoError(err)
+	s.Require().NotEmpty(bbsVectors)
+	for _, vector := range bbsVectors {
+		s.NotEmpty(vector.Source, vector.Name)
+		s.NotEmpty(vector.License, vector.Name)
+		results, err := core.RunBbsVector(vector)
+		s.Require().NoError(err, vector.Name)
+		for _, result := range results {
+			s.True(result.Passed, "%s %s: %v", result.Vector, result.Check, result.Error)
+		}
+	}
+}
+
+func (s *ConformanceTestSuite) TestExportIsReproducible() {
+	var credential model.JsonLdCredentialNoProof
+	credentialBytes, err := os.ReadFile("testdata/unsignedCredential.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(credentialBytes, &credential))
+
+	for _, name := range []string{
+		"permanent-resident-card",
+		"permanent-resident-card-bls12-381-sha-256",
+		"permanent-resident-card-bls12-381-shake-256",
+	} {
+		var expected *model.ConformanceVector
+		for _, vector := range s.vectors {
+			if vector.Name == name {
+				expected = vector
+			}
+		}
+		s.Require().NotNil(expected, name)
+
+		options := offlineOptions(s.T())
+		options.Clock = func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+		options.Rand = &seededReader{seed: []byte("jsonld-vc-bbs-go")}
+		options.Ciphersuite = expected.Ciphersuite
+
+		vector, err := core.ExportConformanceVector(
+			context.Background(), expected.Name, benchmarkPublicKey(s.T()), benchmarkPrivateKey(s.T()),
+			credential, expected.Frame, []byte("conformance"), options,
+		)
+		s.Require().NoError(err, name)
+
+		// the exported documents are identical to the published ones
+		actualJSON, err := json.Marshal([]interface{}{vector.PublicKeyBase58, vector.SignedCredential, vector.DerivedCredential})
+		s.Require().NoError(err)
+		expectedJSON, err := json.Marshal([]interface{}{expected.PublicKeyBase58, expected.SignedCredential, expected.DerivedCredential})
+		s.Require().NoError(err)
+		s.JSONEq(string(expectedJSON), string(actualJSON), name)
+	}
+}
+
+func (s *ConformanceTestSuite) TestMalformedVector() {
+	vector := *s.vectors[0]
+	vector.PublicKeyBase58 = "not base58!"
+	_, err := core.RunConformanceVector(context.Background(), &vector, nil)
+	s.Error(err)
+
+	vector = *s.vectors[0]
+	vector.Nonce = "not base64!"
+	_, err = core.RunConformanceVector(context.Background(), &vector, nil)
+	s.Error(err)
+}
@@ -0,0 +1,185 @@
+package core
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/piprate/json-gold/ld"
+)
+
+const (
+	// DefaultContextCacheSize is the default maximum number of remote contexts kept in the shared cache.
+	DefaultContextCacheSize = 256
+	// DefaultContextCacheTTL is the default time a remote context is kept in the shared cache.
+	DefaultContextCacheTTL = 24 * time.Hour
+)
+
+// sharedContextCache is the process-wide cache used by the default document loader of every suite.
+var sharedContextCache = NewContextCache(DefaultContextCacheSize, DefaultContextCacheTTL)
+
+// SharedContextCache Return the process-wide cache of remote JSON-LD contexts shared by all the suites
+// using the default document loader.
+//
+// returns:
+//
+//	cache *ContextCache
+func SharedContextCache() *ContextCache {
+	return sharedContextCache
+}
+
+// A ContextCache is a bounded, least recently used cache of remote JSON-LD documents.
+// Entries expire after a configurable time to live.
+// A ContextCache is safe for concurrent use.
+type ContextCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ttl        time.Duration
+	entries    map[string]*list.Element
+	order      *list.List
+}
+
+type contextCacheEntry struct {
+	url       string
+	document  *ld.RemoteDocument
+	expiresAt time.Time
+}
+
+// NewContextCache initializes and returns ContextCache.
+//
+//	maxEntries int The maximum number of documents to keep, 0 means unbounded.
+//	ttl time.Duration The time a document is kept in the cache, 0 means no expiration.
+func NewContextCache(maxEntries int, ttl time.Duration) *ContextCache {
+	return &ContextCache{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Configure Change the limits of the cache, evicting the documents exceeding the new size.
+//
+//	maxEntries int The maximum number of documents to keep, 0 means unbounded.
+//	ttl time.Duration The time a document is kept in the cache, 0 means no expiration.
+func (c *ContextCache) Configure(maxEntries int, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.maxEntries = maxEntries
+	c.ttl = ttl
+	c.evict()
+}
+
+// Get Retrieve a non-expired document from the cache.
+//
+//	url string The URL of the document.
+//
+// returns:
+//
+//	document *ld.RemoteDocument
+//	found bool
+func (c *ContextCache) Get(url string) (*ld.RemoteDocument, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[url]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*contextCacheEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, url)
+
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+
+	return entry.document, true
+}
+
+// Put Store a document in the cache, evicting the least recently used ones if the cache is full.
+//
+//	url string The URL of the document.
+//	document *ld.RemoteDocument
+func (c *ContextCache) Put(url string, document *ld.RemoteDocument) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if c.ttl > 0 {
+		expiresAt = time.Now().Add(c.ttl)
+	}
+
+	if element, ok := c.entries[url]; ok {
+		entry := element.Value.(*contextCacheEntry)
+		entry.document = document
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+
+		return
+	}
+
+	c.entries[url] = c.order.PushFront(&contextCacheEntry{
+		url:       url,
+		document:  document,
+		expiresAt: expiresAt,
+	})
+	c.evict()
+}
+
+// Len Return the number of documents in the cache, including the expired ones not yet evicted.
+func (c *ContextCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// Clear Remove all the documents from the cache.
+func (c *ContextCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// evict Remove the least recently used documents exceeding the maximum size. Must be called with the lock held.
+func (c *ContextCache) evict() {
+	if c.maxEntries <= 0 {
+		return
+	}
+
+	for c.order.Len() > c.maxEntries {
+		element := c.order.Back()
+		c.order.Remove(element)
+		delete(c.entries, element.Value.(*contextCacheEntry).url)
+	}
+}
+
+// A cachingDocumentLoader loads documents through a ContextCache, falling back to another loader on a miss.
+type cachingDocumentLoader struct {
+	op     *operation // nullable, the operation to which the source of the documents is reported
+	cache  *ContextCache
+	loader ld.DocumentLoader
+}
+
+func (l cachingDocumentLoader) LoadDocument(u string) (*ld.RemoteDocument, error) {
+	if document, ok := l.cache.Get(u); ok {
+		l.op.record(u, model.ContextSourceCache)
+		return document, nil
+	}
+
+	document, err := l.loader.LoadDocument(u)
+	if err != nil {
+		return nil, err
+	}
+	l.cache.Put(u, document)
+	l.op.record(u, model.ContextSourceRemote)
+
+	return document, nil
+}
@@ -0,0 +1,99 @@
+package core_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/piprate/json-gold/ld"
+	"github.com/stretchr/testify/suite"
+)
+
+type ContextCacheTestSuite struct {
+	suite.Suite
+}
+
+func TestContextCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(ContextCacheTestSuite))
+}
+
+func (s *ContextCacheTestSuite) TestGetAndPut() {
+	subject := core.NewContextCache(2, time.Minute)
+
+	_, found := subject.Get("https://example.com/a")
+	s.False(found)
+
+	document := &ld.RemoteDocument{DocumentURL: "https://example.com/a"}
+	subject.Put("https://example.com/a", document)
+
+	actual, found := subject.Get("https://example.com/a")
+	s.True(found)
+	s.Same(document, actual)
+}
+
+func (s *ContextCacheTestSuite) TestLeastRecentlyUsedEviction() {
+	subject := core.NewContextCache(2, time.Minute)
+
+	subject.Put("https://example.com/a", &ld.RemoteDocument{})
+	subject.Put("https://example.com/b", &ld.RemoteDocument{})
+
+	// touch "a" so that "b" becomes the least recently used
+	_, found := subject.Get("https://example.com/a")
+	s.True(found)
+
+	subject.Put("https://example.com/c", &ld.RemoteDocument{})
+	s.Equal(2, subject.Len())
+
+	_, found = subject.Get("https://example.com/b")
+	s.False(found)
+	_, found = subject.Get("https://example.com/a")
+	s.True(found)
+	_, found = subject.Get("https://example.com/c")
+	s.True(found)
+}
+
+func (s *ContextCacheTestSuite) TestExpiration() {
+	subject := core.NewContextCache(2, 10*time.Millisecond)
+
+	subject.Put("https://example.com/a", &ld.RemoteDocument{})
+	time.Sleep(20 * time.Millisecond)
+
+	_, found := subject.Get("https://example.com/a")
+	s.False(found)
+	s.Equal(0, subject.Len())
+}
+
+func (s *ContextCacheTestSuite) TestConfigureShrinksCache() {
+	subject := core.NewContextCache(0, 0)
+
+	for i := 0; i < 10; i++ {
+		subject.Put(fmt.Sprintf("https://example.com/%d", i), &ld.RemoteDocument{})
+	}
+	s.Equal(10, subject.Len())
+
+	subject.Configure(3, time.Minute)
+	s.Equal(3, subject.Len())
+
+	subject.Clear()
+	s.Equal(0, subject.Len())
+}
+
+func (s *ContextCacheTestSuite) TestConcurrentAccess() {
+	subject := core.NewContextCache(5, time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			url := fmt.Sprintf("https://example.com/%d", i%10)
+			subject.Put(url, &ld.RemoteDocument{})
+			subject.Get(url)
+		}(i)
+	}
+	wg.Wait()
+
+	s.LessOrEqual(subject.Len(), 5)
+}
@@ -0,0 +1,167 @@
+package core
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+)
+
+// Most verification failures are caused by a credential normalized differently by the issuer and the verifier,
+// e.g. a context resolved to another document or a property not defined by the contexts, thus silently dropped.
+// The diagnostics report the canonical statements as they are verified, where the contexts come from,
+// the dropped properties and the statements that differ from a reference, e.g. the ones normalized by the issuer.
+
+// contextTraceKey is the key of the *contextTrace of the context of a diagnosed operation.
+type contextTraceKey struct{}
+
+// A contextTrace records the contexts loaded by the operations of a diagnosis, once per URL.
+type contextTrace struct {
+	contexts []model.LoadedContext
+}
+
+func (t *contextTrace) record(u string, source model.ContextSource) {
+	for _, loaded := range t.contexts {
+		if loaded.URL == u {
+			return
+		}
+	}
+	t.contexts = append(t.contexts, model.LoadedContext{URL: u, Source: source})
+}
+
+// Diagnose Verify a signed JSON-LD credential and explain the outcome.
+// The diagnostics are computed before the verification, so that the contexts report whether they were downloaded.
+// The statements of the credential and of its proof are reported separately, and only the ones of the credential are
+// compared with the reference: the proof is normalized with the contexts of the library.
+//
+//	ctx context.Context
+//	credential model.JsonLdCredential
+//	reference []string nullable, the canonical statements expected for the credential without its proof,
+//	  e.g. the Statements of the diagnostics of the issuer
+//
+// returns:
+//
+//	diagnostics *model.VerificationDiagnostics
+func (s *SignatureSuite2020) Diagnose(ctx context.Context, credential model.JsonLdCredential, reference []string) *model.VerificationDiagnostics {
+	trace := &contextTrace{}
+	traceCtx := context.WithValue(ctx, contextTraceKey{}, trace)
+	diagnostics := &model.VerificationDiagnostics{}
+
+	credCopy, proof, err := splitSignedCredential(credential)
+	if err == nil {
+		diagnostics.Statements, err = s.normalizer.NormalizeDocumentContext(traceCtx, credCopy)
+	}
+	if err == nil {
+		diagnostics.ProofStatements, err = s.normalizer.NormalizeDocumentContext(traceCtx, proof)
+	}
+	if err != nil {
+		diagnostics.Error = err
+	} else {
+		if reference != nil {
+			diagnostics.Diff = diffStatements(reference, diagnostics.Statements)
+		}
+
+		for prefix, document := range map[string]map[string]interface{}{"": credCopy, c.CredentialFieldProof: proof} {
+			dropped, err := s.normalizer.droppedTerms(traceCtx, prefix, document)
+			if err != nil {
+				diagnostics.Error = err
+				break
+			}
+			diagnostics.DroppedTerms = append(diagnostics.DroppedTerms, dropped...)
+		}
+		slices.Sort(diagnostics.DroppedTerms)
+	}
+	diagnostics.Contexts = trace.contexts
+	diagnostics.Result = s.VerifyContext(ctx, credential)
+
+	return diagnostics
+}
+
+// droppedTerms Find the properties of a JSON-LD document that are not defined by its contexts.
+// The properties dropped by the expansion are the ones missing once the document is compacted again with its contexts.
+//
+//	ctx context.Context
+//	prefix string The path of the document, empty for a root document.
+//	document map[string]interface{}
+//
+// returns:
+//
+//	paths []string dot-separated paths of the dropped properties
+//	err error
+func (n *normalizer) droppedTerms(ctx context.Context, prefix string, document map[string]interface{}) ([]string, error) {
+	compacted, err := n.Compact(ctx, deepCopyMap(document), deepCopyValue(document[c.CredentialFieldContext]))
+	if err != nil {
+		return nil, err
+	}
+
+	original := make(map[string]bool)
+	collectPropertyPaths(prefix, document, original)
+	kept := make(map[string]bool)
+	collectPropertyPaths(prefix, compacted, kept)
+
+	dropped := make([]string, 0)
+	for path := range original {
+		if !kept[path] {
+			dropped = append(dropped, path)
+		}
+	}
+
+	return dropped, nil
+}
+
+// collectPropertyPaths Collect the dot-separated paths of the properties of a JSON value.
+// The keywords and their aliases are not properties: e.g. a node reference may be compacted to its identifier.
+func collectPropertyPaths(prefix string, value interface{}, paths map[string]bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if strings.HasPrefix(key, "@") || key == c.CredentialFieldId || key == c.CredentialFieldType {
+				continue
+			}
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			paths[path] = true
+			collectPropertyPaths(path, item, paths)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectPropertyPaths(prefix, item, paths)
+		}
+	}
+}
+
+// diffStatements Compare two lists of canonical statements regardless of their order.
+//
+//	reference []string The expected statements.
+//	statements []string The computed statements.
+//
+// returns:
+//
+//	diff []model.StatementDiff the missing statements in reference order, then the unexpected ones in computed order
+func diffStatements(reference, statements []string) []model.StatementDiff {
+	counts := make(map[string]int, len(statements))
+	for _, statement := range statements {
+		counts[statement]++
+	}
+
+	diff := make([]model.StatementDiff, 0)
+	for _, statement := range reference {
+		if counts[statement] > 0 {
+			counts[statement]--
+		} else {
+			diff = append(diff, model.StatementDiff{Kind: model.StatementMissing, Statement: statement})
+		}
+	}
+	for _, statement := range statements {
+		if counts[statement] > 0 {
+			counts[statement]--
+			diff = append(diff, model.StatementDiff{Kind: model.StatementUnexpected, Statement: statement})
+		}
+	}
+
+	return diff
+}
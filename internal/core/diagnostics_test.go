@@ -0,0 +1,82 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type DiagnosticsTestSuite struct {
+	suite.Suite
+	suite      *core.SignatureSuite2020
+	credential model.JsonLdCredential
+}
+
+func TestDiagnosticsTestSuite(t *testing.T) {
+	suite.Run(t, new(DiagnosticsTestSuite))
+}
+
+func (s *DiagnosticsTestSuite) SetupTest() {
+	s.suite = core.NewSignatureSuite2020(benchmarkPublicKey(s.T()), benchmarkPrivateKey(s.T()), offlineOptions(s.T()))
+
+	var unsigned model.JsonLdCredentialNoProof
+	credentialBytes, err := os.ReadFile("testdata/unsignedCredential.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(credentialBytes, &unsigned))
+	s.credential, _, err = s.suite.Sign(unsigned)
+	s.Require().NoError(err)
+}
+
+func (s *DiagnosticsTestSuite) TestValidCredential() {
+	diagnostics := s.suite.Diagnose(context.Background(), s.credential, nil)
+	s.NoError(diagnostics.Error)
+	s.True(diagnostics.Result.Success, diagnostics.Result.Error)
+	s.NotEmpty(diagnostics.Statements)
+	s.NotEmpty(diagnostics.ProofStatements)
+
+	// the statements are signed as the ones of the proof followed by the ones of the credential
+	signingData, err := s.suite.ProvideSigningData(s.credential)
+	s.Require().NoError(err)
+	s.Require().Len(signingData, len(diagnostics.ProofStatements)+len(diagnostics.Statements))
+	for i, statement := range append(diagnostics.ProofStatements, diagnostics.Statements...) {
+		s.Equal(statement, string(signingData[i]))
+	}
+	s.Empty(diagnostics.DroppedTerms)
+	s.Empty(diagnostics.Diff)
+	s.Contains(diagnostics.Contexts, model.LoadedContext{URL: "https://w3id.org/citizenship/v1", Source: model.ContextSourcePreloaded})
+}
+
+func (s *DiagnosticsTestSuite) TestDiffWithReference() {
+	ctx := context.Background()
+	// the issuer provides the statements of the credential, without the ones of the proof
+	reference := s.suite.Diagnose(ctx, s.credential, nil).Statements
+	s.Require().NotEmpty(reference)
+	s.Empty(s.suite.Diagnose(ctx, s.credential, reference).Diff)
+
+	// a claim changed after issuance, and a property not defined by the contexts
+	subject := s.credential["credentialSubject"].(map[string]interface{})
+	subject["givenName"] = "John"
+	subject["nickname"] = "Jo"
+
+	diagnostics := s.suite.Diagnose(ctx, s.credential, reference)
+	s.NoError(diagnostics.Error)
+	s.False(diagnostics.Result.Success)
+	s.Equal([]string{"credentialSubject.nickname"}, diagnostics.DroppedTerms)
+	s.Require().Len(diagnostics.Diff, 2)
+	s.Equal(model.StatementMissing, diagnostics.Diff[0].Kind)
+	s.Contains(diagnostics.Diff[0].Statement, `"Jace"`)
+	s.Equal(model.StatementUnexpected, diagnostics.Diff[1].Kind)
+	s.Contains(diagnostics.Diff[1].Statement, `"John"`)
+}
+
+func (s *DiagnosticsTestSuite) TestMissingProof() {
+	delete(s.credential, "proof")
+	diagnostics := s.suite.Diagnose(context.Background(), s.credential, nil)
+	s.Error(diagnostics.Error)
+	s.False(diagnostics.Result.Success)
+}
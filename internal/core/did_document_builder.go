@@ -0,0 +1,173 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+)
+
+// A DidDocumentBuilder builds the DID document of an issuer from its BBS+ public keys.
+// The verification method ids default to the ones emitted by KeyEncoder.CreateDidKeyVerificationMethod.
+type DidDocumentBuilder struct {
+	did        string
+	didKey     []byte // public key of the DID, nil for other methods than did:key
+	keys       []model.DidDocumentKey
+	keyEncoder *KeyEncoder
+}
+
+// NewDidKeyDocumentBuilder initializes and returns DidDocumentBuilder for the did:key of a public key.
+// Additional keys must be other forms of the same public key.
+//
+//	key model.DidDocumentKey The key of the DID.
+//
+// returns:
+//
+//	builder *DidDocumentBuilder
+//	err error
+func NewDidKeyDocumentBuilder(key model.DidDocumentKey) (*DidDocumentBuilder, error) {
+	keyEncoder := &KeyEncoder{}
+	did, err := keyEncoder.CreateDidKey(key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DidDocumentBuilder{
+		did:        did,
+		didKey:     key.PublicKey,
+		keys:       []model.DidDocumentKey{key},
+		keyEncoder: keyEncoder,
+	}, nil
+}
+
+// NewDidWebDocumentBuilder initializes and returns DidDocumentBuilder for a did:web.
+//
+//	did string example: "did:web:issuer.example.com"
+//	key model.DidDocumentKey The main key of the issuer.
+//
+// returns:
+//
+//	builder *DidDocumentBuilder
+//	err error if the DID is not a valid did:web
+func NewDidWebDocumentBuilder(did string, key model.DidDocumentKey) (*DidDocumentBuilder, error) {
+	if _, err := didWebURL(did); err != nil {
+		return nil, err
+	}
+
+	return &DidDocumentBuilder{
+		did:        did,
+		keys:       []model.DidDocumentKey{key},
+		keyEncoder: &KeyEncoder{},
+	}, nil
+}
+
+// AddKey Add a key to the document, e.g. a key in rotation or another form of an existing key.
+//
+//	key model.DidDocumentKey
+//
+// returns:
+//
+//	builder *DidDocumentBuilder
+func (b *DidDocumentBuilder) AddKey(key model.DidDocumentKey) *DidDocumentBuilder {
+	b.keys = append(b.keys, key)
+
+	return b
+}
+
+// Build Create the DID document.
+//
+// returns:
+//
+//	document *model.DidDocument
+//	err error if a key is invalid or two verification methods share the same id
+func (b *DidDocumentBuilder) Build() (*model.DidDocument, error) {
+	document := &model.DidDocument{
+		ID: b.did,
+	}
+	contexts := []interface{}{c.ContextDidV1}
+
+	for _, key := range b.keys {
+		verificationMethod, err := b.buildVerificationMethod(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if slices.ContainsFunc(document.VerificationMethod, func(existing model.VerificationMethod) bool {
+			return existing.ID == verificationMethod.ID
+		}) {
+			return nil, fmt.Errorf("duplicate verification method id '%s'", verificationMethod.ID)
+		}
+		document.VerificationMethod = append(document.VerificationMethod, *verificationMethod)
+
+		context := c.ContextSecurityBbsV1
+		if verificationMethod.Type == c.VerificationMethodTypeMultikey {
+			context = c.ContextMultikeyV1
+		}
+		if !slices.Contains(contexts, interface{}(context)) {
+			contexts = append(contexts, context)
+		}
+
+		relationships := key.Relationships
+		if relationships == nil {
+			relationships = []string{c.ProofPurposeAssertionMethod, c.ProofPurposeAuthentication}
+		}
+		for _, proofPurpose := range relationships {
+			reference := model.VerificationRelationship{Reference: verificationMethod.ID}
+			switch proofPurpose {
+			case c.ProofPurposeAssertionMethod:
+				document.AssertionMethod = append(document.AssertionMethod, reference)
+			case c.ProofPurposeAuthentication:
+				document.Authentication = append(document.Authentication, reference)
+			case c.ProofPurposeCapabilityInvocation:
+				document.CapabilityInvocation = append(document.CapabilityInvocation, reference)
+			case c.ProofPurposeCapabilityDelegation:
+				document.CapabilityDelegation = append(document.CapabilityDelegation, reference)
+			default:
+				return nil, fmt.Errorf("unsupported verification relationship '%s'", proofPurpose)
+			}
+		}
+	}
+	document.Context = contexts
+
+	return document, nil
+}
+
+// buildVerificationMethod Create the verification method of a key.
+func (b *DidDocumentBuilder) buildVerificationMethod(key model.DidDocumentKey) (*model.VerificationMethod, error) {
+	if len(key.PublicKey) == 0 {
+		return nil, fmt.Errorf("public key is empty")
+	}
+	if b.didKey != nil && !bytes.Equal(b.didKey, key.PublicKey) {
+		return nil, fmt.Errorf("a did:key document can only contain the key of the DID")
+	}
+
+	multibaseKey, err := b.keyEncoder.multibaseEncode(key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	fragment := strings.TrimPrefix(key.Fragment, "#")
+	if fragment == "" {
+		fragment = multibaseKey
+	}
+
+	verificationMethod := &model.VerificationMethod{
+		ID:         b.did + "#" + fragment,
+		Type:       key.Type,
+		Controller: b.did,
+	}
+	switch key.Type {
+	case "", c.VerificationMethodTypeBls12381G2Key2020:
+		verificationMethod.Type = c.VerificationMethodTypeBls12381G2Key2020
+		verificationMethod.PublicKeyBase58 = encodeBase58(key.PublicKey)
+	case c.VerificationMethodTypeMultikey:
+		verificationMethod.PublicKeyMultibase = multibaseKey
+	default:
+		return nil, fmt.Errorf("%w: type '%s'", model.ErrUnsupportedVerificationMethod, key.Type)
+	}
+
+	return verificationMethod, nil
+}
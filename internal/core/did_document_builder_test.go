@@ -0,0 +1,121 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type DidDocumentBuilderTestSuite struct {
+	suite.Suite
+	publicKey []byte
+}
+
+func TestDidDocumentBuilderTestSuite(t *testing.T) {
+	suite.Run(t, new(DidDocumentBuilderTestSuite))
+}
+
+func (s *DidDocumentBuilderTestSuite) SetupTest() {
+	s.publicKey = benchmarkPublicKey(s.T())
+}
+
+func (s *DidDocumentBuilderTestSuite) TestDidKeyDocument() {
+	builder, err := core.NewDidKeyDocumentBuilder(model.DidDocumentKey{PublicKey: s.publicKey})
+	s.NoError(err)
+	builder.AddKey(model.DidDocumentKey{
+		PublicKey: s.publicKey,
+		Type:      c.VerificationMethodTypeMultikey,
+		Fragment:  "multikey",
+	})
+
+	document, err := builder.Build()
+	s.NoError(err)
+
+	verificationMethodID, err := (&core.KeyEncoder{}).CreateDidKeyVerificationMethod(s.publicKey)
+	s.NoError(err)
+	didKey, err := (&core.KeyEncoder{}).CreateDidKey(s.publicKey)
+	s.NoError(err)
+
+	s.Equal(didKey, document.ID)
+	s.Equal([]interface{}{c.ContextDidV1, c.ContextSecurityBbsV1, c.ContextMultikeyV1}, document.Context)
+	s.Len(document.VerificationMethod, 2)
+	s.Equal(verificationMethodID, document.VerificationMethod[0].ID)
+	s.Equal(c.VerificationMethodTypeBls12381G2Key2020, document.VerificationMethod[0].Type)
+	s.Equal(didKey+"#multikey", document.VerificationMethod[1].ID)
+	s.Equal([]model.VerificationRelationship{{Reference: verificationMethodID}, {Reference: didKey + "#multikey"}}, document.AssertionMethod)
+	s.Equal(document.AssertionMethod, document.Authentication)
+
+	// both forms carry the same key
+	for _, verificationMethod := range document.VerificationMethod {
+		publicKey, err := core.PublicKeyFromVerificationMethod(&verificationMethod)
+		s.NoError(err)
+		s.Equal(s.publicKey, publicKey)
+	}
+}
+
+func (s *DidDocumentBuilderTestSuite) TestDidWebDocument() {
+	did := "did:web:issuer.example.com"
+	builder, err := core.NewDidWebDocumentBuilder(did, model.DidDocumentKey{
+		PublicKey: s.publicKey,
+		Fragment:  "key-1",
+	})
+	s.NoError(err)
+
+	document, err := builder.Build()
+	s.NoError(err)
+
+	// the serialized document can be published and resolved
+	serialized, err := json.Marshal(document)
+	s.NoError(err)
+	s.JSONEq(`{
+		"@context": ["https://www.w3.org/ns/did/v1", "https://w3id.org/security/bbs/v1"],
+		"id": "did:web:issuer.example.com",
+		"verificationMethod": [{
+			"id": "did:web:issuer.example.com#key-1",
+			"type": "Bls12381G2Key2020",
+			"controller": "did:web:issuer.example.com",
+			"publicKeyBase58": "`+document.VerificationMethod[0].PublicKeyBase58+`"
+		}],
+		"assertionMethod": ["did:web:issuer.example.com#key-1"],
+		"authentication": ["did:web:issuer.example.com#key-1"]
+	}`, string(serialized))
+
+	var parsed model.DidDocument
+	s.NoError(json.Unmarshal(serialized, &parsed))
+	resolver := core.NewMethodDidResolver(map[string]model.DidResolver{c.DidMethodWeb: staticDidResolver{&parsed}})
+	_, publicKey, err := core.DereferenceVerificationMethod(context.Background(), resolver, did+"#key-1", c.ProofPurposeAssertionMethod)
+	s.NoError(err)
+	s.Equal(s.publicKey, publicKey)
+}
+
+func (s *DidDocumentBuilderTestSuite) TestInvalidDocuments() {
+	// duplicate verification method id
+	builder, err := core.NewDidWebDocumentBuilder("did:web:issuer.example.com", model.DidDocumentKey{PublicKey: s.publicKey})
+	s.NoError(err)
+	_, err = builder.AddKey(model.DidDocumentKey{PublicKey: s.publicKey, Type: c.VerificationMethodTypeMultikey}).Build()
+	s.ErrorContains(err, "duplicate verification method id")
+
+	// foreign key in a did:key document
+	builder, err = core.NewDidKeyDocumentBuilder(model.DidDocumentKey{PublicKey: s.publicKey})
+	s.NoError(err)
+	_, err = builder.AddKey(model.DidDocumentKey{PublicKey: []byte{1, 2, 3}, Fragment: "other"}).Build()
+	s.Error(err)
+
+	// invalid did:web
+	_, err = core.NewDidWebDocumentBuilder("did:key:z123", model.DidDocumentKey{PublicKey: s.publicKey})
+	s.Error(err)
+}
+
+// A staticDidResolver resolves every DID to the same document.
+type staticDidResolver struct {
+	document *model.DidDocument
+}
+
+func (r staticDidResolver) Resolve(ctx context.Context, did string) (*model.DidDocument, error) {
+	return r.document, nil
+}
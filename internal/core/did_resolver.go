@@ -0,0 +1,321 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	multibase "github.com/multiformats/go-multibase"
+)
+
+// maxDidDocumentBytes is the maximum size of a DID document fetched by the DidWebResolver.
+const maxDidDocumentBytes = 1 << 20
+
+// A MethodDidResolver dispatches the resolution of a DID to the resolver of its method.
+// A MethodDidResolver is safe for concurrent use as long as its resolvers are.
+type MethodDidResolver struct {
+	resolvers map[string]model.DidResolver
+}
+
+// NewMethodDidResolver initializes and returns MethodDidResolver.
+//
+//	resolvers map[string]model.DidResolver The resolvers by DID method, e.g. "key" or "web".
+func NewMethodDidResolver(resolvers map[string]model.DidResolver) *MethodDidResolver {
+	return &MethodDidResolver{
+		resolvers: resolvers,
+	}
+}
+
+// NewDidResolver Return a resolver supporting the did:key and did:web methods.
+//
+//	client *http.Client nullable, used to fetch did:web documents. If not provided, http.DefaultClient will be used.
+//
+// returns:
+//
+//	resolver *MethodDidResolver
+func NewDidResolver(client *http.Client) *MethodDidResolver {
+	return NewMethodDidResolver(map[string]model.DidResolver{
+		c.DidMethodKey: NewDidKeyResolver(),
+		c.DidMethodWeb: NewDidWebResolver(client),
+	})
+}
+
+// Resolve Resolve a DID with the resolver of its method.
+//
+//	ctx context.Context
+//	did string
+//
+// returns:
+//
+//	document *model.DidDocument
+//	err error model.ErrUnsupportedDidMethod if no resolver is registered for the method
+func (r *MethodDidResolver) Resolve(ctx context.Context, did string) (*model.DidDocument, error) {
+	method, err := didMethod(did)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, ok := r.resolvers[method]
+	if !ok {
+		return nil, fmt.Errorf("%w: did:%s", model.ErrUnsupportedDidMethod, method)
+	}
+
+	return resolver.Resolve(ctx, did)
+}
+
+// A DidKeyResolver resolves did:key identifiers of BLS12-381 G2 public keys, without any network access.
+type DidKeyResolver struct {
+	keyEncoder *KeyEncoder
+}
+
+// NewDidKeyResolver initializes and returns DidKeyResolver.
+func NewDidKeyResolver() *DidKeyResolver {
+	return &DidKeyResolver{
+		keyEncoder: &KeyEncoder{},
+	}
+}
+
+// Resolve Expand a did:key into its DID document.
+// The verification method id is the one emitted by KeyEncoder.CreateDidKeyVerificationMethod, and it is listed under
+// all the verification relationships.
+//
+//	ctx context.Context
+//	did string
+//
+// returns:
+//
+//	document *model.DidDocument
+//	err error
+func (r *DidKeyResolver) Resolve(ctx context.Context, did string) (*model.DidDocument, error) {
+	publicKey, err := r.keyEncoder.DecodeDidKey(did)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", model.ErrDidNotFound, did, err)
+	}
+
+	builder, err := NewDidKeyDocumentBuilder(model.DidDocumentKey{
+		PublicKey: publicKey,
+		Relationships: []string{
+			c.ProofPurposeAuthentication,
+			c.ProofPurposeAssertionMethod,
+			c.ProofPurposeCapabilityInvocation,
+			c.ProofPurposeCapabilityDelegation,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return builder.Build()
+}
+
+// A DidWebResolver resolves did:web identifiers by fetching their DID document over HTTPS
+// (https://w3c-ccg.github.io/did-method-web/).
+// A DidWebResolver is safe for concurrent use.
+type DidWebResolver struct {
+	client *http.Client
+}
+
+// NewDidWebResolver initializes and returns DidWebResolver.
+//
+//	client *http.Client nullable, if not provided http.DefaultClient will be used
+func NewDidWebResolver(client *http.Client) *DidWebResolver {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &DidWebResolver{
+		client: client,
+	}
+}
+
+// Resolve Fetch the DID document of a did:web.
+//
+//	ctx context.Context
+//	did string example: "did:web:issuer.example.com" or "did:web:example.com:issuers:1"
+//
+// returns:
+//
+//	document *model.DidDocument
+//	err error model.ErrDidNotFound if the document does not exist
+func (r *DidWebResolver) Resolve(ctx context.Context, did string) (*model.DidDocument, error) {
+	documentURL, err := didWebURL(did)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, documentURL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/did+json, application/json")
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", did, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusGone {
+		return nil, fmt.Errorf("%w: %s", model.ErrDidNotFound, did)
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("resolve %s: unexpected status %d", did, res.StatusCode)
+	}
+
+	var document model.DidDocument
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxDidDocumentBytes)).Decode(&document); err != nil {
+		return nil, fmt.Errorf("resolve %s: invalid DID document: %w", did, err)
+	}
+	if document.ID != did {
+		return nil, fmt.Errorf("resolve %s: DID document id '%s' does not match", did, document.ID)
+	}
+
+	return &document, nil
+}
+
+// DereferenceVerificationMethod Resolve the DID of a verification method URL, retrieve the verification method and
+// check that it is authorized for a proof purpose.
+// Only Bls12381G2Key2020 and Multikey BLS12-381 G2 keys are supported.
+//
+//	ctx context.Context
+//	resolver model.DidResolver
+//	verificationMethodURL string example: "did:web:issuer.example.com#key-1"
+//	proofPurpose string e.g. "assertionMethod"
+//
+// returns:
+//
+//	verificationMethod *model.VerificationMethod
+//	publicKey []byte
+//	err error
+func DereferenceVerificationMethod(
+	ctx context.Context,
+	resolver model.DidResolver,
+	verificationMethodURL string,
+	proofPurpose string,
+) (*model.VerificationMethod, []byte, error) {
+	did, fragment, ok := strings.Cut(verificationMethodURL, "#")
+	if !ok || fragment == "" {
+		return nil, nil, fmt.Errorf("%w: '%s' has no fragment", model.ErrVerificationMethodNotFound, verificationMethodURL)
+	}
+
+	document, err := resolver.Resolve(ctx, did)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	matches := func(id string) bool {
+		return id == verificationMethodURL || id == "#"+fragment
+	}
+
+	// 1. Check that the verification method is authorized for the proof purpose, and retrieve it if embedded
+	var verificationMethod *model.VerificationMethod
+	authorized := false
+	for _, entry := range document.Relationship(proofPurpose) {
+		if entry.Embedded != nil && matches(entry.Embedded.ID) {
+			verificationMethod = entry.Embedded
+			authorized = true
+		} else if entry.Reference != "" && matches(entry.Reference) {
+			authorized = true
+		}
+	}
+	if !authorized {
+		return nil, nil, fmt.Errorf("%w: %s is not listed under %s", model.ErrVerificationMethodNotAuthorized, verificationMethodURL, proofPurpose)
+	}
+
+	// 2. Retrieve the referenced verification method
+	if verificationMethod == nil {
+		for i := range document.VerificationMethod {
+			if matches(document.VerificationMethod[i].ID) {
+				verificationMethod = &document.VerificationMethod[i]
+				break
+			}
+		}
+	}
+	if verificationMethod == nil {
+		return nil, nil, fmt.Errorf("%w: %s", model.ErrVerificationMethodNotFound, verificationMethodURL)
+	}
+
+	// 3. Extract the public key
+	publicKey, err := PublicKeyFromVerificationMethod(verificationMethod)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return verificationMethod, publicKey, nil
+}
+
+// PublicKeyFromVerificationMethod Extract the BLS12-381 G2 public key of a Bls12381G2Key2020 or Multikey verification method.
+//
+//	verificationMethod *model.VerificationMethod
+//
+// returns:
+//
+//	publicKey []byte
+//	err error model.ErrUnsupportedVerificationMethod if the type or the key is not supported
+func PublicKeyFromVerificationMethod(verificationMethod *model.VerificationMethod) ([]byte, error) {
+	switch verificationMethod.Type {
+	case c.VerificationMethodTypeBls12381G2Key2020:
+		_, publicKey, err := multibase.Decode(string(multibase.Base58BTC) + verificationMethod.PublicKeyBase58)
+		if err != nil || verificationMethod.PublicKeyBase58 == "" {
+			return nil, fmt.Errorf("%w: %s has no valid publicKeyBase58", model.ErrUnsupportedVerificationMethod, verificationMethod.ID)
+		}
+
+		return publicKey, nil
+	case c.VerificationMethodTypeMultikey:
+		publicKey, err := (&KeyEncoder{}).DecodeMultibaseKey(verificationMethod.PublicKeyMultibase)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", model.ErrUnsupportedVerificationMethod, verificationMethod.ID, err)
+		}
+
+		return publicKey, nil
+	default:
+		return nil, fmt.Errorf("%w: type '%s'", model.ErrUnsupportedVerificationMethod, verificationMethod.Type)
+	}
+}
+
+// didMethod Return the method of a DID, e.g. "key" for "did:key:z...".
+func didMethod(did string) (string, error) {
+	parts := strings.SplitN(did, ":", 3)
+	if len(parts) != 3 || parts[0] != "did" || parts[1] == "" || parts[2] == "" {
+		return "", fmt.Errorf("'%s' is not a valid DID", did)
+	}
+
+	return parts[1], nil
+}
+
+// didWebURL Transform a did:web into the URL of its DID document.
+func didWebURL(did string) (string, error) {
+	id, ok := strings.CutPrefix(did, "did:web:")
+	if !ok || id == "" {
+		return "", fmt.Errorf("'%s' is not a did:web", did)
+	}
+
+	segments := strings.Split(id, ":")
+	for i, segment := range segments {
+		decoded, err := url.PathUnescape(segment)
+		if err != nil || decoded == "" || strings.Contains(decoded, "/") {
+			return "", fmt.Errorf("'%s' is not a valid did:web", did)
+		}
+		segments[i] = decoded
+	}
+
+	path := "/.well-known"
+	if len(segments) > 1 {
+		path = "/" + strings.Join(segments[1:], "/")
+	}
+
+	return "https://" + segments[0] + path + "/did.json", nil
+}
+
+// encodeBase58 Encode bytes in base58 (bitcoin alphabet), without multibase prefix.
+func encodeBase58(data []byte) string {
+	encoded, _ := multibase.Encode(multibase.Base58BTC, data)
+
+	return encoded[1:]
+}
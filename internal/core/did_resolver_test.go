@@ -0,0 +1,159 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type DidResolverTestSuite struct {
+	suite.Suite
+	publicKey  []byte
+	didKey     string
+	multikey   string
+	server     *httptest.Server
+	documents  map[string]interface{}
+	serverHost string
+}
+
+func TestDidResolverTestSuite(t *testing.T) {
+	suite.Run(t, new(DidResolverTestSuite))
+}
+
+func (s *DidResolverTestSuite) SetupTest() {
+	s.publicKey = benchmarkPublicKey(s.T())
+
+	var err error
+	s.didKey, err = (&core.KeyEncoder{}).CreateDidKey(s.publicKey)
+	s.NoError(err)
+	s.multikey = strings.TrimPrefix(s.didKey, "did:key:")
+
+	s.documents = make(map[string]interface{})
+	s.server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		document, ok := s.documents[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/did+json")
+		_ = json.NewEncoder(w).Encode(document)
+	}))
+	serverURL, err := url.Parse(s.server.URL)
+	s.NoError(err)
+	s.serverHost = strings.ReplaceAll(serverURL.Host, ":", "%3A")
+}
+
+func (s *DidResolverTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *DidResolverTestSuite) TestResolveDidKey() {
+	verificationMethodURL, err := (&core.KeyEncoder{}).CreateDidKeyVerificationMethod(s.publicKey)
+	s.NoError(err)
+
+	resolver := core.NewDidResolver(nil)
+	verificationMethod, publicKey, err := core.DereferenceVerificationMethod(context.Background(), resolver, verificationMethodURL, c.ProofPurposeAssertionMethod)
+	s.NoError(err)
+	s.Equal(c.VerificationMethodTypeBls12381G2Key2020, verificationMethod.Type)
+	s.Equal(s.didKey, verificationMethod.Controller)
+	s.Equal(s.publicKey, publicKey)
+}
+
+func (s *DidResolverTestSuite) TestResolveDidWeb() {
+	did := "did:web:" + s.serverHost + ":issuers:1"
+	s.documents["/issuers/1/did.json"] = map[string]interface{}{
+		"@context": []interface{}{c.ContextDidV1, c.ContextMultikeyV1},
+		"id":       did,
+		"verificationMethod": []interface{}{
+			map[string]interface{}{
+				"id":                 did + "#key-1",
+				"type":               c.VerificationMethodTypeMultikey,
+				"controller":         did,
+				"publicKeyMultibase": s.multikey,
+			},
+		},
+		"assertionMethod": []interface{}{"#key-1"},
+		"authentication": []interface{}{
+			map[string]interface{}{
+				"id":                 did + "#key-2",
+				"type":               c.VerificationMethodTypeMultikey,
+				"controller":         did,
+				"publicKeyMultibase": s.multikey,
+			},
+		},
+	}
+
+	resolver := core.NewDidResolver(s.server.Client())
+
+	// referenced verification method
+	verificationMethod, publicKey, err := core.DereferenceVerificationMethod(context.Background(), resolver, did+"#key-1", c.ProofPurposeAssertionMethod)
+	s.NoError(err)
+	s.Equal(did+"#key-1", verificationMethod.ID)
+	s.Equal(s.publicKey, publicKey)
+
+	// embedded verification method
+	_, publicKey, err = core.DereferenceVerificationMethod(context.Background(), resolver, did+"#key-2", c.ProofPurposeAuthentication)
+	s.NoError(err)
+	s.Equal(s.publicKey, publicKey)
+
+	// key not listed under the proof purpose
+	_, _, err = core.DereferenceVerificationMethod(context.Background(), resolver, did+"#key-1", c.ProofPurposeAuthentication)
+	s.ErrorIs(err, model.ErrVerificationMethodNotAuthorized)
+}
+
+func (s *DidResolverTestSuite) TestResolveWellKnownDidWeb() {
+	did := "did:web:" + s.serverHost
+	s.documents["/.well-known/did.json"] = map[string]interface{}{
+		"@context": c.ContextDidV1,
+		"id":       did,
+	}
+
+	document, err := core.NewDidWebResolver(s.server.Client()).Resolve(context.Background(), did)
+	s.NoError(err)
+	s.Equal(did, document.ID)
+}
+
+func (s *DidResolverTestSuite) TestDidWebErrors() {
+	resolver := core.NewDidResolver(s.server.Client())
+
+	// unknown document
+	_, err := resolver.Resolve(context.Background(), "did:web:"+s.serverHost+":unknown")
+	s.ErrorIs(err, model.ErrDidNotFound)
+
+	// document served for another DID
+	s.documents["/other/did.json"] = map[string]interface{}{"id": "did:web:attacker.example.com"}
+	_, err = resolver.Resolve(context.Background(), "did:web:"+s.serverHost+":other")
+	s.ErrorContains(err, "does not match")
+
+	// unsupported method
+	_, err = resolver.Resolve(context.Background(), "did:example:123")
+	s.ErrorIs(err, model.ErrUnsupportedDidMethod)
+}
+
+func (s *DidResolverTestSuite) TestUnsupportedVerificationMethod() {
+	did := "did:web:" + s.serverHost
+	s.documents["/.well-known/did.json"] = map[string]interface{}{
+		"id": did,
+		"verificationMethod": []interface{}{
+			map[string]interface{}{
+				"id":                 did + "#key-1",
+				"type":               "Ed25519VerificationKey2020",
+				"controller":         did,
+				"publicKeyMultibase": "z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK",
+			},
+		},
+		"assertionMethod": []interface{}{did + "#key-1"},
+	}
+
+	_, _, err := core.DereferenceVerificationMethod(context.Background(), core.NewDidResolver(s.server.Client()), did+"#key-1", c.ProofPurposeAssertionMethod)
+	s.ErrorIs(err, model.ErrUnsupportedVerificationMethod)
+}
@@ -0,0 +1,29 @@
+package core
+
+import (
+	"context"
+
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/piprate/json-gold/ld"
+)
+
+// WithoutActiveContexts Return a context whose operations expand the documents with the JSON-LD processor,
+// as the diagnosed operations do, rather than with the processed context sets of the normalizer.
+func WithoutActiveContexts(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextTraceKey{}, &contextTrace{})
+}
+
+// CanonicalizeNQuads Canonicalize N-Quads with the URDNA2015 implementation of the library, without limits.
+func CanonicalizeNQuads(nquads string) ([]string, error) {
+	dataset, err := ld.ParseNQuads(nquads)
+	if err != nil {
+		return nil, err
+	}
+
+	return canonicalize(context.Background(), dataset, &model.ResourceLimits{})
+}
+
+// DocumentLoader Return the document loader of the normalizer, serving its preloaded contexts.
+func (n *normalizer) DocumentLoader() ld.DocumentLoader {
+	return n.getDocumentLoader(n.newOperation(context.Background()))
+}
@@ -0,0 +1,223 @@
+package core
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io"
+
+	ml "github.com/IBM/mathlib"
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/hyperledger/aries-bbs-go/bbs"
+)
+
+// The messages of a credential bound to its holder are the statements of the proof and of the credential,
+// followed by the holder binding marker, always disclosed, and by the holder secret, never disclosed.
+// The marker makes the binding visible to the verifiers, who cannot otherwise tell the secret from a hidden statement.
+
+// NewHolderSecret Generate a random holder secret.
+//
+// returns:
+//
+//	secret model.HolderSecret
+//	err error
+func NewHolderSecret() (model.HolderSecret, error) {
+	return NewHolderSecretWithRand(rand.Reader)
+}
+
+// NewHolderSecretWithRand Generate a holder secret from a source of randomness, e.g. a seeded generator
+// to produce reproducible test vectors.
+//
+//	rng io.Reader
+//
+// returns:
+//
+//	secret model.HolderSecret
+//	err error
+func NewHolderSecretWithRand(rng io.Reader) (model.HolderSecret, error) {
+	secret := make(model.HolderSecret, c.HolderSecretSize)
+	if _, err := io.ReadFull(rng, secret); err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrRandomnessUnavailable, err.Error())
+	}
+
+	return secret, nil
+}
+
+// NewHolderBindingRequest Commit to the holder secret in order to obtain a credential bound to it.
+// The commitment is hidden by a random blinding factor, that the holder keeps to unblind the signature of the issuer.
+//
+//	secret model.HolderSecret
+//	issuerPublicKey []byte
+//	messagesCount int The number of messages of the credential, returned by SignatureSuite2020.PrepareHolderBinding.
+//	issuerNonce []byte The nonce of the issuer, to which the proof of knowledge of the secret is bound.
+//
+// returns:
+//
+//	request *model.HolderBindingRequest to send to the issuer
+//	blinding []byte to keep secret until the credential is received
+//	err error
+func NewHolderBindingRequest(
+	secret model.HolderSecret,
+	issuerPublicKey []byte,
+	messagesCount int,
+	issuerNonce []byte,
+) (*model.HolderBindingRequest, []byte, error) {
+	return NewHolderBindingRequestWithRand(rand.Reader, secret, issuerPublicKey, messagesCount, issuerNonce)
+}
+
+// NewHolderBindingRequestWithRand Commit to the holder secret as NewHolderBindingRequest,
+// drawing the blinding factor from a source of randomness, e.g. a seeded generator to produce reproducible test vectors.
+//
+//	rng io.Reader
+//	secret model.HolderSecret
+//	issuerPublicKey []byte
+//	messagesCount int The number of messages of the credential, returned by SignatureSuite2020.PrepareHolderBinding.
+//	issuerNonce []byte The nonce of the issuer, to which the proof of knowledge of the secret is bound.
+//
+// returns:
+//
+//	request *model.HolderBindingRequest to send to the issuer
+//	blinding []byte to keep secret until the credential is received
+//	err error
+func NewHolderBindingRequestWithRand(
+	rng io.Reader,
+	secret model.HolderSecret,
+	issuerPublicKey []byte,
+	messagesCount int,
+	issuerNonce []byte,
+) (*model.HolderBindingRequest, []byte, error) {
+	if len(secret) == 0 {
+		return nil, nil, fmt.Errorf("holder secret is empty")
+	}
+
+	curve := newBBSCurve()
+	lib := bbs.NewBBSLib(curve)
+	bases, err := holderBindingBases(lib, issuerPublicKey, messagesCount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// commitment = h0^blinding * h_secret^secret
+	blinding, err := randomZr(curve, rng)
+	if err != nil {
+		return nil, nil, err
+	}
+	secrets := []*ml.Zr{blinding, bbs.FrFromOKM(secret, curve)}
+	commitment := bases[0].Mul2(secrets[0], bases[1], secrets[1])
+
+	// proof of knowledge of the opening of the commitment
+	committed, err := commitG1(curve, rng, bases, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	challenge := holderBindingChallenge(curve, commitment, committed.Commitment, issuerNonce)
+	proof := committed.GenerateProof(challenge, secrets)
+
+	return &model.HolderBindingRequest{
+		MessagesCount: messagesCount,
+		Commitment:    commitment.Compressed(),
+		Proof:         proof.ToBytes(),
+	}, blinding.Bytes(), nil
+}
+
+// verifyHolderBindingRequest Check the proof of knowledge of the opening of the commitment of a holder.
+//
+//	issuerPublicKey []byte
+//	request *model.HolderBindingRequest
+//	issuerNonce []byte The nonce sent by the issuer to the holder.
+//
+// returns:
+//
+//	err error wrapping model.ErrInvalidHolderBindingRequest if the proof is not valid
+func verifyHolderBindingRequest(issuerPublicKey []byte, request *model.HolderBindingRequest, issuerNonce []byte) error {
+	curve := newBBSCurve()
+	lib := bbs.NewBBSLib(curve)
+	bases, err := holderBindingBases(lib, issuerPublicKey, request.MessagesCount)
+	if err != nil {
+		return err
+	}
+
+	commitment, err := curve.NewG1FromCompressed(request.Commitment)
+	if err != nil {
+		return fmt.Errorf("%w: %s", model.ErrInvalidHolderBindingRequest, err.Error())
+	}
+	proof, err := lib.ParseProofG1(request.Proof)
+	if err != nil {
+		return fmt.Errorf("%w: %s", model.ErrInvalidHolderBindingRequest, err.Error())
+	}
+	if len(proof.Responses) != len(bases) {
+		return fmt.Errorf("%w: %d responses, %d expected", model.ErrInvalidHolderBindingRequest, len(proof.Responses), len(bases))
+	}
+
+	challenge := holderBindingChallenge(curve, commitment, proof.Commitment, issuerNonce)
+	if err := proof.Verify(bases, commitment, challenge); err != nil {
+		return fmt.Errorf("%w: %s", model.ErrInvalidHolderBindingRequest, err.Error())
+	}
+
+	return nil
+}
+
+// holderBindingBases Retrieve the generators of the blinding factor and of the holder secret.
+func holderBindingBases(lib *bbs.BBSLib, issuerPublicKey []byte, messagesCount int) ([]*ml.G1, error) {
+	// at least the marker and the secret
+	if messagesCount < 2 {
+		return nil, fmt.Errorf("%w: %d messages", model.ErrInvalidHolderBindingRequest, messagesCount)
+	}
+
+	publicKey, err := lib.UnmarshalPublicKey(issuerPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+	generators, err := publicKey.ToPublicKeyWithGenerators(messagesCount)
+	if err != nil {
+		return nil, fmt.Errorf("build generators from public key: %w", err)
+	}
+
+	return []*ml.G1{generators.H0, generators.H[messagesCount-1]}, nil
+}
+
+// holderBindingChallenge Compute the Fiat-Shamir challenge of the proof of knowledge of a holder commitment.
+func holderBindingChallenge(curve *ml.Curve, commitment, proofCommitment *ml.G1, issuerNonce []byte) *ml.Zr {
+	data := append(commitment.Bytes(), proofCommitment.Bytes()...)
+	data = append(data, issuerNonce...)
+
+	return bbs.FrFromOKM(data, curve)
+}
+
+// unblindSignature Add the blinding factor of the holder to a signature created by SignBlind.
+//
+//	signature []byte
+//	blinding []byte
+//
+// returns:
+//
+//	signature []byte
+//	err error
+func unblindSignature(signature, blinding []byte) ([]byte, error) {
+	curve := newBBSCurve()
+	parsed, err := bbs.NewBBSLib(curve).ParseSignature(signature)
+	if err != nil {
+		return nil, fmt.Errorf("parse signature: %w", err)
+	}
+
+	parsed.S = parsed.S.Plus(curve.NewZrFromBytes(blinding))
+	parsed.S.Mod(curve.GroupOrder)
+
+	return parsed.ToBytes()
+}
+
+// isHolderBound Check whether a derived proof discloses the holder binding marker, besides the disclosed statements.
+// The marker is the second to last message, and the largest disclosed index.
+//
+//	proofValue []byte The derived proof.
+//	disclosedStatements int The number of disclosed statements.
+func isHolderBound(proofValue []byte, disclosedStatements int) bool {
+	// ParsePoKPayload reverses the bit vector of the revealed messages in place
+	payload, err := bbs.ParsePoKPayload(bytes.Clone(proofValue))
+	if err != nil || len(payload.Revealed) != disclosedStatements+1 {
+		return false
+	}
+
+	return payload.Revealed[len(payload.Revealed)-1] == payload.MessagesCount-2
+}
@@ -0,0 +1,205 @@
+package core_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"testing"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type HolderBindingTestSuite struct {
+	suite.Suite
+	options    *model.SignatureSuiteOptions
+	publicKey  []byte
+	privateKey []byte
+	credential model.JsonLdCredentialNoProof
+	frame      model.JsonLdFrame
+}
+
+func TestHolderBindingTestSuite(t *testing.T) {
+	suite.Run(t, new(HolderBindingTestSuite))
+}
+
+func (s *HolderBindingTestSuite) SetupTest() {
+	s.options = offlineOptions(s.T())
+	s.publicKey = benchmarkPublicKey(s.T())
+	s.privateKey = benchmarkPrivateKey(s.T())
+
+	credentialBytes, err := os.ReadFile("testdata/unsignedCredential.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(credentialBytes, &s.credential))
+	frameBytes, err := os.ReadFile("testdata/frame.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(frameBytes, &s.frame))
+}
+
+// issue Run the issuance protocol of a credential bound to the secret.
+func (s *HolderBindingTestSuite) issue(secret model.HolderSecret) model.JsonLdCredential {
+	return issueHolderBound(s.T(), s.options, s.credential, s.publicKey, s.privateKey, secret)
+}
+
+// issueHolderBound Run the issuance protocol of a credential bound to the secret, between an issuer and a holder.
+func issueHolderBound(
+	t testing.TB,
+	options *model.SignatureSuiteOptions,
+	unsigned model.JsonLdCredentialNoProof,
+	publicKey, privateKey []byte,
+	secret model.HolderSecret,
+) model.JsonLdCredential {
+	ctx := context.Background()
+	issuer := core.NewSignatureSuite2020(publicKey, privateKey, options)
+	issuerNonce := []byte("issuer nonce")
+
+	messagesCount, err := issuer.PrepareHolderBinding(ctx, unsigned)
+	require.NoError(t, err)
+	request, blinding, err := core.NewHolderBindingRequest(secret, publicKey, messagesCount, issuerNonce)
+	require.NoError(t, err)
+
+	blinded, _, err := issuer.SignHolderBound(ctx, unsigned, request, issuerNonce)
+	require.NoError(t, err)
+
+	holder := core.NewSignatureSuite2020(publicKey, nil, options)
+	credential, err := holder.CompleteHolderBinding(ctx, blinded, secret, blinding)
+	require.NoError(t, err)
+
+	// the credential cannot be verified without the secret
+	require.False(t, holder.Verify(credential).Success)
+
+	return credential
+}
+
+func (s *HolderBindingTestSuite) TestDeriveAndVerify() {
+	ctx := context.Background()
+	secret, err := core.NewHolderSecret()
+	s.NoError(err)
+	credential := s.issue(secret)
+
+	proofSuite := core.NewSignatureProofSuite2020(s.publicKey, s.options)
+	challenge := []byte("verifier challenge")
+	derived, err := proofSuite.DeriveProofWithOptions(ctx, credential, s.frame, challenge, &model.DeriveProofOptions{HolderSecret: secret})
+	s.Require().NoError(err)
+
+	result := proofSuite.VerifyProofWithOptions(derived, &model.VerifyProofOptions{RequireHolderBinding: true})
+	s.True(result.Success, result.Error)
+
+	// the proof is bound to the challenge
+	result = proofSuite.VerifyProof(withNonce(derived, []byte("other challenge")))
+	s.False(result.Success)
+
+	// a proof cannot be derived without the signed secret
+	otherSecret, err := core.NewHolderSecret()
+	s.NoError(err)
+	_, err = proofSuite.DeriveProofWithOptions(ctx, credential, s.frame, challenge, &model.DeriveProofOptions{HolderSecret: otherSecret})
+	s.Error(err)
+	_, err = proofSuite.DeriveProofContext(ctx, credential, s.frame, challenge)
+	s.Error(err)
+}
+
+func (s *HolderBindingTestSuite) TestIsHolderBound() {
+	ctx := context.Background()
+	secret, err := core.NewHolderSecret()
+	s.Require().NoError(err)
+	otherSecret, err := core.NewHolderSecret()
+	s.Require().NoError(err)
+	holder := core.NewSignatureSuite2020(s.publicKey, nil, s.options)
+
+	bound, err := holder.IsHolderBound(ctx, s.issue(secret), secret)
+	s.NoError(err)
+	s.True(bound)
+
+	signed, _, err := core.NewSignatureSuite2020(s.publicKey, s.privateKey, s.options).Sign(s.credential)
+	s.Require().NoError(err)
+	bound, err = holder.IsHolderBound(ctx, signed, secret)
+	s.NoError(err)
+	s.False(bound)
+
+	// a credential bound to another secret, or a tampered one, is not taken for a bound credential
+	_, err = holder.IsHolderBound(ctx, s.issue(otherSecret), secret)
+	s.Error(err)
+	signed[c.CredentialFieldIssuanceDate] = "2020-01-01T00:00:00Z"
+	_, err = holder.IsHolderBound(ctx, signed, secret)
+	s.Error(err)
+}
+
+func (s *HolderBindingTestSuite) TestBindingRequired() {
+	ctx := context.Background()
+	signed, _, err := core.NewSignatureSuite2020(s.publicKey, s.privateKey, s.options).Sign(s.credential)
+	s.NoError(err)
+
+	proofSuite := core.NewSignatureProofSuite2020(s.publicKey, s.options)
+	derived, err := proofSuite.DeriveProofContext(ctx, signed, s.frame, []byte("verifier challenge"))
+	s.Require().NoError(err)
+
+	result := proofSuite.VerifyProof(derived)
+	s.True(result.Success, result.Error)
+
+	result = proofSuite.VerifyProofWithOptions(derived, &model.VerifyProofOptions{RequireHolderBinding: true})
+	s.False(result.Success)
+	s.ErrorIs(result.Error, model.ErrHolderBindingRequired)
+}
+
+func (s *HolderBindingTestSuite) TestInvalidRequest() {
+	ctx := context.Background()
+	issuer := core.NewSignatureSuite2020(s.publicKey, s.privateKey, s.options)
+	secret, err := core.NewHolderSecret()
+	s.NoError(err)
+
+	messagesCount, err := issuer.PrepareHolderBinding(ctx, s.credential)
+	s.NoError(err)
+	request, _, err := core.NewHolderBindingRequest(secret, s.publicKey, messagesCount, []byte("issuer nonce"))
+	s.NoError(err)
+
+	// proof bound to another nonce
+	_, _, err = issuer.SignHolderBound(ctx, s.credential, request, []byte("other nonce"))
+	s.ErrorIs(err, model.ErrInvalidHolderBindingRequest)
+
+	// commitment for another credential
+	request, _, err = core.NewHolderBindingRequest(secret, s.publicKey, messagesCount+1, []byte("issuer nonce"))
+	s.NoError(err)
+	_, _, err = issuer.SignHolderBound(ctx, s.credential, request, []byte("issuer nonce"))
+	s.ErrorIs(err, model.ErrInvalidHolderBindingRequest)
+
+	// signer without blind signing
+	issuer, err = core.NewSignatureSuite2020WithSigner(ctx, plainSigner{core.NewInMemorySigner(s.publicKey, s.privateKey)}, s.options)
+	s.NoError(err)
+	_, _, err = issuer.SignHolderBound(ctx, s.credential, request, []byte("issuer nonce"))
+	s.ErrorIs(err, model.ErrBlindSigningUnsupported)
+}
+
+// withNonce Replace the nonce of a derived proof.
+func withNonce(derived model.JsonLdCredential, nonce []byte) model.JsonLdCredential {
+	proof := derived[c.CredentialFieldProof].(model.JsonLdProof)
+	proof[c.CredentialFieldNonce] = base64.StdEncoding.EncodeToString(nonce)
+
+	return derived
+}
+
+// A plainSigner hides the optional methods of a signer.
+type plainSigner struct {
+	model.Signer
+}
+
+// offlineOptions Return the test options, with a copy of the security v1 context imported by the security v2 context,
+// so that proofs can be derived without network access.
+func offlineOptions(t testing.TB) *model.SignatureSuiteOptions {
+	var contextSecurityV1 map[string]interface{}
+	contextBytes, err := os.ReadFile("testdata/securityV1Context.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(contextBytes, &contextSecurityV1); err != nil {
+		t.Fatal(err)
+	}
+
+	options := benchmarkOptions(t)
+	options.Contexts["https://w3id.org/security/v1"] = contextSecurityV1
+
+	return options
+}
@@ -0,0 +1,197 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+)
+
+const (
+	// SignerPathSign is the path of the signing endpoint served by the SignerHandler.
+	SignerPathSign = "/sign"
+	// SignerPathPublicKey is the path of the public key endpoint served by the SignerHandler.
+	SignerPathPublicKey = "/public-key"
+
+	// maxSignerRequestBytes is the maximum size of a request accepted by the SignerHandler.
+	maxSignerRequestBytes = 16 << 20
+	// maxSignerResponseBytes is the maximum size of a response read by the HTTPSigner.
+	maxSignerResponseBytes = 1 << 20
+)
+
+// signRequest is the body of a request to the signing endpoint. The messages are base64 encoded in JSON.
+type signRequest struct {
+	Messages [][]byte `json:"messages"`
+}
+
+// signResponse is the body of a successful response of the signing endpoint.
+type signResponse struct {
+	Signature []byte `json:"signature"`
+}
+
+// publicKeyResponse is the body of a successful response of the public key endpoint.
+type publicKeyResponse struct {
+	PublicKey []byte `json:"publicKey"`
+}
+
+// errorResponse is the body of a failed response of the SignerHandler.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// An HTTPSigner delegates the signatures to a remote signing service, e.g. a service fronting a KMS or an HSM.
+// The service must expose the endpoints served by the SignerHandler.
+// An HTTPSigner is safe for concurrent use.
+type HTTPSigner struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPSigner initializes and returns HTTPSigner.
+//
+//	baseURL string The URL of the signing service, e.g. "https://signer.example.com/keys/issuer-1".
+//	client *http.Client nullable, if not provided http.DefaultClient will be used
+func NewHTTPSigner(baseURL string, client *http.Client) *HTTPSigner {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &HTTPSigner{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  client,
+	}
+}
+
+// Sign Request a BBS+ signature over a vector of messages to the signing service.
+//
+//	ctx context.Context
+//	messages [][]byte The messages to sign.
+//
+// returns:
+//
+//	signature []byte
+//	err error
+func (s *HTTPSigner) Sign(ctx context.Context, messages [][]byte) ([]byte, error) {
+	body, err := json.Marshal(signRequest{Messages: messages})
+	if err != nil {
+		return nil, err
+	}
+
+	var response signResponse
+	if err := s.do(ctx, http.MethodPost, SignerPathSign, body, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Signature, nil
+}
+
+// PublicKey Retrieve the BBS+ public key from the signing service.
+//
+//	ctx context.Context
+//
+// returns:
+//
+//	publicKey []byte
+//	err error
+func (s *HTTPSigner) PublicKey(ctx context.Context) ([]byte, error) {
+	var response publicKeyResponse
+	if err := s.do(ctx, http.MethodGet, SignerPathPublicKey, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response.PublicKey, nil
+}
+
+// do Call an endpoint of the signing service and decode its response.
+func (s *HTTPSigner) do(ctx context.Context, method, path string, body []byte, response interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("remote signer: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		var failure errorResponse
+		if err := json.NewDecoder(io.LimitReader(res.Body, maxSignerResponseBytes)).Decode(&failure); err != nil || failure.Error == "" {
+			return fmt.Errorf("remote signer: unexpected status %d", res.StatusCode)
+		}
+
+		return fmt.Errorf("remote signer: %s", failure.Error)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxSignerResponseBytes)).Decode(response); err != nil {
+		return fmt.Errorf("remote signer: invalid response: %w", err)
+	}
+
+	return nil
+}
+
+// A SignerHandler exposes a Signer over HTTP, with the endpoints expected by the HTTPSigner:
+//   - POST /sign {"messages": [base64...]} -> {"signature": base64}
+//   - GET /public-key -> {"publicKey": base64}
+//
+// It is a stand-in for a remote signing service, meant for tests and local development:
+// it performs no authentication.
+type SignerHandler struct {
+	signer model.Signer
+}
+
+// NewSignerHandler initializes and returns SignerHandler.
+//
+//	signer model.Signer The signer to expose.
+func NewSignerHandler(signer model.Signer) *SignerHandler {
+	return &SignerHandler{
+		signer: signer,
+	}
+}
+
+func (h *SignerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == SignerPathSign && r.Method == http.MethodPost:
+		var request signRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSignerRequestBytes)).Decode(&request); err != nil {
+			writeSignerError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+			return
+		}
+
+		signature, err := h.signer.Sign(r.Context(), request.Messages)
+		if err != nil {
+			writeSignerError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeSignerResponse(w, signResponse{Signature: signature})
+	case r.URL.Path == SignerPathPublicKey && r.Method == http.MethodGet:
+		publicKey, err := h.signer.PublicKey(r.Context())
+		if err != nil {
+			writeSignerError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeSignerResponse(w, publicKeyResponse{PublicKey: publicKey})
+	default:
+		writeSignerError(w, http.StatusNotFound, fmt.Errorf("unknown endpoint %s %s", r.Method, r.URL.Path))
+	}
+}
+
+func writeSignerResponse(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func writeSignerError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(errorResponse{Error: err.Error()})
+}
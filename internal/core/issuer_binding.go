@@ -0,0 +1,142 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+)
+
+// An issuerBinding checks that the verification method of a proof is controlled by the issuer of the credential,
+// and that it designates the key used to verify the proof.
+//
+// Without resolver, the verification method must be the did:key of the issuer, designating the verification key:
+// the other DID methods cannot be checked and fail closed.
+// With a resolver, the verification method is dereferenced: its key must be the verification key,
+// its controller must be the issuer and, when it belongs to another DID,
+// the DID document of the issuer must list it for the proof purpose.
+type issuerBinding struct {
+	resolver   model.DidResolver // nil if DID documents must not be resolved
+	keyEncoder *KeyEncoder
+	skip       bool // true if the binding is not checked
+}
+
+// newIssuerBinding Build the issuer binding check configured by the suite options.
+//
+//	options *model.SignatureSuiteOptions nullable
+func newIssuerBinding(options *model.SignatureSuiteOptions) *issuerBinding {
+	binding := &issuerBinding{keyEncoder: &KeyEncoder{}}
+	if options != nil {
+		binding.resolver = options.DidResolver
+		binding.skip = options.SkipIssuerBinding
+	}
+
+	return binding
+}
+
+// check Verify that the verification method of a proof is bound to the issuer of the credential.
+//
+//	ctx context.Context
+//	credential model.JsonLdCredential The credential, with or without its proof.
+//	proof model.JsonLdProof The proof to check.
+//	publicKey []byte The key used to verify the proof.
+//
+// returns:
+//
+//	err error wrapping model.ErrIssuerBindingFailed if the verification method is not bound to the issuer,
+//	and model.ErrDidResolverRequired if it cannot be checked without resolver
+func (b *issuerBinding) check(ctx context.Context, credential model.JsonLdCredential, proof model.JsonLdProof, publicKey []byte) error {
+	if b.skip {
+		return nil
+	}
+	issuer, err := credentialIssuerID(credential)
+	if err != nil {
+		return err
+	}
+
+	verificationMethodURL, ok := proof[c.CredentialFieldVerificationMethod].(string)
+	if !ok || verificationMethodURL == "" {
+		return fmt.Errorf("%w: proof doesn't contain field '%s'", model.ErrIssuerBindingFailed, c.CredentialFieldVerificationMethod)
+	}
+	verificationMethodDid, _, _ := strings.Cut(verificationMethodURL, "#")
+
+	proofPurpose, ok := proof[c.CredentialFieldProofPurpose].(string)
+	if !ok || proofPurpose == "" {
+		proofPurpose = c.CredentialProofPurpose
+	}
+
+	if b.resolver == nil {
+		if verificationMethodDid != issuer {
+			return fmt.Errorf("%w: %s does not belong to issuer %s", model.ErrIssuerBindingFailed, verificationMethodURL, issuer)
+		}
+		if !strings.HasPrefix(verificationMethodDid, "did:key:") {
+			return fmt.Errorf("%w: %w: %s", model.ErrIssuerBindingFailed, model.ErrDidResolverRequired, verificationMethodURL)
+		}
+		key, err := b.keyEncoder.DecodeDidKey(verificationMethodDid)
+		if err != nil {
+			return fmt.Errorf("%w: %s", model.ErrIssuerBindingFailed, err.Error())
+		}
+		if !bytes.Equal(key, publicKey) {
+			return fmt.Errorf("%w: %s does not designate the verification key", model.ErrIssuerBindingFailed, verificationMethodURL)
+		}
+		return nil
+	}
+
+	// 1. Dereference the verification method from the DID document it belongs to
+	verificationMethod, key, err := DereferenceVerificationMethod(ctx, b.resolver, verificationMethodURL, proofPurpose)
+	if err != nil {
+		return fmt.Errorf("%w: %w", model.ErrIssuerBindingFailed, err)
+	}
+	if !bytes.Equal(key, publicKey) {
+		return fmt.Errorf("%w: %s does not designate the verification key", model.ErrIssuerBindingFailed, verificationMethodURL)
+	}
+
+	// 2. Check that the issuer controls it
+	if verificationMethod.Controller != "" && verificationMethod.Controller != issuer {
+		return fmt.Errorf("%w: %s is controlled by %s, not by issuer %s", model.ErrIssuerBindingFailed, verificationMethodURL, verificationMethod.Controller, issuer)
+	}
+	if verificationMethodDid == issuer {
+		return nil
+	}
+
+	// 3. A verification method of another DID must be authorized by the DID document of the issuer
+	document, err := b.resolver.Resolve(ctx, issuer)
+	if err != nil {
+		return fmt.Errorf("%w: %w", model.ErrIssuerBindingFailed, err)
+	}
+	for _, entry := range document.Relationship(proofPurpose) {
+		if entry.Reference == verificationMethodURL || (entry.Embedded != nil && entry.Embedded.ID == verificationMethodURL) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s is not listed under %s by issuer %s", model.ErrIssuerBindingFailed, verificationMethodURL, proofPurpose, issuer)
+}
+
+// credentialIssuerID Retrieve the identifier of the issuer of a credential,
+// given either as a string or as an object with an "id".
+//
+//	credential model.JsonLdCredential
+//
+// returns:
+//
+//	issuer string
+//	err error wrapping model.ErrIssuerBindingFailed if the issuer is missing
+func credentialIssuerID(credential model.JsonLdCredential) (string, error) {
+	var issuer string
+	switch value := credential[c.CredentialFieldIssuer].(type) {
+	case string:
+		issuer = value
+	case map[string]interface{}:
+		issuer, _ = value[c.CredentialFieldId].(string)
+	}
+
+	if issuer == "" {
+		return "", fmt.Errorf("%w: credential doesn't disclose its '%s'", model.ErrIssuerBindingFailed, c.CredentialFieldIssuer)
+	}
+
+	return issuer, nil
+}
@@ -0,0 +1,166 @@
+package core_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type IssuerBindingTestSuite struct {
+	suite.Suite
+	options    *model.SignatureSuiteOptions
+	publicKey  []byte
+	privateKey []byte
+}
+
+func TestIssuerBindingTestSuite(t *testing.T) {
+	suite.Run(t, new(IssuerBindingTestSuite))
+}
+
+func (s *IssuerBindingTestSuite) SetupTest() {
+	s.options = benchmarkOptions(s.T())
+	s.publicKey = benchmarkPublicKey(s.T())
+	s.privateKey = benchmarkPrivateKey(s.T())
+}
+
+// signAndVerify Sign the test credential with the issuer and the options, and verify it with the verifier options.
+func (s *IssuerBindingTestSuite) signAndVerify(issuer interface{}, signerOptions, verifierOptions *model.SignatureSuiteOptions) *model.VerificationResult {
+	credential := s.unsignedCredential()
+	if issuer != nil {
+		credential[c.CredentialFieldIssuer] = issuer
+	}
+
+	signedCredential, _, err := core.NewSignatureSuite2020(s.publicKey, s.privateKey, signerOptions).Sign(credential)
+	s.Require().NoError(err)
+
+	return core.NewSignatureSuite2020(s.publicKey, nil, verifierOptions).Verify(signedCredential)
+}
+
+// withDidWeb Copy the test options, adding a verification method and a resolver.
+func (s *IssuerBindingTestSuite) withDidWeb(verificationMethod string, resolver model.DidResolver) *model.SignatureSuiteOptions {
+	options := *s.options
+	options.VerificationMethod = verificationMethod
+	options.DidResolver = resolver
+
+	return &options
+}
+
+func (s *IssuerBindingTestSuite) TestDidKeyIssuer() {
+	// issuer added by the suite
+	result := s.signAndVerify(nil, s.options, s.options)
+	s.True(result.Success, result.Error)
+
+	// issuer object
+	didKey, err := (&core.KeyEncoder{}).CreateDidKey(s.publicKey)
+	s.NoError(err)
+	result = s.signAndVerify(map[string]interface{}{"id": didKey}, s.options, s.options)
+	s.True(result.Success, result.Error)
+}
+
+func (s *IssuerBindingTestSuite) TestIssuerMismatch() {
+	result := s.signAndVerify("did:example:impersonated", s.options, s.options)
+	s.False(result.Success)
+	s.ErrorIs(result.Error, model.ErrIssuerBindingFailed)
+
+	// the DID document of the issuer does not list the did:key of the signer
+	document := s.didWebDocument("did:web:issuer.example.com", s.publicKey)
+	resolver := core.NewMethodDidResolver(map[string]model.DidResolver{
+		c.DidMethodKey: core.NewDidKeyResolver(),
+		c.DidMethodWeb: staticDidResolver{document},
+	})
+	result = s.signAndVerify("did:web:issuer.example.com", s.options, s.withDidWeb("", resolver))
+	s.False(result.Success)
+	s.ErrorIs(result.Error, model.ErrIssuerBindingFailed)
+}
+
+func (s *IssuerBindingTestSuite) TestDidWebIssuer() {
+	did := "did:web:issuer.example.com"
+	resolver := core.NewMethodDidResolver(map[string]model.DidResolver{
+		c.DidMethodWeb: staticDidResolver{s.didWebDocument(did, s.publicKey)},
+	})
+	options := s.withDidWeb(did+"#key-1", resolver)
+
+	// the issuer is taken from the verification method
+	result := s.signAndVerify(nil, options, options)
+	s.True(result.Success, result.Error)
+
+	// the DID document publishes another key
+	otherKey, err := hex.DecodeString("87fae47132975f345b38fafd53149f7a009b89dd94fdc54d5d051a29e185ed4870acc2453fbd2e307d1543dfb7fbfdb30cf0008df96c75e2e43975b7f92864b4bc6e3f2f1495748d80a36691f6feaeb8fe151c1bb35de9bff5ac21ff9e57aebe")
+	s.NoError(err)
+	resolver = core.NewMethodDidResolver(map[string]model.DidResolver{
+		c.DidMethodWeb: staticDidResolver{s.didWebDocument(did, otherKey)},
+	})
+	result = s.signAndVerify(nil, options, s.withDidWeb("", resolver))
+	s.False(result.Success)
+	s.ErrorIs(result.Error, model.ErrIssuerBindingFailed)
+}
+
+func (s *IssuerBindingTestSuite) TestDidWebIssuerWithoutResolver() {
+	did := "did:web:issuer.example.com"
+	options := s.withDidWeb(did+"#key-1", nil)
+
+	// the verification method belongs to the issuer, but its key cannot be checked without resolver
+	result := s.signAndVerify(nil, options, options)
+	s.False(result.Success)
+	s.ErrorIs(result.Error, model.ErrIssuerBindingFailed)
+	s.ErrorIs(result.Error, model.ErrDidResolverRequired)
+	result = s.signAndVerify(nil, options, s.options)
+	s.ErrorIs(result.Error, model.ErrDidResolverRequired)
+}
+
+func (s *IssuerBindingTestSuite) TestSkipIssuerBinding() {
+	skipping := *s.options
+	skipping.SkipIssuerBinding = true
+
+	result := s.signAndVerify("did:example:impersonated", s.options, &skipping)
+	s.True(result.Success, result.Error)
+	options := s.withDidWeb("did:web:issuer.example.com#key-1", nil)
+	result = s.signAndVerify(nil, options, &skipping)
+	s.True(result.Success, result.Error)
+
+	// the derived proofs are not checked either
+	offline := offlineOptions(s.T())
+	offline.VerificationMethod = options.VerificationMethod
+	signed, _, err := core.NewSignatureSuite2020(s.publicKey, s.privateKey, offline).Sign(s.unsignedCredential())
+	s.Require().NoError(err)
+	derived, err := core.NewSignatureProofSuite2020(s.publicKey, offline).DeriveProof(signed, s.frame(), []byte("nonce"))
+	s.Require().NoError(err)
+	result = core.NewSignatureProofSuite2020(s.publicKey, offline).VerifyProof(derived)
+	s.ErrorIs(result.Error, model.ErrDidResolverRequired)
+	offline.SkipIssuerBinding = true
+	result = core.NewSignatureProofSuite2020(s.publicKey, offline).VerifyProof(derived)
+	s.True(result.Success, result.Error)
+}
+
+func (s *IssuerBindingTestSuite) unsignedCredential() model.JsonLdCredentialNoProof {
+	var credential model.JsonLdCredentialNoProof
+	credentialBytes, err := os.ReadFile("testdata/unsignedCredential.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(credentialBytes, &credential))
+
+	return credential
+}
+
+func (s *IssuerBindingTestSuite) frame() model.JsonLdFrame {
+	var frame model.JsonLdFrame
+	frameBytes, err := os.ReadFile("testdata/frame.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(frameBytes, &frame))
+
+	return frame
+}
+
+func (s *IssuerBindingTestSuite) didWebDocument(did string, publicKey []byte) *model.DidDocument {
+	builder, err := core.NewDidWebDocumentBuilder(did, model.DidDocumentKey{PublicKey: publicKey, Fragment: "key-1"})
+	s.Require().NoError(err)
+	document, err := builder.Build()
+	s.Require().NoError(err)
+
+	return document
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"encoding/binary"
 	"fmt"
+	"strings"
 
 	"github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
 	multibase "github.com/multiformats/go-multibase"
@@ -61,3 +62,42 @@ func (e *KeyEncoder) multibaseEncode(blsPublicKey []byte) (string, error) {
 
 	return multibase.Encode(multibase.Base58BTC, append(encodedUvarintBuffer, blsPublicKey...))
 }
+
+// DecodeMultibaseKey Decode a multibase encoded BLS12-381 G2 public key, as found in a did:key or a Multikey.
+//
+//	multibaseKey string example: "zUC73gNPc1EnZmDDjYJzE8Bk89VRhuZPQYXFnSiSUZvX9N1i7N5VtMbJyowDR46rtARHLJYRVf7WMbGLb43s9tfTyKF9KFF22vBjXZRomcwtoQJmMNUSY7tfzyhLEy58dwUz3WD"
+//
+// returns:
+//
+//	blsPublicKey []byte
+//	err error if the key is not a multicodec BLS12-381 G2 public key
+func (e *KeyEncoder) DecodeMultibaseKey(multibaseKey string) ([]byte, error) {
+	_, data, err := multibase.Decode(multibaseKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode multibase key: %w", err)
+	}
+
+	prefix, n := binary.Uvarint(data)
+	if n <= 0 || prefix != constants.MulticodecPrefixBls12_381_g2_pub {
+		return nil, fmt.Errorf("key is not a BLS12-381 G2 public key")
+	}
+
+	return data[n:], nil
+}
+
+// DecodeDidKey Retrieve the bytes of a BLS public key from a did:key.
+//
+//	didKey string example: "did:key:zUC73gNPc1EnZmDDjYJzE8Bk89VRhuZPQYXFnSiSUZvX9N1i7N5VtMbJyowDR46rtARHLJYRVf7WMbGLb43s9tfTyKF9KFF22vBjXZRomcwtoQJmMNUSY7tfzyhLEy58dwUz3WD"
+//
+// returns:
+//
+//	blsPublicKey []byte
+//	err error
+func (e *KeyEncoder) DecodeDidKey(didKey string) ([]byte, error) {
+	key, ok := strings.CutPrefix(didKey, "did:key:")
+	if !ok {
+		return nil, fmt.Errorf("'%s' is not a did:key", didKey)
+	}
+
+	return e.DecodeMultibaseKey(key)
+}
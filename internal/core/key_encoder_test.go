@@ -45,3 +45,17 @@ func (s *KeyEncoderTestSuite) TestCreationOfDidKeyVerificationMethod() {
 	s.NoError(err)
 	s.Equal(expected, actual)
 }
+
+func (s *KeyEncoderTestSuite) TestDecodingOfDidKey() {
+	didKey := "did:key:zUC73gNPc1EnZmDDjYJzE8Bk89VRhuZPQYXFnSiSUZvX9N1i7N5VtMbJyowDR46rtARHLJYRVf7WMbGLb43s9tfTyKF9KFF22vBjXZRomcwtoQJmMNUSY7tfzyhLEy58dwUz3WD"
+	expected, err := hex.DecodeString("87fae47132975f345b38fafd53149f7a009b89dd94fdc54d5d051a29e185ed4870acc2453fbd2e307d1543dfb7fbfdb30cf0008df96c75e2e43975b7f92864b4bc6e3f2f1495748d80a36691f6feaeb8fe151c1bb35de9bff5ac21ff9e57aebe")
+	s.NoError(err)
+
+	actual, err := s.subject.DecodeDidKey(didKey)
+	s.NoError(err)
+	s.Equal(expected, actual)
+
+	// Ed25519 did:key
+	_, err = s.subject.DecodeDidKey("did:key:z6MknntgQWCT8Zs5vpQEVoV2HvsfdYfe7b1LTnM9Lty6fD4e")
+	s.Error(err)
+}
@@ -0,0 +1,462 @@
+package core
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/hyperledger/aries-bbs-go/bbs"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/term"
+)
+
+const (
+	// DefaultScryptN is the default scrypt CPU/memory cost, requiring 128 MiB of memory.
+	DefaultScryptN = 1 << 17
+	// DefaultScryptR is the default scrypt block size.
+	DefaultScryptR = 8
+	// DefaultScryptP is the default scrypt parallelization.
+	DefaultScryptP = 1
+	// DefaultArgon2Time is the default number of argon2id passes.
+	DefaultArgon2Time = 3
+	// DefaultArgon2Memory is the default argon2id memory in KiB (64 MiB).
+	DefaultArgon2Memory = 64 * 1024
+	// DefaultArgon2Threads is the default argon2id parallelism.
+	DefaultArgon2Threads = 4
+
+	keystoreKeyLen  = 32
+	keystoreSaltLen = 32
+
+	// bounds of the KDF parameters, so that a crafted keystore cannot exhaust the memory or the CPU
+	maxScryptN       = 1 << 20
+	maxScryptR       = 32
+	maxScryptP       = 16
+	maxScryptMemory  = 1 << 30 // 128 * N * r bytes
+	maxArgon2Time    = 16
+	maxArgon2Memory  = 4 * 1024 * 1024
+	maxArgon2Threads = 16
+)
+
+// EncryptKey Encrypt a BLS12-381 private key with a key derived from a passphrase.
+// The key pair is checked before the encryption.
+//
+//	publicKey []byte The BBS+ public key.
+//	privateKey []byte The BBS+ private key.
+//	passphrase []byte
+//	options *model.KeystoreOptions nullable
+//
+// returns:
+//
+//	keystore *model.Keystore
+//	err error
+func EncryptKey(publicKey, privateKey, passphrase []byte, options *model.KeystoreOptions) (*model.Keystore, error) {
+	if len(passphrase) == 0 {
+		return nil, model.ErrEmptyPassphrase
+	}
+	if err := checkKeyPair(publicKey, privateKey); err != nil {
+		return nil, err
+	}
+
+	if options == nil {
+		options = &model.KeystoreOptions{}
+	}
+
+	keyEncoder := &KeyEncoder{}
+	didKey, err := keyEncoder.CreateDidKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	keyID := options.KeyID
+	if keyID == "" {
+		keyID, err = keyEncoder.CreateDidKeyVerificationMethod(publicKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	salt := make([]byte, keystoreSaltLen)
+	nonce := make([]byte, 12)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("cannot generate salt: %w", err)
+	}
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("cannot generate nonce: %w", err)
+	}
+
+	keystore := &model.Keystore{
+		Version:   c.KeystoreVersion,
+		KeyID:     keyID,
+		DidKey:    didKey,
+		Created:   time.Now().UTC().Truncate(time.Second),
+		PublicKey: hex.EncodeToString(publicKey),
+		Crypto: model.KeystoreCrypto{
+			KDF:       options.KDF,
+			KDFParams: options.KDFParams,
+			Cipher:    c.KeystoreCipherAES256GCM,
+			Nonce:     hex.EncodeToString(nonce),
+		},
+	}
+	setDefaultKDFParams(&keystore.Crypto)
+	keystore.Crypto.KDFParams.Salt = hex.EncodeToString(salt)
+	keystore.Crypto.KDFParams.KeyLen = keystoreKeyLen
+
+	aead, err := newKeystoreCipher(&keystore.Crypto, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	additionalData, err := keystoreAdditionalData(keystore)
+	if err != nil {
+		return nil, err
+	}
+	keystore.Crypto.Ciphertext = hex.EncodeToString(aead.Seal(nil, nonce, privateKey, additionalData))
+
+	return keystore, nil
+}
+
+// DecryptKey Decrypt the private key of a keystore.
+// The caller should clear the returned private key with ZeroBytes once it is no longer needed.
+//
+//	keystore *model.Keystore
+//	passphrase []byte
+//
+// returns:
+//
+//	publicKey []byte
+//	privateKey []byte
+//	err error model.ErrKeystoreWrongPassphrase if the passphrase is wrong or the keystore has been altered
+func DecryptKey(keystore *model.Keystore, passphrase []byte) ([]byte, []byte, error) {
+	if keystore.Version != c.KeystoreVersion {
+		return nil, nil, fmt.Errorf("unsupported keystore version %d", keystore.Version)
+	}
+	if keystore.Crypto.Cipher != c.KeystoreCipherAES256GCM {
+		return nil, nil, fmt.Errorf("unsupported keystore cipher '%s'", keystore.Crypto.Cipher)
+	}
+
+	publicKey, err := hex.DecodeString(keystore.PublicKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("public key is not in hex: %w", err)
+	}
+	nonce, err := hex.DecodeString(keystore.Crypto.Nonce)
+	if err != nil {
+		return nil, nil, fmt.Errorf("nonce is not in hex: %w", err)
+	}
+	ciphertext, err := hex.DecodeString(keystore.Crypto.Ciphertext)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ciphertext is not in hex: %w", err)
+	}
+
+	aead, err := newKeystoreCipher(&keystore.Crypto, passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, nil, fmt.Errorf("invalid nonce size %d", len(nonce))
+	}
+
+	additionalData, err := keystoreAdditionalData(keystore)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateKey, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, nil, model.ErrKeystoreWrongPassphrase
+	}
+
+	if err := checkKeyPair(publicKey, privateKey); err != nil {
+		ZeroBytes(privateKey)
+		return nil, nil, err
+	}
+
+	return publicKey, privateKey, nil
+}
+
+// SaveKeystore Write a keystore to a new file readable only by its owner.
+// An existing file is never overwritten, so that a key cannot be lost by mistake.
+//
+//	path string
+//	keystore *model.Keystore
+//
+// returns:
+//
+//	err error
+func SaveKeystore(path string, keystore *model.Keystore) error {
+	data, err := json.MarshalIndent(keystore, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// LoadKeystore Read a keystore file.
+//
+//	path string
+//
+// returns:
+//
+//	keystore *model.Keystore
+//	err error
+func LoadKeystore(path string) (*model.Keystore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var keystore model.Keystore
+	if err := json.Unmarshal(data, &keystore); err != nil {
+		return nil, fmt.Errorf("invalid keystore file: %w", err)
+	}
+
+	return &keystore, nil
+}
+
+// ReadPassphrase Read a passphrase from the first line of a reader, e.g. os.Stdin or a file.
+// The reader is not consumed beyond the end of the line, and the line terminator is not part of the passphrase.
+// A terminal is read with its echo disabled, so that the passphrase is not displayed.
+//
+//	r io.Reader
+//
+// returns:
+//
+//	passphrase []byte
+//	err error model.ErrEmptyPassphrase if the line is empty
+func ReadPassphrase(r io.Reader) ([]byte, error) {
+	if f, ok := r.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		passphrase, err := term.ReadPassword(int(f.Fd()))
+		if err != nil {
+			ZeroBytes(passphrase)
+			return nil, err
+		}
+		if len(passphrase) == 0 {
+			return nil, model.ErrEmptyPassphrase
+		}
+
+		return passphrase, nil
+	}
+
+	var passphrase []byte
+	buffer := make([]byte, 1)
+	for {
+		n, err := r.Read(buffer)
+		if n == 1 {
+			if buffer[0] == '\n' {
+				break
+			}
+			passphrase = append(passphrase, buffer[0])
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			ZeroBytes(passphrase)
+			return nil, err
+		}
+	}
+
+	passphrase = bytes.TrimSuffix(passphrase, []byte("\r"))
+	if len(passphrase) == 0 {
+		return nil, model.ErrEmptyPassphrase
+	}
+
+	return passphrase, nil
+}
+
+// PassphraseFromEnv Read a passphrase from an environment variable and remove the variable from the environment,
+// so that it is not inherited by child processes.
+//
+//	name string The name of the environment variable.
+//
+// returns:
+//
+//	passphrase []byte
+//	err error model.ErrEmptyPassphrase if the variable is not set or empty
+func PassphraseFromEnv(name string) ([]byte, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return nil, fmt.Errorf("%w: environment variable %s is not set", model.ErrEmptyPassphrase, name)
+	}
+	if err := os.Unsetenv(name); err != nil {
+		return nil, err
+	}
+
+	return []byte(value), nil
+}
+
+// ZeroBytes Overwrite key material with zeros.
+//
+//	b []byte
+func ZeroBytes(b []byte) {
+	clear(b)
+}
+
+// checkKeyPair Check that a private key matches a public key.
+func checkKeyPair(publicKey, privateKey []byte) error {
+	lib := bbs.NewBBSLib(newBBSCurve())
+
+	privKey, err := lib.UnmarshalPrivateKey(privateKey)
+	if err != nil {
+		return fmt.Errorf("parse private key: %w", err)
+	}
+
+	derivedPublicKey, err := privKey.PublicKey().Marshal()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(derivedPublicKey, publicKey) {
+		return model.ErrKeyPairMismatch
+	}
+
+	return nil
+}
+
+// setDefaultKDFParams Fill in the KDF and its cost parameters when not provided.
+func setDefaultKDFParams(crypto *model.KeystoreCrypto) {
+	if crypto.KDF == "" {
+		crypto.KDF = c.KeystoreKDFScrypt
+	}
+
+	params := &crypto.KDFParams
+	switch crypto.KDF {
+	case c.KeystoreKDFScrypt:
+		if params.N == 0 {
+			params.N = DefaultScryptN
+		}
+		if params.R == 0 {
+			params.R = DefaultScryptR
+		}
+		if params.P == 0 {
+			params.P = DefaultScryptP
+		}
+	case c.KeystoreKDFArgon2id:
+		if params.Time == 0 {
+			params.Time = DefaultArgon2Time
+		}
+		if params.Memory == 0 {
+			params.Memory = DefaultArgon2Memory
+		}
+		if params.Threads == 0 {
+			params.Threads = DefaultArgon2Threads
+		}
+	}
+}
+
+// newKeystoreCipher Derive the encryption key from the passphrase and return the AES-GCM cipher using it.
+// The derived key is cleared once the cipher has been initialized.
+func newKeystoreCipher(crypto *model.KeystoreCrypto, passphrase []byte) (cipher.AEAD, error) {
+	params := crypto.KDFParams
+	if params.KeyLen != keystoreKeyLen {
+		return nil, fmt.Errorf("unsupported key length %d", params.KeyLen)
+	}
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("salt is not in hex: %w", err)
+	}
+
+	if err := checkKDFParams(crypto.KDF, &params); err != nil {
+		return nil, err
+	}
+
+	var key []byte
+	switch crypto.KDF {
+	case c.KeystoreKDFScrypt:
+		key, err = scrypt.Key(passphrase, salt, params.N, params.R, params.P, params.KeyLen)
+		if err != nil {
+			return nil, fmt.Errorf("derive key: %w", err)
+		}
+	case c.KeystoreKDFArgon2id:
+		key = argon2.IDKey(passphrase, salt, params.Time, params.Memory, params.Threads, uint32(params.KeyLen))
+	default:
+		return nil, fmt.Errorf("unsupported KDF '%s'", crypto.KDF)
+	}
+	defer ZeroBytes(key)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// checkKDFParams Check that the cost parameters of a KDF are within their bounds, before deriving any key.
+//
+//	kdf string The KDF, constants.KeystoreKDFScrypt or constants.KeystoreKDFArgon2id.
+//	params *model.KeystoreKDFParams
+//
+// returns:
+//
+//	err error model.ErrKeystoreKDFParams if a parameter is out of range
+func checkKDFParams(kdf string, params *model.KeystoreKDFParams) error {
+	outOfRange := func(name string, value any, low, high int) error {
+		return fmt.Errorf("%w: %s %s=%v not in [%d, %d]", model.ErrKeystoreKDFParams, kdf, name, value, low, high)
+	}
+
+	switch kdf {
+	case c.KeystoreKDFScrypt:
+		// scrypt requires N to be a power of 2 greater than 1
+		if params.N < 2 || params.N > maxScryptN || params.N&(params.N-1) != 0 {
+			return fmt.Errorf("%w: scrypt n=%d is not a power of 2 in [2, %d]", model.ErrKeystoreKDFParams, params.N, maxScryptN)
+		}
+		if params.R < 1 || params.R > maxScryptR {
+			return outOfRange("r", params.R, 1, maxScryptR)
+		}
+		if params.P < 1 || params.P > maxScryptP {
+			return outOfRange("p", params.P, 1, maxScryptP)
+		}
+		// computed in uint64, the product overflows an int on 32-bit platforms
+		if 128*uint64(params.N)*uint64(params.R) > maxScryptMemory {
+			return fmt.Errorf("%w: scrypt n=%d and r=%d require more than %d bytes", model.ErrKeystoreKDFParams, params.N, params.R, maxScryptMemory)
+		}
+	case c.KeystoreKDFArgon2id:
+		if params.Time < 1 || params.Time > maxArgon2Time {
+			return outOfRange("time", params.Time, 1, maxArgon2Time)
+		}
+		if params.Threads < 1 || params.Threads > maxArgon2Threads {
+			return outOfRange("threads", params.Threads, 1, maxArgon2Threads)
+		}
+		// argon2id requires at least 8 KiB per thread
+		if params.Memory < 8*uint32(params.Threads) || params.Memory > maxArgon2Memory {
+			return outOfRange("memory", params.Memory, 8*int(params.Threads), maxArgon2Memory)
+		}
+	}
+
+	return nil
+}
+
+// keystoreAdditionalData Serialize the metadata of a keystore, authenticated by the encryption.
+func keystoreAdditionalData(keystore *model.Keystore) ([]byte, error) {
+	return json.Marshal(struct {
+		Version   int       `json:"version"`
+		KeyID     string    `json:"keyId"`
+		DidKey    string    `json:"didKey"`
+		Created   time.Time `json:"created"`
+		PublicKey string    `json:"publicKey"`
+	}{
+		Version:   keystore.Version,
+		KeyID:     keystore.KeyID,
+		DidKey:    keystore.DidKey,
+		Created:   keystore.Created,
+		PublicKey: keystore.PublicKey,
+	})
+}
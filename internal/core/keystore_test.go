@@ -0,0 +1,218 @@
+package core_test
+
+import (
+	"context"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type KeystoreTestSuite struct {
+	suite.Suite
+	publicKey  []byte
+	privateKey []byte
+	passphrase []byte
+}
+
+func TestKeystoreTestSuite(t *testing.T) {
+	suite.Run(t, new(KeystoreTestSuite))
+}
+
+func (s *KeystoreTestSuite) SetupTest() {
+	s.publicKey = benchmarkPublicKey(s.T())
+	s.privateKey = benchmarkPrivateKey(s.T())
+	s.passphrase = []byte("correct horse battery staple")
+}
+
+// lightOptions Return options with low KDF costs, to keep the tests fast.
+func lightOptions(kdf string) *model.KeystoreOptions {
+	return &model.KeystoreOptions{
+		KDF: kdf,
+		KDFParams: model.KeystoreKDFParams{
+			N:       1 << 10,
+			Time:    1,
+			Memory:  1024,
+			Threads: 1,
+		},
+	}
+}
+
+func (s *KeystoreTestSuite) TestSaveAndLoadKeystore() {
+	for _, kdf := range []string{c.KeystoreKDFScrypt, c.KeystoreKDFArgon2id} {
+		s.Run(kdf, func() {
+			keystore, err := core.EncryptKey(s.publicKey, s.privateKey, s.passphrase, lightOptions(kdf))
+			s.NoError(err)
+			s.Equal(kdf, keystore.Crypto.KDF)
+			s.NotContains(keystore.Crypto.Ciphertext, hex.EncodeToString(s.privateKey))
+
+			didKey, err := (&core.KeyEncoder{}).CreateDidKey(s.publicKey)
+			s.NoError(err)
+			s.Equal(didKey, keystore.DidKey)
+			s.True(strings.HasPrefix(keystore.KeyID, didKey+"#"))
+
+			path := filepath.Join(s.T().TempDir(), "issuer.json")
+			s.NoError(core.SaveKeystore(path, keystore))
+
+			info, err := os.Stat(path)
+			s.NoError(err)
+			s.Equal(os.FileMode(0o600), info.Mode().Perm())
+
+			loaded, err := core.LoadKeystore(path)
+			s.NoError(err)
+			s.Equal(keystore, loaded)
+
+			publicKey, privateKey, err := core.DecryptKey(loaded, s.passphrase)
+			s.NoError(err)
+			s.Equal(s.publicKey, publicKey)
+			s.Equal(s.privateKey, privateKey)
+		})
+	}
+}
+
+func (s *KeystoreTestSuite) TestSaveKeystoreDoesNotOverwrite() {
+	keystore, err := core.EncryptKey(s.publicKey, s.privateKey, s.passphrase, lightOptions(c.KeystoreKDFScrypt))
+	s.NoError(err)
+
+	path := filepath.Join(s.T().TempDir(), "issuer.json")
+	s.NoError(core.SaveKeystore(path, keystore))
+	s.ErrorIs(core.SaveKeystore(path, keystore), os.ErrExist)
+}
+
+func (s *KeystoreTestSuite) TestWrongPassphrase() {
+	keystore, err := core.EncryptKey(s.publicKey, s.privateKey, s.passphrase, lightOptions(c.KeystoreKDFScrypt))
+	s.NoError(err)
+
+	_, _, err = core.DecryptKey(keystore, []byte("wrong passphrase"))
+	s.ErrorIs(err, model.ErrKeystoreWrongPassphrase)
+}
+
+func (s *KeystoreTestSuite) TestTamperedMetadata() {
+	keystore, err := core.EncryptKey(s.publicKey, s.privateKey, s.passphrase, lightOptions(c.KeystoreKDFArgon2id))
+	s.NoError(err)
+
+	keystore.KeyID = "did:example:attacker#key-1"
+	_, _, err = core.DecryptKey(keystore, s.passphrase)
+	s.ErrorIs(err, model.ErrKeystoreWrongPassphrase)
+}
+
+func (s *KeystoreTestSuite) TestKDFParamsOutOfRange() {
+	tests := []struct {
+		name   string
+		kdf    string
+		params func(*model.KeystoreKDFParams)
+	}{
+		{"scrypt n too large", c.KeystoreKDFScrypt, func(p *model.KeystoreKDFParams) { p.N = 1 << 21 }},
+		{"scrypt n not a power of 2", c.KeystoreKDFScrypt, func(p *model.KeystoreKDFParams) { p.N = 1000 }},
+		{"scrypt n negative", c.KeystoreKDFScrypt, func(p *model.KeystoreKDFParams) { p.N = -1 << 10 }},
+		{"scrypt r too large", c.KeystoreKDFScrypt, func(p *model.KeystoreKDFParams) { p.R = 1 << 20 }},
+		{"scrypt n and r too much memory", c.KeystoreKDFScrypt, func(p *model.KeystoreKDFParams) { p.N, p.R = 1<<20, 32 }},
+		{"scrypt r negative", c.KeystoreKDFScrypt, func(p *model.KeystoreKDFParams) { p.R = -1 }},
+		{"scrypt p too large", c.KeystoreKDFScrypt, func(p *model.KeystoreKDFParams) { p.P = 1 << 20 }},
+		{"scrypt p negative", c.KeystoreKDFScrypt, func(p *model.KeystoreKDFParams) { p.P = -1 }},
+		{"scrypt memory too large", c.KeystoreKDFScrypt, func(p *model.KeystoreKDFParams) { p.N, p.R = 1<<20, 32 }},
+		{"argon2id time missing", c.KeystoreKDFArgon2id, func(p *model.KeystoreKDFParams) { p.Time = 0 }},
+		{"argon2id time too large", c.KeystoreKDFArgon2id, func(p *model.KeystoreKDFParams) { p.Time = 1 << 20 }},
+		{"argon2id memory too large", c.KeystoreKDFArgon2id, func(p *model.KeystoreKDFParams) { p.Memory = 1 << 30 }},
+		{"argon2id memory too small", c.KeystoreKDFArgon2id, func(p *model.KeystoreKDFParams) { p.Memory = 7 }},
+		{"argon2id threads missing", c.KeystoreKDFArgon2id, func(p *model.KeystoreKDFParams) { p.Threads = 0 }},
+		{"argon2id threads too large", c.KeystoreKDFArgon2id, func(p *model.KeystoreKDFParams) { p.Threads = 255 }},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			// a crafted keystore is rejected before deriving the key
+			keystore, err := core.EncryptKey(s.publicKey, s.privateKey, s.passphrase, lightOptions(test.kdf))
+			s.Require().NoError(err)
+			test.params(&keystore.Crypto.KDFParams)
+			_, _, err = core.DecryptKey(keystore, s.passphrase)
+			s.ErrorIs(err, model.ErrKeystoreKDFParams)
+
+			// and so is the encryption with out of range costs, whose zero values are not replaced by defaults
+			options := lightOptions(test.kdf)
+			options.KDFParams.R, options.KDFParams.P = 1, 1
+			test.params(&options.KDFParams)
+			if options.KDFParams.Time == 0 || options.KDFParams.Threads == 0 {
+				return
+			}
+			_, err = core.EncryptKey(s.publicKey, s.privateKey, s.passphrase, options)
+			s.ErrorIs(err, model.ErrKeystoreKDFParams)
+		})
+	}
+}
+
+func (s *KeystoreTestSuite) TestKeyPairMismatch() {
+	otherPrivateKey, _ := hex.DecodeString("4b47459199b0c2210de9d28c1412551c28c57caae60872aa677bc9af2038d22b")
+
+	_, err := core.EncryptKey(s.publicKey, otherPrivateKey, s.passphrase, lightOptions(c.KeystoreKDFScrypt))
+	s.ErrorIs(err, model.ErrKeyPairMismatch)
+}
+
+func (s *KeystoreTestSuite) TestReadPassphrase() {
+	reader := strings.NewReader("secret\r\nnext line")
+
+	passphrase, err := core.ReadPassphrase(reader)
+	s.NoError(err)
+	s.Equal([]byte("secret"), passphrase)
+
+	// the reader is not consumed beyond the passphrase
+	passphrase, err = core.ReadPassphrase(reader)
+	s.NoError(err)
+	s.Equal([]byte("next line"), passphrase)
+
+	_, err = core.ReadPassphrase(strings.NewReader("\n"))
+	s.ErrorIs(err, model.ErrEmptyPassphrase)
+
+	// a file that is not a terminal is read as any reader
+	r, w, err := os.Pipe()
+	s.Require().NoError(err)
+	defer r.Close()
+	_, err = w.WriteString("piped\n")
+	s.Require().NoError(err)
+	s.Require().NoError(w.Close())
+	passphrase, err = core.ReadPassphrase(r)
+	s.NoError(err)
+	s.Equal([]byte("piped"), passphrase)
+}
+
+func (s *KeystoreTestSuite) TestPassphraseFromEnv() {
+	s.T().Setenv("TEST_KEYSTORE_PASSPHRASE", "secret")
+
+	passphrase, err := core.PassphraseFromEnv("TEST_KEYSTORE_PASSPHRASE")
+	s.NoError(err)
+	s.Equal([]byte("secret"), passphrase)
+
+	_, ok := os.LookupEnv("TEST_KEYSTORE_PASSPHRASE")
+	s.False(ok)
+
+	_, err = core.PassphraseFromEnv("TEST_KEYSTORE_PASSPHRASE")
+	s.ErrorIs(err, model.ErrEmptyPassphrase)
+}
+
+func (s *KeystoreTestSuite) TestSignerFromKeystore() {
+	keystore, err := core.EncryptKey(s.publicKey, s.privateKey, s.passphrase, lightOptions(c.KeystoreKDFScrypt))
+	s.NoError(err)
+
+	signer, err := core.NewInMemorySignerFromKeystore(keystore, s.passphrase)
+	s.NoError(err)
+
+	messages := [][]byte{[]byte("message 1"), []byte("message 2")}
+	_, err = signer.Sign(context.Background(), messages)
+	s.NoError(err)
+
+	signer.Destroy()
+	_, err = signer.Sign(context.Background(), messages)
+	s.Error(err)
+}
+
+func (s *KeystoreTestSuite) TestZeroBytes() {
+	key := []byte{1, 2, 3}
+	core.ZeroBytes(key)
+	s.Equal([]byte{0, 0, 0}, key)
+}
@@ -0,0 +1,460 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+
+	ml "github.com/IBM/mathlib"
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/hyperledger/aries-bbs-go/bbs"
+)
+
+// The proofs of knowledge of a linked proof are generated with a single challenge, computed over the commitments
+// of all the proofs, the links and the nonce of the verifier. The linked messages are committed with the same blinding factor
+// in every proof, so that their responses, blinding - challenge * message, are equal if and only if the messages are.
+//
+// Only the holder secret can be linked: it is identified by the disclosed holder binding marker that precedes it,
+// whereas a hidden statement is a hash of a whole N-Quad, which does not show the verifier which predicate it uses.
+
+// DeriveLinkedProof Derive proofs for several signed credentials, possibly from different issuers,
+// proving that undisclosed claims are equal without disclosing them.
+//
+//	ctx context.Context
+//	credentials []model.LinkedCredential The signed credentials, with their frames. Only the first proof of each credential is used.
+//	claims []string The claims to link: model.LinkedClaimHolderSecret, linking the credentials given with a holder secret,
+//	at least two.
+//	nonceBytes []byte The challenge of the verifier.
+//
+// returns:
+//
+//	proof *model.LinkedProof
+//	err error
+func (s *SignatureProofSuite2020) DeriveLinkedProof(
+	ctx context.Context,
+	credentials []model.LinkedCredential,
+	claims []string,
+	nonceBytes []byte,
+) (*model.LinkedProof, error) {
+	// 0. Check the request
+	if len(nonceBytes) == 0 {
+		return nil, fmt.Errorf("Nonce has not been supplied by the verifier.")
+	}
+	if len(credentials) < 2 || len(claims) == 0 {
+		return nil, fmt.Errorf("a linked proof requires at least two credentials and one claim")
+	}
+	if err := requireBBSPlus(s.scheme, "linked proofs"); err != nil {
+		return nil, err
+	}
+
+	// 1. Frame the credentials and compute the messages to disclose
+	proofs := make([]model.JsonLdProof, len(credentials))
+	derivations := make([]*derivation, len(credentials))
+	for i, credential := range credentials {
+		credWithoutProofs, supportedProofs, err := s.getSupportedProofs(ctx, credential.Credential)
+		if err != nil {
+			return nil, err
+		}
+		if len(supportedProofs) == 0 {
+			return nil, fmt.Errorf("credentials[%d]: no proof can be used to derive a proof with this suite", i)
+		}
+
+		proofs[i] = supportedProofs[0]
+		framedCredential, err := s.frameCredential(ctx, credWithoutProofs, credential.Frame)
+		if err != nil {
+			return nil, fmt.Errorf("credentials[%d]: %w", i, err)
+		}
+		derivations[i], err = s.prepareDerivation(ctx, credWithoutProofs, framedCredential, proofs[i], credential.HolderSecret)
+		if err != nil {
+			return nil, fmt.Errorf("credentials[%d]: %w", i, err)
+		}
+	}
+
+	// 2. Locate the linked messages and give them a shared blinding factor
+	curve := newBBSCurve()
+	lib := bbs.NewBBSLib(curve)
+	links := make([]model.ClaimLink, len(claims))
+	blindings := make([]map[int]*ml.Zr, len(credentials))
+	for i := range blindings {
+		blindings[i] = make(map[int]*ml.Zr)
+	}
+	for l, claim := range claims {
+		if claim != model.LinkedClaimHolderSecret {
+			return nil, fmt.Errorf("%w: '%s'", model.ErrLinkedClaimUnsupported, claim)
+		}
+		blinding, err := randomZr(curve, s.rand)
+		if err != nil {
+			return nil, err
+		}
+
+		link := model.ClaimLink{Claim: claim}
+		for i, derivation := range derivations {
+			if credentials[i].HolderSecret == nil {
+				continue
+			}
+			index, err := linkedMessageIndex(derivation)
+			if err != nil {
+				return nil, fmt.Errorf("credentials[%d]: %w", i, err)
+			}
+			if len(link.Credentials) > 0 {
+				first := derivations[link.Credentials[0]].messages[link.Messages[0]]
+				if !derivation.messages[index].FR.Equals(first.FR) {
+					return nil, fmt.Errorf("%w: '%s' differs in credentials[%d]", model.ErrLinkedClaimsNotEqual, claim, i)
+				}
+			}
+
+			link.Credentials = append(link.Credentials, i)
+			link.Messages = append(link.Messages, index)
+			blindings[i][index] = blinding
+		}
+		if len(link.Credentials) < 2 {
+			return nil, fmt.Errorf("%w: fewer than two credentials are bound to a holder secret", model.ErrLinkedClaimNotFound)
+		}
+		links[l] = link
+	}
+
+	// 3. Commit to the proofs of knowledge of the signatures
+	committed := make([]*proofOfKnowledge, len(credentials))
+	challengeBytes := make([]byte, 0)
+	for i, derivation := range derivations {
+		publicKey := credentials[i].PublicKey
+		if publicKey == nil {
+			publicKey = s.publicKey
+		}
+		generators, err := publicKeyGenerators(lib, publicKey, len(derivation.messages))
+		if err != nil {
+			return nil, fmt.Errorf("credentials[%d]: %w", i, err)
+		}
+
+		committed[i], err = newProofOfKnowledge(curve, lib, s.rand, generators, derivation.signature, derivation.messages, derivation.revealed, blindings[i])
+		if err != nil {
+			return nil, fmt.Errorf("credentials[%d]: %w", i, err)
+		}
+		challengeBytes = append(challengeBytes, committed[i].ToBytes()...)
+	}
+
+	// 4. Generate the proofs with the shared challenge, and embed them in the framed credentials
+	challengeBytes = append(challengeBytes, linksChallengeBytes(links)...)
+	challenge := proofOfKnowledgeChallenge(curve, challengeBytes, nonceBytes)
+	linked := &model.LinkedProof{
+		Credentials: make([]model.JsonLdCredential, len(credentials)),
+		Links:       links,
+	}
+	for i, derivation := range derivations {
+		proofValue, err := proofOfKnowledgeValue(committed[i].GenerateProof(challenge), len(derivation.messages), derivation.revealed)
+		if err != nil {
+			return nil, fmt.Errorf("credentials[%d]: %w", i, err)
+		}
+
+		derivation.framedCredential[c.CredentialFieldProof] = newDerivedProof(proofs[i], nonceBytes, proofValue)
+		linked.Credentials[i] = derivation.framedCredential
+	}
+
+	return linked, nil
+}
+
+// VerifyLinkedProof Verify the derived proofs of a linked proof, and the equality of their linked messages.
+// The links are chosen by the holder: only the links required by the verifier are trusted.
+//
+//	ctx context.Context
+//	linkedProof *model.LinkedProof
+//	publicKeys [][]byte The keys of the issuers, in the order of the credentials. Missing or nil keys are replaced by the key of the suite.
+//	required []model.RequiredLink The claims that must be linked, at least one.
+//	options *model.VerifyProofOptions nullable
+//
+// returns:
+//
+//	result *model.VerificationResult failing with model.ErrLinkedClaimRequired if a required link is missing
+func (s *SignatureProofSuite2020) VerifyLinkedProof(
+	ctx context.Context,
+	linkedProof *model.LinkedProof,
+	publicKeys [][]byte,
+	required []model.RequiredLink,
+	options *model.VerifyProofOptions,
+) *model.VerificationResult {
+	result, err := s.verifyLinkedProof(ctx, linkedProof, publicKeys, required, options)
+	if err != nil {
+		return &model.VerificationResult{
+			Success: false,
+			Error:   err,
+		}
+	}
+
+	return result
+}
+
+// A linkedVerification holds a parsed proof of a linked proof.
+type linkedVerification struct {
+	unsignedCredential model.JsonLdCredentialNoProof
+	proof              model.JsonLdProof
+	publicKey          []byte
+	data               *verificationData
+	*parsedProofOfKnowledge
+}
+
+// verifyLinkedProof Verify a linked proof.
+//
+// returns:
+//
+//	result *model.VerificationResult the result of the verification policy
+//	err error the verification error
+func (s *SignatureProofSuite2020) verifyLinkedProof(
+	ctx context.Context,
+	linkedProof *model.LinkedProof,
+	publicKeys [][]byte,
+	required []model.RequiredLink,
+	options *model.VerifyProofOptions,
+) (*model.VerificationResult, error) {
+	if linkedProof == nil || len(linkedProof.Credentials) == 0 {
+		return nil, fmt.Errorf("There were not any provided proofs that can be verified with this suite.")
+	}
+	if err := requireBBSPlus(s.scheme, "linked proofs"); err != nil {
+		return nil, err
+	}
+	if err := checkRequiredLinks(required, linkedProof.Links, len(linkedProof.Credentials)); err != nil {
+		return nil, err
+	}
+
+	// 1. Parse the derived proofs, which must share the nonce of the verifier
+	curve := newBBSCurve()
+	lib := bbs.NewBBSLib(curve)
+	verifications := make([]*linkedVerification, len(linkedProof.Credentials))
+	challengeBytes := make([]byte, 0)
+	for i, credential := range linkedProof.Credentials {
+		var publicKey []byte
+		if i < len(publicKeys) {
+			publicKey = publicKeys[i]
+		}
+
+		verification, err := s.parseLinkedCredential(ctx, curve, lib, credential, publicKey, options)
+		if err != nil {
+			return nil, fmt.Errorf("credentials[%d]: %w", i, err)
+		}
+		if i > 0 && !bytes.Equal(verification.data.nonce, verifications[0].data.nonce) {
+			return nil, model.ErrNonceMismatch
+		}
+
+		verifications[i] = verification
+		challengeBytes = append(challengeBytes, verification.challengeBytes()...)
+	}
+
+	// 2. Verify the proofs of knowledge with the shared challenge
+	challengeBytes = append(challengeBytes, linksChallengeBytes(linkedProof.Links)...)
+	challenge := proofOfKnowledgeChallenge(curve, challengeBytes, verifications[0].data.nonce)
+	for i, verification := range verifications {
+		err := verification.verify(challenge)
+		if err != nil {
+			return nil, fmt.Errorf("credentials[%d]: %w", i, err)
+		}
+	}
+
+	// 3. Check that the responses of the linked messages are equal
+	for _, link := range linkedProof.Links {
+		if err := checkClaimLink(curve, link, verifications); err != nil {
+			return nil, err
+		}
+	}
+
+	// 4. Check that the verification methods are controlled by the issuers
+	for i, verification := range verifications {
+		err := s.issuerBinding.check(ctx, verification.unsignedCredential, verification.proof, verification.publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("credentials[%d]: %w", i, err)
+		}
+	}
+
+	// 5. Check that the nonce has been issued by the verifier and consume it
+	if options != nil && options.NonceChecker != nil {
+		err := options.NonceChecker.ConsumeNonce(options.SessionID, verifications[0].data.nonce)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 6. Evaluate the verification policy on every disclosed credential
+	for i, verification := range verifications {
+		result := s.policyEngine.apply(linkedProof.Credentials[i], verification.publicKey)
+		if !result.Success {
+			return result, nil
+		}
+	}
+
+	return &model.VerificationResult{
+		Success: true,
+	}, nil
+}
+
+// parseLinkedCredential Parse the derived proof of a credential of a linked proof.
+//
+//	ctx context.Context
+//	curve *ml.Curve
+//	lib *bbs.BBSLib The library of the curve.
+//	credential model.JsonLdCredential The framed credential together with its proof.
+//	publicKey []byte nil for the key of the suite
+//	options *model.VerifyProofOptions nullable
+//
+// returns:
+//
+//	verification *linkedVerification
+//	err error
+func (s *SignatureProofSuite2020) parseLinkedCredential(
+	ctx context.Context,
+	curve *ml.Curve,
+	lib *bbs.BBSLib,
+	credential model.JsonLdCredential,
+	publicKey []byte,
+	options *model.VerifyProofOptions,
+) (*linkedVerification, error) {
+	if publicKey == nil {
+		publicKey = s.publicKey
+	}
+
+	credentialCopy := deepCopyMap(credential)
+	proofs, err := s.getDerivedProofs(credentialCopy)
+	if err != nil {
+		return nil, err
+	}
+	if len(proofs) != 1 {
+		return nil, fmt.Errorf("expected one derived proof, got %d", len(proofs))
+	}
+
+	unsignedCredential := credentialCopy
+	delete(unsignedCredential, c.CredentialFieldProof)
+	credentialStatements, err := s.createRevealedDocumentData(ctx, unsignedCredential)
+	if err != nil {
+		return nil, err
+	}
+	data, err := s.prepareVerification(ctx, proofs[0], credentialStatements, options)
+	if err != nil {
+		return nil, err
+	}
+	if len(data.predicates) > 0 || data.pseudonym != nil {
+		return nil, fmt.Errorf("linked proofs do not support predicate proofs nor pseudonyms")
+	}
+
+	parsed, err := parseProofOfKnowledge(curve, lib, publicKey, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &linkedVerification{
+		unsignedCredential:     unsignedCredential,
+		proof:                  proofs[0],
+		publicKey:              publicKey,
+		data:                   data,
+		parsedProofOfKnowledge: parsed,
+	}, nil
+}
+
+// checkRequiredLinks Check that the links of a linked proof include the links required by the verifier.
+// A required link is proven by a link of the same claim covering at least the required credentials.
+//
+//	required []model.RequiredLink
+//	links []model.ClaimLink The links of the linked proof.
+//	credentialsCount int
+//
+// returns:
+//
+//	err error wrapping model.ErrLinkedClaimRequired
+func checkRequiredLinks(required []model.RequiredLink, links []model.ClaimLink, credentialsCount int) error {
+	if len(required) == 0 {
+		return fmt.Errorf("%w: the verifier must require at least one link", model.ErrLinkedClaimRequired)
+	}
+
+	for _, link := range required {
+		if link.Claim != model.LinkedClaimHolderSecret {
+			return fmt.Errorf("%w: %w: '%s'", model.ErrLinkedClaimRequired, model.ErrLinkedClaimUnsupported, link.Claim)
+		}
+		credentials := link.Credentials
+		if len(credentials) == 0 {
+			credentials = make([]int, credentialsCount)
+			for i := range credentials {
+				credentials[i] = i
+			}
+		}
+		for _, credential := range credentials {
+			if credential < 0 || credential >= credentialsCount {
+				return fmt.Errorf("%w: '%s' required in credentials[%d], only %d credentials", model.ErrLinkedClaimRequired, link.Claim, credential, credentialsCount)
+			}
+		}
+
+		covered := func(proven model.ClaimLink) bool {
+			return proven.Claim == link.Claim && !slices.ContainsFunc(credentials, func(credential int) bool {
+				return !slices.Contains(proven.Credentials, credential)
+			})
+		}
+		if !slices.ContainsFunc(links, covered) {
+			return fmt.Errorf("%w: '%s' in credentials %v", model.ErrLinkedClaimRequired, link.Claim, credentials)
+		}
+	}
+
+	return nil
+}
+
+// linksChallengeBytes Serialize the links of a linked proof, to compute the challenge.
+func linksChallengeBytes(links []model.ClaimLink) []byte {
+	challengeBytes := make([]byte, 0)
+	for _, link := range links {
+		challengeBytes = append(challengeBytes, fmt.Sprintf("%s %q %v %v\n", c.ClaimLinkMarker, link.Claim, link.Credentials, link.Messages)...)
+	}
+
+	return challengeBytes
+}
+
+// checkClaimLink Check that the responses of the linked messages are equal in the proofs of the linked credentials.
+// Since the proofs share the challenge, equal responses can only be computed from equal messages.
+func checkClaimLink(curve *ml.Curve, link model.ClaimLink, verifications []*linkedVerification) error {
+	if link.Claim != model.LinkedClaimHolderSecret {
+		return fmt.Errorf("%w: '%s'", model.ErrLinkedClaimUnsupported, link.Claim)
+	}
+	if len(link.Credentials) < 2 || len(link.Messages) != len(link.Credentials) {
+		return fmt.Errorf("link '%s': %d messages for %d credentials, at least two", link.Claim, len(link.Messages), len(link.Credentials))
+	}
+
+	var linkedResponse *ml.Zr
+	for j, i := range link.Credentials {
+		if i < 0 || i >= len(verifications) || slices.Contains(link.Credentials[:j], i) {
+			return fmt.Errorf("link '%s': invalid credentials %v", link.Claim, link.Credentials)
+		}
+		verification := verifications[i]
+		index := link.Messages[j]
+		messagesCount := verification.generators.MessagesCount
+		// the holder secret is the last message, following the disclosed holder binding marker
+		marker, bound := verification.revealed[messagesCount-2]
+		if index != messagesCount-1 || !bound || !marker.FR.Equals(bbs.FrFromOKM([]byte(c.HolderBindingMarker), curve)) {
+			return fmt.Errorf("link '%s': credentials[%d] is not bound to a holder secret", link.Claim, i)
+		}
+
+		response, err := hiddenMessageResponse(verification.signatureProof, verification.revealed, index)
+		if err != nil {
+			return fmt.Errorf("link '%s': credentials[%d]: %w", link.Claim, i, err)
+		}
+		if linkedResponse == nil {
+			linkedResponse = response
+		} else if !linkedResponse.Equals(response) {
+			return fmt.Errorf("%w: '%s' differs in credentials[%d]", model.ErrLinkedClaimsNotEqual, link.Claim, i)
+		}
+	}
+
+	return nil
+}
+
+// linkedMessageIndex Retrieve the index of the holder secret, the last message following the holder binding marker.
+//
+//	derivation *derivation
+//
+// returns:
+//
+//	index int
+//	err error wrapping model.ErrLinkedClaimNotFound
+func linkedMessageIndex(derivation *derivation) (int, error) {
+	lastIndex := len(derivation.messages) - 1
+	marker := bbs.FrFromOKM([]byte(c.HolderBindingMarker), newBBSCurve())
+	if lastIndex < 1 || !derivation.messages[lastIndex-1].FR.Equals(marker) {
+		return 0, fmt.Errorf("%w: the credential is not bound to a holder secret", model.ErrLinkedClaimNotFound)
+	}
+
+	return lastIndex, nil
+}
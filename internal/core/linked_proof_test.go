@@ -0,0 +1,191 @@
+package core_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"os"
+	"testing"
+
+	ml "github.com/IBM/mathlib"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/hyperledger/aries-bbs-go/bbs"
+	"github.com/stretchr/testify/suite"
+)
+
+const givenNameIRI = "http://schema.org/givenName"
+
+type LinkedProofTestSuite struct {
+	suite.Suite
+	options *model.SignatureSuiteOptions
+	// the keys of the passport and residence issuers
+	publicKeys  [][]byte
+	privateKeys [][]byte
+	credential  model.JsonLdCredentialNoProof
+	frame       model.JsonLdFrame
+}
+
+func TestLinkedProofTestSuite(t *testing.T) {
+	suite.Run(t, new(LinkedProofTestSuite))
+}
+
+func (s *LinkedProofTestSuite) SetupTest() {
+	s.options = offlineOptions(s.T())
+
+	publicKey, privateKey, err := bbs.NewBBSLib(ml.Curves[ml.BLS12_381_BBS]).GenerateKeyPair(sha256.New, nil)
+	s.Require().NoError(err)
+	publicKeyBytes, err := publicKey.Marshal()
+	s.Require().NoError(err)
+	privateKeyBytes, err := privateKey.Marshal()
+	s.Require().NoError(err)
+	s.publicKeys = [][]byte{benchmarkPublicKey(s.T()), publicKeyBytes}
+	s.privateKeys = [][]byte{benchmarkPrivateKey(s.T()), privateKeyBytes}
+
+	credentialBytes, err := os.ReadFile("testdata/unsignedCredential.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(credentialBytes, &s.credential))
+	frameBytes, err := os.ReadFile("testdata/frame.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(frameBytes, &s.frame))
+}
+
+// unsignedCredential Return a copy of the test credential, with the given claims replaced.
+func (s *LinkedProofTestSuite) unsignedCredential(id string, claims map[string]interface{}) model.JsonLdCredentialNoProof {
+	var credential model.JsonLdCredentialNoProof
+	credentialBytes, err := json.Marshal(s.credential)
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(credentialBytes, &credential))
+
+	credential["id"] = id
+	subject := credential["credentialSubject"].(map[string]interface{})
+	for claim, value := range claims {
+		subject[claim] = value
+	}
+
+	return credential
+}
+
+// sign Sign a credential with the key of an issuer.
+func (s *LinkedProofTestSuite) sign(issuer int, credential model.JsonLdCredentialNoProof) model.JsonLdCredential {
+	signed, _, err := core.NewSignatureSuite2020(s.publicKeys[issuer], s.privateKeys[issuer], s.options).Sign(credential)
+	s.Require().NoError(err)
+
+	return signed
+}
+
+func (s *LinkedProofTestSuite) TestLinkedStatementUnsupported() {
+	ctx := context.Background()
+	proofSuite := core.NewSignatureProofSuite2020(nil, s.options)
+	nonce := []byte("verifier challenge")
+	credentials := []model.LinkedCredential{
+		{
+			Credential: s.sign(0, s.unsignedCredential("https://passport.example/credentials/1", nil)),
+			Frame:      s.frame,
+			PublicKey:  s.publicKeys[0],
+		},
+		{
+			Credential: s.sign(1, s.unsignedCredential("https://residence.example/credentials/2", nil)),
+			Frame:      s.frame,
+			PublicKey:  s.publicKeys[1],
+		},
+	}
+
+	// a hidden statement does not show which predicate it uses
+	_, err := proofSuite.DeriveLinkedProof(ctx, credentials, []string{givenNameIRI}, nonce)
+	s.ErrorIs(err, model.ErrLinkedClaimUnsupported)
+
+	// nor can the verifier require it
+	linked := &model.LinkedProof{
+		Credentials: []model.JsonLdCredential{credentials[0].Credential, credentials[1].Credential},
+		Links:       []model.ClaimLink{{Claim: givenNameIRI, Credentials: []int{0, 1}, Messages: []int{10, 10}}},
+	}
+	result := proofSuite.VerifyLinkedProof(ctx, linked, s.publicKeys, []model.RequiredLink{{Claim: givenNameIRI}}, nil)
+	s.ErrorIs(result.Error, model.ErrLinkedClaimUnsupported)
+}
+
+func (s *LinkedProofTestSuite) TestLinkSecret() {
+	ctx := context.Background()
+	proofSuite := core.NewSignatureProofSuite2020(nil, s.options)
+	nonce := []byte("verifier challenge")
+	secret, err := core.NewHolderSecret()
+	s.NoError(err)
+
+	credentials := make([]model.LinkedCredential, 2)
+	for issuer := range credentials {
+		unsigned := s.unsignedCredential("https://issuer.example/credentials/1", nil)
+		credentials[issuer] = model.LinkedCredential{
+			Credential:   issueHolderBound(s.T(), s.options, unsigned, s.publicKeys[issuer], s.privateKeys[issuer], secret),
+			Frame:        s.frame,
+			PublicKey:    s.publicKeys[issuer],
+			HolderSecret: secret,
+		}
+	}
+
+	linked, err := proofSuite.DeriveLinkedProof(ctx, credentials, []string{model.LinkedClaimHolderSecret}, nonce)
+	s.Require().NoError(err)
+	s.Equal([]int{0, 1}, linked.Links[0].Credentials)
+
+	required := []model.RequiredLink{{Claim: model.LinkedClaimHolderSecret}}
+	result := proofSuite.VerifyLinkedProof(ctx, linked, s.publicKeys, required, &model.VerifyProofOptions{RequireHolderBinding: true})
+	s.True(result.Success, result.Error)
+
+	// the links required by the verifier are stripped
+	forged := *linked
+	forged.Links = nil
+	result = proofSuite.VerifyLinkedProof(ctx, &forged, s.publicKeys, required, nil)
+	s.ErrorIs(result.Error, model.ErrLinkedClaimRequired)
+	result = proofSuite.VerifyLinkedProof(ctx, linked, s.publicKeys, nil, nil)
+	s.ErrorIs(result.Error, model.ErrLinkedClaimRequired)
+	result = proofSuite.VerifyLinkedProof(ctx, linked, s.publicKeys, []model.RequiredLink{{Claim: model.LinkedClaimHolderSecret, Credentials: []int{2}}}, nil)
+	s.ErrorIs(result.Error, model.ErrLinkedClaimRequired)
+
+	// the link secret must be the holder secret, and the links cannot be changed once the proofs are generated
+	for _, link := range []model.ClaimLink{
+		{Claim: model.LinkedClaimHolderSecret, Credentials: []int{0, 1}, Messages: []int{0, 0}},
+		{Claim: model.LinkedClaimHolderSecret, Credentials: []int{0, 0}, Messages: linked.Links[0].Messages},
+		{Claim: model.LinkedClaimHolderSecret, Credentials: []int{0}, Messages: linked.Links[0].Messages[:1]},
+		{Claim: givenNameIRI, Credentials: []int{0, 1}, Messages: linked.Links[0].Messages},
+	} {
+		forged.Links = []model.ClaimLink{link}
+		result = proofSuite.VerifyLinkedProof(ctx, &forged, s.publicKeys, []model.RequiredLink{{Claim: model.LinkedClaimHolderSecret, Credentials: []int{0}}}, nil)
+		s.False(result.Success, link)
+	}
+
+	// keys swapped
+	result = proofSuite.VerifyLinkedProof(ctx, linked, [][]byte{s.publicKeys[1], s.publicKeys[0]}, required, nil)
+	s.False(result.Success)
+
+	// a credential not bound to the holder is presented without being linked
+	unbound := model.LinkedCredential{
+		Credential: s.sign(1, s.unsignedCredential("https://issuer.example/credentials/3", nil)),
+		Frame:      s.frame,
+		PublicKey:  s.publicKeys[1],
+	}
+	linked, err = proofSuite.DeriveLinkedProof(ctx, append(credentials, unbound), []string{model.LinkedClaimHolderSecret}, nonce)
+	s.Require().NoError(err)
+	s.Equal([]int{0, 1}, linked.Links[0].Credentials)
+	publicKeys := append(s.publicKeys, s.publicKeys[1])
+	required = []model.RequiredLink{{Claim: model.LinkedClaimHolderSecret, Credentials: []int{0, 1}}}
+	result = proofSuite.VerifyLinkedProof(ctx, linked, publicKeys, required, nil)
+	s.True(result.Success, result.Error)
+	for _, credentials := range [][]int{{0, 2}, nil} {
+		required = []model.RequiredLink{{Claim: model.LinkedClaimHolderSecret, Credentials: credentials}}
+		result = proofSuite.VerifyLinkedProof(ctx, linked, publicKeys, required, nil)
+		s.ErrorIs(result.Error, model.ErrLinkedClaimRequired)
+	}
+
+	// credentials bound to different secrets
+	otherSecret, err := core.NewHolderSecret()
+	s.NoError(err)
+	unsigned := s.unsignedCredential("https://issuer.example/credentials/2", nil)
+	credentials[1].Credential = issueHolderBound(s.T(), s.options, unsigned, s.publicKeys[1], s.privateKeys[1], otherSecret)
+	credentials[1].HolderSecret = otherSecret
+	_, err = proofSuite.DeriveLinkedProof(ctx, credentials, []string{model.LinkedClaimHolderSecret}, nonce)
+	s.ErrorIs(err, model.ErrLinkedClaimsNotEqual)
+
+	// credential not bound to the holder
+	credentials[1] = model.LinkedCredential{Credential: s.sign(1, unsigned), Frame: s.frame, PublicKey: s.publicKeys[1]}
+	_, err = proofSuite.DeriveLinkedProof(ctx, credentials, []string{model.LinkedClaimHolderSecret}, nonce)
+	s.ErrorIs(err, model.ErrLinkedClaimNotFound)
+}
@@ -0,0 +1,191 @@
+package core
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+)
+
+// DefaultNonceSize is the number of random bytes of a nonce issued by the NonceManager.
+const DefaultNonceSize = 32
+
+// A NonceManager issues random, expiring nonces bound to a verifier session and
+// checks that the nonce embedded in a derived proof is outstanding before consuming it.
+type NonceManager struct {
+	store model.NonceStore
+	ttl   time.Duration
+	clock func() time.Time
+	rand  io.Reader
+}
+
+// NewNonceManager initializes and returns NonceManager.
+//
+//	store model.NonceStore nullable, if not provided an in-memory store will be used
+//	ttl time.Duration The validity of the issued nonces.
+func NewNonceManager(store model.NonceStore, ttl time.Duration) *NonceManager {
+	return NewNonceManagerWithOptions(store, ttl, nil)
+}
+
+// NewNonceManagerWithOptions initializes and returns NonceManager, with a custom clock or source of nonces.
+//
+//	store model.NonceStore nullable, if not provided an in-memory store using the clock of the options will be used
+//	ttl time.Duration The validity of the issued nonces.
+//	options *model.NonceManagerOptions nullable
+func NewNonceManagerWithOptions(store model.NonceStore, ttl time.Duration, options *model.NonceManagerOptions) *NonceManager {
+	clock := time.Now
+	var rng io.Reader = rand.Reader
+	if options != nil {
+		if options.Clock != nil {
+			clock = options.Clock
+		}
+		if options.Rand != nil {
+			rng = &lockedReader{reader: options.Rand}
+		}
+	}
+	if store == nil {
+		store = NewInMemoryNonceStoreWithClock(clock)
+	}
+
+	return &NonceManager{
+		store: store,
+		ttl:   ttl,
+		clock: clock,
+		rand:  rng,
+	}
+}
+
+// Issue Generate a new random nonce bound to a verifier session.
+//
+//	sessionID string The verifier session the nonce is bound to.
+//
+// returns:
+//
+//	nonceBytes []byte The nonce to send to the holder.
+//	err error
+func (m *NonceManager) Issue(sessionID string) ([]byte, error) {
+	nonceBytes := make([]byte, DefaultNonceSize)
+	if _, err := io.ReadFull(m.rand, nonceBytes); err != nil {
+		return nil, fmt.Errorf("cannot generate nonce: %w", err)
+	}
+
+	err := m.store.Save(encodeNonce(nonceBytes), model.NonceRecord{
+		SessionID: sessionID,
+		ExpiresAt: m.clock().Add(m.ttl),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nonceBytes, nil
+}
+
+// ConsumeNonce Check that a nonce is outstanding for a verifier session and mark it as consumed.
+// The nonce is consumed even if the check fails, so that it cannot be replayed.
+//
+//	sessionID string The verifier session the nonce is expected to be bound to.
+//	nonceBytes []byte The nonce embedded in the derived proof.
+//
+// returns:
+//
+//	err error
+func (m *NonceManager) ConsumeNonce(sessionID string, nonceBytes []byte) error {
+	record, err := m.store.Consume(encodeNonce(nonceBytes))
+	if err != nil {
+		return err
+	}
+
+	if record.SessionID != sessionID {
+		return model.ErrNonceSessionMismatch
+	}
+
+	if m.clock().After(record.ExpiresAt) {
+		return model.ErrNonceExpired
+	}
+
+	return nil
+}
+
+// An InMemoryNonceStore keeps the outstanding nonces in memory.
+// Expired nonces are purged every time a new nonce is saved.
+type InMemoryNonceStore struct {
+	mu     sync.Mutex
+	nonces map[string]model.NonceRecord
+	clock  func() time.Time
+}
+
+// NewInMemoryNonceStore initializes and returns InMemoryNonceStore.
+func NewInMemoryNonceStore() *InMemoryNonceStore {
+	return NewInMemoryNonceStoreWithClock(nil)
+}
+
+// NewInMemoryNonceStoreWithClock initializes and returns InMemoryNonceStore, purging the expired nonces with a custom clock.
+//
+//	clock func() time.Time nullable, if not provided time.Now will be used
+func NewInMemoryNonceStoreWithClock(clock func() time.Time) *InMemoryNonceStore {
+	if clock == nil {
+		clock = time.Now
+	}
+
+	return &InMemoryNonceStore{
+		nonces: make(map[string]model.NonceRecord),
+		clock:  clock,
+	}
+}
+
+// Save Store a newly issued nonce.
+//
+//	nonce string The encoded nonce.
+//	record model.NonceRecord
+//
+// returns:
+//
+//	err error
+func (s *InMemoryNonceStore) Save(nonce string, record model.NonceRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock()
+	for key, value := range s.nonces {
+		if now.After(value.ExpiresAt) {
+			delete(s.nonces, key)
+		}
+	}
+
+	if _, ok := s.nonces[nonce]; ok {
+		return fmt.Errorf("nonce already issued")
+	}
+	s.nonces[nonce] = record
+
+	return nil
+}
+
+// Consume Remove a nonce from the store and return its record.
+//
+//	nonce string The encoded nonce.
+//
+// returns:
+//
+//	record *model.NonceRecord
+//	err error model.ErrNonceNotFound if the nonce is unknown or has already been consumed
+func (s *InMemoryNonceStore) Consume(nonce string) (*model.NonceRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.nonces[nonce]
+	if !ok {
+		return nil, model.ErrNonceNotFound
+	}
+	delete(s.nonces, nonce)
+
+	return &record, nil
+}
+
+// encodeNonce Encode the nonce bytes the same way they are embedded in a derived proof.
+func encodeNonce(nonceBytes []byte) string {
+	return base64.StdEncoding.EncodeToString(nonceBytes)
+}
@@ -0,0 +1,100 @@
+package core_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type NonceManagerTestSuite struct {
+	suite.Suite
+}
+
+func TestNonceManagerTestSuite(t *testing.T) {
+	suite.Run(t, new(NonceManagerTestSuite))
+}
+
+func (s *NonceManagerTestSuite) TestIssueAndConsume() {
+	subject := core.NewNonceManager(nil, time.Minute)
+
+	nonceBytes, err := subject.Issue("session-1")
+	s.NoError(err)
+	s.Len(nonceBytes, core.DefaultNonceSize)
+
+	s.NoError(subject.ConsumeNonce("session-1", nonceBytes))
+
+	// a nonce cannot be replayed
+	s.ErrorIs(subject.ConsumeNonce("session-1", nonceBytes), model.ErrNonceNotFound)
+}
+
+func (s *NonceManagerTestSuite) TestIssuedNoncesAreUnique() {
+	subject := core.NewNonceManager(nil, time.Minute)
+
+	first, err := subject.Issue("session-1")
+	s.NoError(err)
+	second, err := subject.Issue("session-1")
+	s.NoError(err)
+
+	s.NotEqual(first, second)
+}
+
+func (s *NonceManagerTestSuite) TestUnknownNonce() {
+	subject := core.NewNonceManager(nil, time.Minute)
+
+	s.ErrorIs(subject.ConsumeNonce("session-1", []byte("nonce")), model.ErrNonceNotFound)
+}
+
+func (s *NonceManagerTestSuite) TestSessionMismatch() {
+	subject := core.NewNonceManager(nil, time.Minute)
+
+	nonceBytes, err := subject.Issue("session-1")
+	s.NoError(err)
+
+	s.ErrorIs(subject.ConsumeNonce("session-2", nonceBytes), model.ErrNonceSessionMismatch)
+
+	// the nonce has been consumed by the failed attempt
+	s.ErrorIs(subject.ConsumeNonce("session-1", nonceBytes), model.ErrNonceNotFound)
+}
+
+func (s *NonceManagerTestSuite) TestExpiredNonce() {
+	store := core.NewInMemoryNonceStore()
+	subject := core.NewNonceManager(store, time.Minute)
+
+	err := store.Save("bm9uY2U=", model.NonceRecord{
+		SessionID: "session-1",
+		ExpiresAt: time.Now().Add(-time.Second),
+	})
+	s.NoError(err)
+
+	s.ErrorIs(subject.ConsumeNonce("session-1", []byte("nonce")), model.ErrNonceExpired)
+}
+
+func (s *NonceManagerTestSuite) TestClockAndRand() {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	nonces := bytes.Repeat([]byte{7}, core.DefaultNonceSize)
+	subject := core.NewNonceManagerWithOptions(nil, time.Minute, &model.NonceManagerOptions{
+		Clock: func() time.Time { return now },
+		Rand:  bytes.NewReader(append(append([]byte(nil), nonces...), nonces...)),
+	})
+
+	nonceBytes, err := subject.Issue("session-1")
+	s.NoError(err)
+	s.Equal(nonces, nonceBytes)
+
+	// the nonce expires on the clock of the manager
+	now = now.Add(2 * time.Minute)
+	s.ErrorIs(subject.ConsumeNonce("session-1", nonceBytes), model.ErrNonceExpired)
+
+	// the in-memory store purges the expired nonces on the same clock
+	nonceBytes, err = subject.Issue("session-1")
+	s.NoError(err)
+	s.NoError(subject.ConsumeNonce("session-1", nonceBytes))
+
+	// the source of nonces is exhausted
+	_, err = subject.Issue("session-1")
+	s.Error(err)
+}
@@ -1,15 +1,31 @@
 package core
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"net/http"
 	"strings"
+	"sync"
 
 	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
-	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/context"
+	contexts "github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/context"
 	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
 	"github.com/piprate/json-gold/ld"
 )
 
+// embeddedContexts is the set of contexts shipped with the library, decoded once and shared by all the normalizers.
+// The decoded documents are never modified.
+var embeddedContexts = sync.OnceValue(func() map[string]interface{} {
+	return map[string]interface{}{
+		c.ContextCredentialV1:           decodeOrPanic(contexts.ContextCredentialsV1),
+		c.ContextSecurityBbsV1:          decodeOrPanic(contexts.ContextBbsBlsSignature2020),
+		c.ContextVCRevocationList2020V1: decodeOrPanic(contexts.ContextVCRevocationList2020V1),
+		c.ContextCitizenshipV1:          decodeOrPanic(contexts.ContextResidentCardV1),
+		c.ContextSecurityV2:             decodeOrPanic(contexts.ContextSecurityV2),
+	}
+})
+
 // NewNormalizer initializes normalizer struct
 // arguments:
 //
@@ -19,64 +35,78 @@ import (
 //
 //	normalizer *normalizer The normalizer instance.
 func NewNormalizer(options *model.SignatureSuiteOptions) *normalizer {
-	defaultLocalContexts := map[string]interface{}{
-		c.ContextCredentialV1:           decodeOrPanic(context.ContextCredentialsV1),
-		c.ContextSecurityBbsV1:          decodeOrPanic(context.ContextBbsBlsSignature2020),
-		c.ContextVCRevocationList2020V1: decodeOrPanic(context.ContextVCRevocationList2020V1),
-		c.ContextCitizenshipV1:          decodeOrPanic(context.ContextResidentCardV1),
-		c.ContextSecurityV2:             decodeOrPanic(context.ContextSecurityV2),
+	defaultLocalContexts := make(map[string]*ld.RemoteDocument)
+	for key, value := range embeddedContexts() {
+		defaultLocalContexts[key] = newLocalContextDocument(key, value)
 	}
 
 	if options != nil && options.Contexts != nil {
 		for key, value := range options.Contexts {
-			defaultLocalContexts[key] = value
+			defaultLocalContexts[key] = newLocalContextDocument(key, value)
 		}
 	}
 
 	var documentLoader ld.DocumentLoader
-
-	if options == nil || options.DocumentLoader == nil {
-		documentLoader = defaultDocumentLoader{
-			localContexts: defaultLocalContexts,
-			remoteDocumentLoader: ld.NewCachingDocumentLoader(
-				ld.NewDefaultDocumentLoader(nil), // 'nil' means that default http.Client will be used
-			),
-		}
-	} else {
+	limits := &model.ResourceLimits{} // no limit is enforced unless configured
+	if options != nil {
 		documentLoader = options.DocumentLoader
+		if options.Limits != nil {
+			limits = options.Limits
+		}
 	}
 
 	return &normalizer{
 		documentLoader: documentLoader,
+		localContexts:  defaultLocalContexts,
+		limits:         limits,
+		activeContexts: make(map[string]*ld.Context),
 	}
 }
 
+// maxActiveContexts The maximum number of processed context sets kept by a normalizer.
+// Once reached, the other context sets are processed for every document.
+const maxActiveContexts = 64
+
 // A normalizer implements operations for manipulations of json-ld documents.
 // Supported operations:
 // - Normalize
+//
+// A normalizer is safe for concurrent use as long as its document loader is.
 type normalizer struct {
-	documentLoader ld.DocumentLoader
+	documentLoader ld.DocumentLoader // custom document loader, nil if the default one has to be used
+	localContexts  map[string]*ld.RemoteDocument
+	limits         *model.ResourceLimits
+
+	activeContextsMu sync.RWMutex
+	activeContexts   map[string]*ld.Context // processed context sets, by their JSON serialization
 }
 
 // A defaultDocumentLoader contains a set of predefined contexts for document normalization
-// A defaultDocumentLoader uses *ld.CachingDocumentLoader to fetch unknown contexts from the internet
+// A defaultDocumentLoader fetches unknown contexts from the internet and keeps them in the process-wide ContextCache
 type defaultDocumentLoader struct {
-	remoteDocumentLoader *ld.CachingDocumentLoader
-	localContexts        map[string]interface{}
+	op                   *operation
+	remoteDocumentLoader ld.DocumentLoader
+	localContexts        map[string]*ld.RemoteDocument
 }
 
 func (l defaultDocumentLoader) LoadDocument(u string) (*ld.RemoteDocument, error) {
-	if val, ok := l.localContexts[u]; ok {
-		return &ld.RemoteDocument{
-			Document:    val,
-			DocumentURL: u,
-			ContextURL:  u,
-		}, nil
+	if document, ok := l.localContexts[u]; ok {
+		l.op.record(u, model.ContextSourcePreloaded)
+		return document, nil
 	}
 
 	return l.remoteDocumentLoader.LoadDocument(u)
 }
 
+// newLocalContextDocument Wrap a preloaded context so that it is built once and served as is by the document loader.
+func newLocalContextDocument(u string, context interface{}) *ld.RemoteDocument {
+	return &ld.RemoteDocument{
+		Document:    context,
+		DocumentURL: u,
+		ContextURL:  u,
+	}
+}
+
 // Normalize Perform normalization of a JSON-LD document using
 // format "application/n-quads" and algorithm "URDNA2015".
 //
@@ -87,8 +117,9 @@ func (l defaultDocumentLoader) LoadDocument(u string) (*ld.RemoteDocument, error
 //	messages []string array of string
 //	err error if an error appeared during the normalization: e.g. necessary context was not found
 func (n *normalizer) Normalize(document string) ([]string, error) {
-	proc := ld.NewJsonLdProcessor()
-	options := n.getStandardOptions()
+	if maxBytes := n.limits.MaxDocumentBytes; maxBytes > 0 && len(document) > maxBytes {
+		return nil, fmt.Errorf("%w: %d bytes, limit %d", model.ErrDocumentTooLarge, len(document), maxBytes)
+	}
 
 	var jsonRaw map[string]interface{}
 	err := json.Unmarshal([]byte(document), &jsonRaw)
@@ -96,21 +127,155 @@ func (n *normalizer) Normalize(document string) ([]string, error) {
 		return nil, err
 	}
 
-	normalizedTriples, err := proc.Normalize(jsonRaw, options)
+	return n.NormalizeDocument(jsonRaw)
+}
+
+// NormalizeDocument Perform normalization of a parsed JSON-LD document using
+// format "application/n-quads" and algorithm "URDNA2015".
+// The document is not modified.
+//
+//	document map[string]interface{} The JSON-LD document to normalize.
+//
+// returns:
+//
+//	messages []string array of string
+//	err error if an error appeared during the normalization: e.g. necessary context was not found
+func (n *normalizer) NormalizeDocument(document map[string]interface{}) ([]string, error) {
+	return n.NormalizeDocumentContext(context.Background(), document)
+}
+
+// NormalizeDocumentContext Perform normalization of a parsed JSON-LD document using
+// format "application/n-quads" and algorithm "URDNA2015".
+// The context is checked while loading the remote contexts and during the canonicalization.
+// The resource limits of the normalizer are enforced on the document, the loaded contexts and the canonicalization.
+//
+//	ctx context.Context
+//	document map[string]interface{} The JSON-LD document to normalize.
+//
+// returns:
+//
+//	messages []string array of string
+//	err error if an error appeared during the normalization: e.g. necessary context was not found or a limit was exceeded
+func (n *normalizer) NormalizeDocumentContext(ctx context.Context, document map[string]interface{}) ([]string, error) {
+	if err := checkDocumentLimits(document, n.limits); err != nil {
+		return nil, err
+	}
+
+	op := n.newOperation(ctx)
+	options := n.getStandardOptions(op)
+
+	dataset, err := n.toRDFWithActiveContext(document, options, op)
+	if dataset == nil || err != nil {
+		var rdf interface{}
+		rdf, err = ld.NewJsonLdProcessor().ToRDF(deepCopyMap(document), options)
+		if err != nil {
+			return nil, op.result(err)
+		}
+		dataset = rdf.(*ld.RDFDataset)
+	}
+
+	return canonicalize(ctx, dataset, n.limits)
+}
+
+// toRDFWithActiveContext Convert a document to RDF, expanding it with the processed context set of the document.
+// The context sets made only of preloaded contexts are processed once, and kept for the next documents.
+// The conversion is not attempted with a custom document loader, nor when the loaded contexts are traced.
+//
+//	document map[string]interface{}
+//	options *ld.JsonLdOptions The options of the operation.
+//	op *operation
+//
+// returns:
+//
+//	dataset *ld.RDFDataset nil if the document has to be converted by the JSON-LD processor
+//	err error if the conversion failed, in which case the JSON-LD processor reports the error
+func (n *normalizer) toRDFWithActiveContext(document map[string]interface{}, options *ld.JsonLdOptions, op *operation) (*ld.RDFDataset, error) {
+	documentContext, ok := document[c.CredentialFieldContext]
+	if !ok || n.documentLoader != nil || op.trace != nil {
+		return nil, nil
+	}
+	activeContext, err := n.activeContext(documentContext)
+	if activeContext == nil || err != nil {
+		return nil, err
+	}
+
+	input := deepCopyMap(document)
+	delete(input, c.CredentialFieldContext)
+	expanded, err := ld.NewJsonLdApi().Expand(activeContext, "", input, options, false, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	result := strings.Split(normalizedTriples.(string), "\n")
-	if result[len(result)-1] == "" {
-		result = result[:len(result)-1]
+	// the final steps of the expansion algorithm of ld.JsonLdProcessor
+	expandedMap, isMap := expanded.(map[string]interface{})
+	if isMap && len(expandedMap) == 0 {
+		expanded = nil
+	}
+	if graph, hasGraph := expandedMap["@graph"]; isMap && hasGraph && len(expandedMap) == 1 {
+		expanded = graph
+	} else if expanded == nil {
+		expanded = make([]interface{}, 0)
+	}
+	if _, isList := expanded.([]interface{}); !isList {
+		expanded = []interface{}{expanded}
 	}
 
-	return result, nil
+	return ld.NewJsonLdApi().ToRDF(expanded, options)
+}
+
+// activeContext Retrieve the processed context set of a document, processing it if it is made of preloaded contexts.
+// The processed context is shared by the operations: it only loads the preloaded contexts,
+// so that it never loads a document outside of the operation that uses it.
+//
+//	documentContext interface{} The '@context' of the document.
+//
+// returns:
+//
+//	activeContext *ld.Context nil if the context set cannot be processed once for all, e.g. it loads remote contexts
+//	err error
+func (n *normalizer) activeContext(documentContext interface{}) (*ld.Context, error) {
+	key, err := json.Marshal(documentContext)
+	if err != nil {
+		return nil, err
+	}
+	n.activeContextsMu.RLock()
+	activeContext, ok := n.activeContexts[string(key)]
+	n.activeContextsMu.RUnlock()
+	if ok {
+		return activeContext, nil
+	}
+
+	options := ld.NewJsonLdOptions("")
+	options.DocumentLoader = preloadedDocumentLoader(n.localContexts)
+	activeContext, err = ld.NewContext(nil, options).Parse(ld.CloneDocument(documentContext))
+	// a context set reverting to a previous context is expanded by the JSON-LD processor
+	if err != nil || activeContext.RevertToPreviousContext() != activeContext {
+		return nil, err
+	}
+
+	n.activeContextsMu.Lock()
+	defer n.activeContextsMu.Unlock()
+	if len(n.activeContexts) < maxActiveContexts {
+		n.activeContexts[string(key)] = activeContext
+	}
+
+	return activeContext, nil
+}
+
+// A preloadedDocumentLoader only loads the preloaded contexts.
+type preloadedDocumentLoader map[string]*ld.RemoteDocument
+
+func (l preloadedDocumentLoader) LoadDocument(u string) (*ld.RemoteDocument, error) {
+	if document, ok := l[u]; ok {
+		return document, nil
+	}
+
+	return nil, fmt.Errorf("context %s is not preloaded", u)
 }
 
 // Compact Compact a JSON-LD document against a set of contexts.
 //
+//	ctx context.Context
 //	document model.JsonLdCredential The JSON-LD document to compact.
 //	context interface{} The contexts against which the document has to be compacted.
 //
@@ -118,13 +283,17 @@ func (n *normalizer) Normalize(document string) ([]string, error) {
 //
 //	compactedDocument model.JsonLdCredential
 //	err error
-func (n *normalizer) Compact(document model.JsonLdCredential, context interface{}) (model.JsonLdCredential, error) {
+func (n *normalizer) Compact(ctx context.Context, document model.JsonLdCredential, context interface{}) (model.JsonLdCredential, error) {
+	if err := checkDocumentLimits(document, n.limits); err != nil {
+		return nil, err
+	}
+
 	proc := ld.NewJsonLdProcessor()
-	options := n.getStandardOptions()
+	op := n.newOperation(ctx)
 
-	result, err := proc.Compact(document, context, options)
+	result, err := proc.Compact(document, context, n.getStandardOptions(op))
 	if err != nil {
-		return nil, err
+		return nil, op.result(err)
 	}
 
 	return result, nil
@@ -132,31 +301,93 @@ func (n *normalizer) Compact(document model.JsonLdCredential, context interface{
 
 // Frame Frame a JSON-LD document according to the passed frame.
 //
+//	ctx context.Context
 //	input model.JsonLdCredential The document to frame.
 //	frame model.JsonLdFrame The frame document.
 //
 // returns:
 //
 //	framedDocument model.JsonLdCredential
-func (n *normalizer) Frame(input model.JsonLdCredential, frame model.JsonLdFrame) (model.JsonLdCredential, error) {
+func (n *normalizer) Frame(ctx context.Context, input model.JsonLdCredential, frame model.JsonLdFrame) (model.JsonLdCredential, error) {
+	if err := checkDocumentLimits(input, n.limits); err != nil {
+		return nil, err
+	}
+
 	proc := ld.NewJsonLdProcessor()
-	options := n.getStandardOptions()
+	op := n.newOperation(ctx)
+	options := n.getStandardOptions(op)
 	options.OmitGraph = true
 
 	result, err := proc.Frame(input, frame, options)
 	if err != nil {
-		return nil, err
+		return nil, op.result(err)
 	}
 
 	return result, nil
 }
 
-// getStandardOptions Get the list of options to use for the normalization.
-func (n *normalizer) getStandardOptions() *ld.JsonLdOptions {
+// FromRDF Convert normalized statements to an expanded JSON-LD document, the typed literals being converted to native values.
+//
+//	ctx context.Context
+//	statements []string The normalized statements.
+//
+// returns:
+//
+//	document model.JsonLdCredential The nodes of the statements in a '@graph'.
+//	err error
+func (n *normalizer) FromRDF(ctx context.Context, statements []string) (model.JsonLdCredential, error) {
+	op := n.newOperation(ctx)
+	options := n.getStandardOptions(op)
+	options.UseNativeTypes = true
+
+	result, err := ld.NewJsonLdProcessor().FromRDF(strings.Join(statements, "\n")+"\n", options)
+	if err != nil {
+		return nil, op.result(err)
+	}
+
+	return model.JsonLdCredential{"@graph": result}, nil
+}
+
+// newOperation Start tracking a new operation bound to a context.
+func (n *normalizer) newOperation(ctx context.Context) *operation {
+	trace, _ := ctx.Value(contextTraceKey{}).(*contextTrace)
+
+	return &operation{
+		ctx:    ctx,
+		limits: n.limits,
+		trace:  trace,
+	}
+}
+
+// getStandardOptions Get the list of options to use for an operation.
+func (n *normalizer) getStandardOptions(op *operation) *ld.JsonLdOptions {
 	options := ld.NewJsonLdOptions("")
-	options.Format = "application/n-quads"
-	options.Algorithm = "URDNA2015"
-	options.DocumentLoader = n.documentLoader
+	options.DocumentLoader = n.getDocumentLoader(op)
 
 	return options
 }
+
+// getDocumentLoader Get the document loader to use for an operation.
+// The preloaded contexts are trusted and do not count towards the limits of the operation.
+func (n *normalizer) getDocumentLoader(op *operation) ld.DocumentLoader {
+	if n.documentLoader != nil {
+		return operationDocumentLoader{
+			op:     op,
+			loader: n.documentLoader,
+			source: model.ContextSourceDocumentLoader,
+		}
+	}
+
+	return defaultDocumentLoader{
+		op:            op,
+		localContexts: n.localContexts,
+		remoteDocumentLoader: operationDocumentLoader{
+			op: op,
+			loader: cachingDocumentLoader{
+				op:     op,
+				cache:  sharedContextCache,
+				loader: ld.NewDefaultDocumentLoader(&http.Client{Transport: operationTransport{op: op}}),
+			},
+		},
+	}
+}
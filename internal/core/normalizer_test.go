@@ -1,11 +1,15 @@
 package core_test
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"testing"
 
 	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/piprate/json-gold/ld"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -51,3 +55,120 @@ func (s *NormalizerTestSuite) TestCredentialNormalization() {
 
 	s.Equal(expected, actual)
 }
+
+func (s *NormalizerTestSuite) TestActiveContextNormalization() {
+	subject := core.NewNormalizer(benchmarkOptions(s.T()))
+
+	var documents []map[string]interface{}
+	for _, file := range []string{"testdata/unsignedPermanentResidentCard.json", "testdata/unsignedCredential.json", "testdata/unsignedProof.json"} {
+		var document map[string]interface{}
+		documentBytes, err := os.ReadFile(file)
+		s.Require().NoError(err)
+		s.Require().NoError(json.Unmarshal(documentBytes, &document))
+		documents = append(documents, document)
+	}
+	documents = append(documents, map[string]interface{}{
+		"@context": []interface{}{
+			"https://www.w3.org/2018/credentials/v1",
+			map[string]interface{}{"name": "https://example.com/name"},
+		},
+		"@id":  "urn:example:1",
+		"type": "VerifiableCredential",
+		"name": "Alice",
+	}, map[string]interface{}{
+		"@context": map[string]interface{}{"@vocab": "https://example.com/"},
+		"@graph": []interface{}{
+			map[string]interface{}{"@id": "urn:example:1", "name": "Alice"},
+		},
+	})
+
+	// every document twice, so that the processed context sets are reused, by another document
+	for _, document := range append(documents, documents...) {
+		expected, err := subject.NormalizeDocumentContext(core.WithoutActiveContexts(context.Background()), document)
+		s.Require().NoError(err)
+
+		actual, err := subject.NormalizeDocument(document)
+		s.Require().NoError(err)
+		s.Equal(expected, actual)
+	}
+}
+
+func (s *NormalizerTestSuite) TestNormalizationCancelled() {
+	subject := core.NewNormalizer(nil)
+
+	var credential map[string]interface{}
+	credentialJSONLdBytes, err := os.ReadFile("testdata/unsignedPermanentResidentCard.json")
+	s.NoError(err)
+	err = json.Unmarshal(credentialJSONLdBytes, &credential)
+	s.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = subject.NormalizeDocumentContext(ctx, credential)
+	s.ErrorIs(err, context.Canceled)
+}
+
+func (s *NormalizerTestSuite) TestContextPassedToDocumentLoader() {
+	loader := &recordingDocumentLoader{}
+	subject := core.NewNormalizer(&model.SignatureSuiteOptions{
+		DocumentLoader: loader,
+	})
+
+	document := map[string]interface{}{
+		"@context": "https://example.com/context/v1",
+		"@id":      "urn:example:1",
+		"name":     "Alice",
+	}
+
+	ctx := context.WithValue(context.Background(), recordingContextKey{}, "request-1")
+	actual, err := subject.NormalizeDocumentContext(ctx, document)
+	s.NoError(err)
+	s.Equal([]string{`<urn:example:1> <https://example.com/name> "Alice" .`}, actual)
+	s.Equal([]interface{}{"request-1"}, loader.values)
+}
+
+type recordingContextKey struct{}
+
+// A recordingDocumentLoader serves a fixed context and records a value of the context of every load.
+type recordingDocumentLoader struct {
+	values []interface{}
+}
+
+func (l *recordingDocumentLoader) LoadDocument(u string) (*ld.RemoteDocument, error) {
+	return l.LoadDocumentContext(context.Background(), u)
+}
+
+func (l *recordingDocumentLoader) LoadDocumentContext(ctx context.Context, u string) (*ld.RemoteDocument, error) {
+	l.values = append(l.values, ctx.Value(recordingContextKey{}))
+
+	return &ld.RemoteDocument{
+		DocumentURL: u,
+		Document: map[string]interface{}{
+			"@context": map[string]interface{}{
+				"name": "https://example.com/name",
+			},
+		},
+	}, nil
+}
+
+func BenchmarkNormalizeDocument(b *testing.B) {
+	for _, claims := range []int{10, 100, 1000} {
+		for name, ctx := range map[string]context.Context{
+			"activeContext": context.Background(),
+			"processor":     core.WithoutActiveContexts(context.Background()),
+		} {
+			b.Run(fmt.Sprintf("claims=%d/%s", claims, name), func(b *testing.B) {
+				subject := core.NewNormalizer(benchmarkOptions(b))
+				credential := benchmarkCredential(b, claims)
+
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if _, err := subject.NormalizeDocumentContext(ctx, credential); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"gopkg.in/yaml.v3"
+)
+
+// ParseVerificationPolicyJSON Parse a verification policy from its JSON representation.
+// Unknown fields are rejected, so that a misspelled rule is not silently ignored.
+//
+//	data []byte
+//
+// returns:
+//
+//	policy *model.VerificationPolicy
+//	err error
+func ParseVerificationPolicyJSON(data []byte) (*model.VerificationPolicy, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var policy model.VerificationPolicy
+	if err := decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("parse verification policy: %w", err)
+	}
+
+	return &policy, nil
+}
+
+// ParseVerificationPolicyYAML Parse a verification policy from its YAML representation.
+// Unknown fields are rejected, so that a misspelled rule is not silently ignored.
+//
+//	data []byte
+//
+// returns:
+//
+//	policy *model.VerificationPolicy
+//	err error
+func ParseVerificationPolicyYAML(data []byte) (*model.VerificationPolicy, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var policy model.VerificationPolicy
+	if err := decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("parse verification policy: %w", err)
+	}
+
+	return &policy, nil
+}
+
+// LoadVerificationPolicy Read a verification policy from a file.
+// Files with extension ".yaml" or ".yml" are parsed as YAML, the others as JSON.
+//
+//	path string
+//
+// returns:
+//
+//	policy *model.VerificationPolicy
+//	err error
+func LoadVerificationPolicy(path string) (*model.VerificationPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ParseVerificationPolicyYAML(data)
+	default:
+		return ParseVerificationPolicyJSON(data)
+	}
+}
+
+// A policyEngine evaluates a verification policy on the credentials whose proofs have been verified.
+type policyEngine struct {
+	policy            *model.VerificationPolicy // nil if every credential is accepted
+	keyEncoder        *KeyEncoder
+	now               func() time.Time
+	skipIssuerBinding bool // the issuer ids are then asserted by the credentials, only the keys can be trusted
+}
+
+// newPolicyEngine Build the policy engine configured by the suite options.
+//
+//	options *model.SignatureSuiteOptions nullable
+func newPolicyEngine(options *model.SignatureSuiteOptions) *policyEngine {
+	engine := &policyEngine{
+		keyEncoder: &KeyEncoder{},
+		now:        clockOption(options),
+	}
+	if options != nil {
+		engine.policy = options.Policy
+		engine.skipIssuerBinding = options.SkipIssuerBinding
+	}
+
+	return engine
+}
+
+// CheckIssuerBindingPolicy Check that the policy of the suite options does not trust issuer ids
+// while the issuer binding is skipped: the issuer of a credential would then be the one it asserts,
+// whatever the key that signed it.
+//
+//	options *model.SignatureSuiteOptions nullable
+//
+// returns:
+//
+//	err error wrapping model.ErrTrustedIssuersWithoutBinding
+func CheckIssuerBindingPolicy(options *model.SignatureSuiteOptions) error {
+	if options == nil || !options.SkipIssuerBinding || options.Policy == nil {
+		return nil
+	}
+	for i, rule := range options.Policy.Rules {
+		if len(rule.TrustedIssuers) > 0 {
+			return fmt.Errorf("%w: rules[%d]", model.ErrTrustedIssuersWithoutBinding, i)
+		}
+	}
+
+	return nil
+}
+
+// apply Evaluate the policy on a credential whose proofs have been successfully verified.
+//
+//	credential model.JsonLdCredential The verified credential, with its proofs.
+//	publicKey []byte The key used to verify the proofs.
+//
+// returns:
+//
+//	result *model.VerificationResult successful, or failed with a *model.PolicyViolation
+func (e *policyEngine) apply(credential model.JsonLdCredential, publicKey []byte) *model.VerificationResult {
+	if violation := e.evaluate(credential, publicKey); violation != nil {
+		return &model.VerificationResult{
+			Success: false,
+			Error:   violation,
+		}
+	}
+
+	return &model.VerificationResult{
+		Success: true,
+	}
+}
+
+// evaluate Check a credential against every matching rule of the policy.
+//
+// returns:
+//
+//	violation *model.PolicyViolation nil if the credential satisfies the policy
+func (e *policyEngine) evaluate(credential model.JsonLdCredential, publicKey []byte) *model.PolicyViolation {
+	if e.policy == nil {
+		return nil
+	}
+
+	types := stringValues(credential[c.CredentialFieldType])
+	matched := false
+	for i := range e.policy.Rules {
+		rule := &e.policy.Rules[i]
+		if rule.CredentialType != "" && !slices.Contains(types, rule.CredentialType) {
+			continue
+		}
+		matched = true
+
+		check, reason := e.evaluateRule(rule, credential, publicKey)
+		if check != "" {
+			name := rule.Name
+			if name == "" {
+				name = fmt.Sprintf("rules[%d]", i)
+			}
+
+			return &model.PolicyViolation{Rule: name, Check: check, Reason: reason}
+		}
+	}
+
+	if !matched {
+		return &model.PolicyViolation{
+			Check:  model.PolicyCheckCredentialType,
+			Reason: fmt.Sprintf("no rule applies to the credential types %v", types),
+		}
+	}
+
+	return nil
+}
+
+// evaluateRule Check a credential against a rule.
+//
+// returns:
+//
+//	check string the failed check, empty if the rule is satisfied
+//	reason string
+func (e *policyEngine) evaluateRule(rule *model.PolicyRule, credential model.JsonLdCredential, publicKey []byte) (string, string) {
+	// 1. Issuer, by id or by verification key
+	if len(rule.TrustedIssuers) > 0 && e.skipIssuerBinding {
+		return model.PolicyCheckTrustedIssuers, model.ErrTrustedIssuersWithoutBinding.Error()
+	}
+	if len(rule.TrustedIssuers) > 0 || len(rule.TrustedKeys) > 0 {
+		issuer, _ := credentialIssuerID(credential)
+		if !slices.Contains(rule.TrustedIssuers, issuer) && !e.isTrustedKey(rule.TrustedKeys, publicKey) {
+			return model.PolicyCheckTrustedIssuers, fmt.Sprintf("issuer '%s' is not trusted", issuer)
+		}
+	}
+
+	// 2. Contexts
+	contexts := stringValues(credential[c.CredentialFieldContext])
+	for _, required := range rule.RequiredContexts {
+		if !slices.Contains(contexts, required) {
+			return model.PolicyCheckRequiredContexts, fmt.Sprintf("context '%s' is missing", required)
+		}
+	}
+
+	// 3. Disclosed claims
+	for _, path := range rule.RequiredClaims {
+		if !hasClaim(credential, strings.Split(path, ".")) {
+			return model.PolicyCheckRequiredClaims, fmt.Sprintf("claim '%s' is not disclosed", path)
+		}
+	}
+
+	// 4. Age
+	if rule.MaxAge > 0 {
+		issued, err := issuanceDate(credential)
+		if err != nil {
+			return model.PolicyCheckMaxAge, err.Error()
+		}
+		if age := e.now().Sub(issued); age > time.Duration(rule.MaxAge) {
+			return model.PolicyCheckMaxAge, fmt.Sprintf("credential issued %s ago, limit %s", age.Truncate(time.Second), time.Duration(rule.MaxAge))
+		}
+	}
+
+	// 5. Proof types
+	if len(rule.AllowedProofTypes) > 0 {
+		for _, proofType := range proofTypes(credential) {
+			if !slices.Contains(rule.AllowedProofTypes, proofType) {
+				return model.PolicyCheckAllowedProofType, fmt.Sprintf("proof type '%s' is not allowed", proofType)
+			}
+		}
+	}
+
+	return "", ""
+}
+
+// isTrustedKey Check whether the verification key is listed, as did:key or multibase.
+func (e *policyEngine) isTrustedKey(trustedKeys []string, publicKey []byte) bool {
+	for _, trusted := range trustedKeys {
+		var key []byte
+		var err error
+		if strings.HasPrefix(trusted, "did:key:") {
+			key, err = e.keyEncoder.DecodeDidKey(trusted)
+		} else {
+			key, err = e.keyEncoder.DecodeMultibaseKey(trusted)
+		}
+		if err == nil && bytes.Equal(key, publicKey) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasClaim Check whether a claim is present at the given path. Arrays are searched element by element.
+func hasClaim(value interface{}, path []string) bool {
+	if len(path) == 0 {
+		return value != nil
+	}
+
+	switch node := value.(type) {
+	case map[string]interface{}:
+		return hasClaim(node[path[0]], path[1:])
+	case []interface{}:
+		for _, element := range node {
+			if hasClaim(element, path) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// issuanceDate Retrieve the issuance date of a credential ("issuanceDate", or "validFrom" for VCDM 2.0).
+func issuanceDate(credential model.JsonLdCredential) (time.Time, error) {
+	for _, field := range []string{c.CredentialFieldIssuanceDate, c.CredentialFieldValidFrom} {
+		if value, ok := credential[field].(string); ok {
+			issued, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("invalid '%s': %s", field, err.Error())
+			}
+			return issued, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("credential doesn't disclose its '%s'", c.CredentialFieldIssuanceDate)
+}
+
+// proofTypes Retrieve the types of the proofs of a credential.
+func proofTypes(credential model.JsonLdCredential) []string {
+	var types []string
+	for _, proof := range asSlice(credential[c.CredentialFieldProof]) {
+		if proof, ok := proof.(map[string]interface{}); ok {
+			types = append(types, stringValues(proof[c.CredentialFieldType])...)
+		}
+	}
+
+	return types
+}
+
+// stringValues Retrieve the strings of a JSON-LD value that is either a string or an array.
+func stringValues(value interface{}) []string {
+	var values []string
+	for _, element := range asSlice(value) {
+		if s, ok := element.(string); ok {
+			values = append(values, s)
+		}
+	}
+
+	return values
+}
+
+// asSlice Wrap a single JSON-LD value in a slice.
+func asSlice(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	default:
+		return []interface{}{v}
+	}
+}
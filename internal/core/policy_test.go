@@ -0,0 +1,159 @@
+package core_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type PolicyTestSuite struct {
+	suite.Suite
+	options   *model.SignatureSuiteOptions
+	publicKey []byte
+}
+
+func TestPolicyTestSuite(t *testing.T) {
+	suite.Run(t, new(PolicyTestSuite))
+}
+
+func (s *PolicyTestSuite) SetupTest() {
+	s.options = benchmarkOptions(s.T())
+	s.publicKey = benchmarkPublicKey(s.T())
+}
+
+// verify Verify a test credential with the policy.
+func (s *PolicyTestSuite) verify(file string, policy *model.VerificationPolicy) *model.VerificationResult {
+	var credential model.JsonLdCredential
+	credentialBytes, err := os.ReadFile(filepath.Join("testdata", file))
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(credentialBytes, &credential))
+
+	options := *s.options
+	options.Policy = policy
+	if file == "derivedProof.json" {
+		return core.NewSignatureProofSuite2020(s.publicKey, &options).VerifyProof(credential)
+	}
+
+	return core.NewSignatureSuite2020(s.publicKey, nil, &options).Verify(credential)
+}
+
+// requireViolation Check that the verification failed on the expected rule and check.
+func (s *PolicyTestSuite) requireViolation(result *model.VerificationResult, rule, check string) {
+	s.False(result.Success)
+	s.ErrorIs(result.Error, model.ErrPolicyViolation)
+
+	var violation *model.PolicyViolation
+	s.Require().True(errors.As(result.Error, &violation))
+	s.Equal(rule, violation.Rule)
+	s.Equal(check, violation.Check)
+}
+
+func (s *PolicyTestSuite) TestPolicyFromFile() {
+	policy, err := core.LoadVerificationPolicy("testdata/policy.yaml")
+	s.NoError(err)
+	s.Equal(model.Duration(876000*time.Hour), policy.Rules[0].MaxAge)
+
+	result := s.verify("signedCredential.json", policy)
+	s.True(result.Success, result.Error)
+	result = s.verify("derivedProof.json", policy)
+	s.True(result.Success, result.Error)
+
+	// same policy in JSON
+	serialized, err := json.Marshal(policy)
+	s.NoError(err)
+	parsed, err := core.ParseVerificationPolicyJSON(serialized)
+	s.NoError(err)
+	s.Equal(policy, parsed)
+}
+
+func (s *PolicyTestSuite) TestInvalidPolicy() {
+	_, err := core.ParseVerificationPolicyJSON([]byte(`{"rules": [{"trustedIssuer": ["did:example:issuer"]}]}`))
+	s.ErrorContains(err, "unknown field")
+
+	_, err = core.ParseVerificationPolicyYAML([]byte("rules:\n  - trustedIssuer: [did:example:issuer]\n"))
+	s.ErrorContains(err, "not found")
+
+	_, err = core.ParseVerificationPolicyYAML([]byte("rules:\n  - maxAge: 30 days\n"))
+	s.Error(err)
+}
+
+func (s *PolicyTestSuite) TestFailedRules() {
+	rule := func(modify func(*model.PolicyRule)) *model.VerificationPolicy {
+		policy, err := core.LoadVerificationPolicy("testdata/policy.yaml")
+		s.Require().NoError(err)
+		modify(&policy.Rules[0])
+		return policy
+	}
+
+	// untrusted issuer
+	result := s.verify("signedCredential.json", rule(func(r *model.PolicyRule) { r.TrustedIssuers = []string{"did:example:issuer"} }))
+	s.requireViolation(result, "permanent-resident-card", model.PolicyCheckTrustedIssuers)
+
+	// trusted by key
+	result = s.verify("signedCredential.json", rule(func(r *model.PolicyRule) {
+		r.TrustedKeys = r.TrustedIssuers
+		r.TrustedIssuers = nil
+	}))
+	s.True(result.Success, result.Error)
+
+	// missing context
+	result = s.verify("signedCredential.json", rule(func(r *model.PolicyRule) { r.RequiredContexts = []string{"https://example.com/context/v1"} }))
+	s.requireViolation(result, "permanent-resident-card", model.PolicyCheckRequiredContexts)
+
+	// undisclosed claim
+	result = s.verify("derivedProof.json", rule(func(r *model.PolicyRule) { r.RequiredClaims = []string{"credentialSubject.givenName"} }))
+	s.requireViolation(result, "permanent-resident-card", model.PolicyCheckRequiredClaims)
+
+	// too old
+	result = s.verify("signedCredential.json", rule(func(r *model.PolicyRule) { r.MaxAge = model.Duration(24 * time.Hour) }))
+	s.requireViolation(result, "permanent-resident-card", model.PolicyCheckMaxAge)
+
+	// derived proofs only
+	result = s.verify("signedCredential.json", rule(func(r *model.PolicyRule) { r.AllowedProofTypes = []string{"BbsBlsSignatureProof2020"} }))
+	s.requireViolation(result, "permanent-resident-card", model.PolicyCheckAllowedProofType)
+
+	// default rule name
+	result = s.verify("signedCredential.json", rule(func(r *model.PolicyRule) {
+		r.Name = ""
+		r.MaxAge = model.Duration(time.Hour)
+	}))
+	s.requireViolation(result, "rules[0]", model.PolicyCheckMaxAge)
+
+	// no rule for the credential type
+	result = s.verify("signedCredential.json", rule(func(r *model.PolicyRule) { r.CredentialType = "UniversityDegreeCredential" }))
+	s.requireViolation(result, "", model.PolicyCheckCredentialType)
+}
+
+func (s *PolicyTestSuite) TestSkipIssuerBinding() {
+	policy, err := core.LoadVerificationPolicy("testdata/policy.yaml")
+	s.Require().NoError(err)
+	s.Require().NotEmpty(policy.Rules[0].TrustedIssuers)
+	skipping := *s.options
+	skipping.SkipIssuerBinding = true
+	skipping.Policy = policy
+	s.ErrorIs(core.CheckIssuerBindingPolicy(&skipping), model.ErrTrustedIssuersWithoutBinding)
+
+	// the issuer id asserted by the credential is not trusted
+	s.options.SkipIssuerBinding = true
+	result := s.verify("signedCredential.json", policy)
+	s.requireViolation(result, "permanent-resident-card", model.PolicyCheckTrustedIssuers)
+	result = s.verify("derivedProof.json", policy)
+	s.requireViolation(result, "permanent-resident-card", model.PolicyCheckTrustedIssuers)
+
+	// the issuer key is
+	policy.Rules[0].TrustedKeys = policy.Rules[0].TrustedIssuers
+	policy.Rules[0].TrustedIssuers = nil
+	skipping.Policy = policy
+	s.NoError(core.CheckIssuerBindingPolicy(&skipping))
+	result = s.verify("signedCredential.json", policy)
+	s.True(result.Success, result.Error)
+	result = s.verify("derivedProof.json", policy)
+	s.True(result.Success, result.Error)
+}
@@ -0,0 +1,560 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	ml "github.com/IBM/mathlib"
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/hyperledger/aries-bbs-go/bbs"
+)
+
+// The claims listed in the PredicateClaims option are signed twice: as N-Quad statements, hashed like the other
+// statements, and as integers, following the statements. Each integer encoding is preceded by a descriptor naming
+// the claim and its datatype, disclosed when a predicate is proven on the hidden encoding.
+//
+// A predicate, e.g. claim < bound, is proven with a range proof: the difference between the bound and the claim
+// is decomposed in PredicateRangeBits bits, each committed and proven to be 0 or 1. The sum of the bit commitments
+// is linked to the hidden encoding by a proof of knowledge sharing the response of the encoding in the proof of
+// knowledge of the signature.
+
+// An encodedClaim is a claim signed with its integer encoding.
+type encodedClaim struct {
+	claim    string // IRI of the predicate of the claim
+	datatype string
+	value    *big.Int
+}
+
+// encodeClaims Compute the integer encodings of the predicate claims found among the statements of a credential,
+// in the order of the statements.
+//
+//	claims []string The IRIs of the predicate claims.
+//	statements [][]byte The normalized statements of the proof and of the credential.
+//
+// returns:
+//
+//	encoded []*encodedClaim
+//	err error if a predicate claim occurs several times or is not an integer or a date
+func encodeClaims(claims []string, statements [][]byte) ([]*encodedClaim, error) {
+	if len(claims) == 0 {
+		return nil, nil
+	}
+
+	encoded := make([]*encodedClaim, 0, len(claims))
+	for _, statement := range statements {
+		// an N-Quad statement: subject predicate object [graph] .
+		terms := strings.SplitN(string(statement), " ", 3)
+		if len(terms) < 3 || !strings.HasPrefix(terms[1], "<") {
+			continue
+		}
+		claim := strings.Trim(terms[1], "<>")
+		if !slices.Contains(claims, claim) {
+			continue
+		}
+		for _, other := range encoded {
+			if other.claim == claim {
+				return nil, fmt.Errorf("predicate claim '%s' occurs several times", claim)
+			}
+		}
+
+		lexical, datatype, ok := parseTypedLiteral(terms[2])
+		if !ok {
+			return nil, fmt.Errorf("predicate claim '%s' is not a typed literal", claim)
+		}
+		value, err := encodeValue(datatype, lexical)
+		if err != nil {
+			return nil, fmt.Errorf("predicate claim '%s': %w", claim, err)
+		}
+		encoded = append(encoded, &encodedClaim{claim: claim, datatype: datatype, value: value})
+	}
+
+	return encoded, nil
+}
+
+// parseTypedLiteral Retrieve the lexical form and the datatype of the object of an N-Quad statement, e.g. "1990-11-22"^^<xsd:date> .
+func parseTypedLiteral(object string) (string, string, bool) {
+	if !strings.HasPrefix(object, `"`) {
+		return "", "", false
+	}
+	end := strings.Index(object, `"^^<`)
+	if end < 0 {
+		return "", "", false
+	}
+	datatype, _, ok := strings.Cut(object[end+len(`"^^<`):], ">")
+	if !ok {
+		return "", "", false
+	}
+
+	return object[1:end], datatype, true
+}
+
+// encodeValue Compute the integer encoding of a literal:
+//   - xsd:integer: the integer, within the range of an int64
+//   - xsd:date: the number of days since 1970-01-01
+//   - xsd:dateTime: the number of seconds since 1970-01-01T00:00:00Z, a date being read as midnight UTC
+//
+// arguments:
+//
+//	datatype string
+//	lexical string
+//
+// returns:
+//
+//	value *big.Int
+//	err error
+func encodeValue(datatype, lexical string) (*big.Int, error) {
+	switch datatype {
+	case c.XsdInteger:
+		value, ok := new(big.Int).SetString(lexical, 10)
+		if !ok || !value.IsInt64() {
+			return nil, fmt.Errorf("invalid integer '%s'", lexical)
+		}
+		return value, nil
+	case c.XsdDate:
+		date, err := time.Parse(time.DateOnly, lexical)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date '%s'", lexical)
+		}
+		// Unix is negative before 1970: round towards minus infinity
+		days := new(big.Int).Div(big.NewInt(date.Unix()), big.NewInt(24*60*60))
+		return days, nil
+	case c.XsdDateTime:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", time.DateOnly} {
+			if dateTime, err := time.Parse(layout, lexical); err == nil {
+				return big.NewInt(dateTime.Unix()), nil
+			}
+		}
+		return nil, fmt.Errorf("invalid date time '%s'", lexical)
+	default:
+		return nil, fmt.Errorf("unsupported datatype '%s'", datatype)
+	}
+}
+
+// encodingDescriptor Build the descriptor signed before the encoding of a claim.
+func encodingDescriptor(claim, datatype string) []byte {
+	return []byte(c.PredicateEncodingMarker + " <" + claim + "> <" + datatype + ">")
+}
+
+// encodingMessages Build the messages of the encodings of the predicate claims: a descriptor followed by the encoding,
+// for each claim.
+//
+//	curve *ml.Curve
+//	offset int The index of the first message, i.e. the number of statements.
+//	encoded []*encodedClaim
+func encodingMessages(curve *ml.Curve, offset int, encoded []*encodedClaim) []*bbs.SignatureMessage {
+	messages := make([]*bbs.SignatureMessage, 0, 2*len(encoded))
+	for _, claim := range encoded {
+		index := offset + len(messages)
+		messages = append(messages,
+			bbs.ParseSignatureMessage(encodingDescriptor(claim.claim, claim.datatype), index, curve),
+			&bbs.SignatureMessage{FR: zrFromBigInt(curve, claim.value), Idx: index + 1},
+		)
+	}
+
+	return messages
+}
+
+// signatureMessages Build all the messages signed for a credential: its statements, the encodings of its predicate claims
+// and, if it is bound to its holder, the holder binding marker and the holder secret.
+//
+//	curve *ml.Curve
+//	statements [][]byte The normalized statements of the proof and of the credential.
+//	encoded []*encodedClaim
+//	holderSecret model.HolderSecret nil if the credential is not bound to its holder
+func signatureMessages(curve *ml.Curve, statements [][]byte, encoded []*encodedClaim, holderSecret model.HolderSecret) []*bbs.SignatureMessage {
+	messages := append(bbs.MessagesToFr(statements, curve), encodingMessages(curve, len(statements), encoded)...)
+	if holderSecret != nil {
+		messages = append(messages,
+			bbs.ParseSignatureMessage([]byte(c.HolderBindingMarker), len(messages), curve),
+			bbs.ParseSignatureMessage(holderSecret, len(messages)+1, curve),
+		)
+	}
+
+	return messages
+}
+
+// zrFromBigInt Convert an integer, possibly negative, to an element of the scalar field.
+func zrFromBigInt(curve *ml.Curve, value *big.Int) *ml.Zr {
+	zr := curve.NewZrFromBytes(new(big.Int).Abs(value).Bytes())
+	if value.Sign() < 0 {
+		zr = curve.ModNeg(zr, curve.GroupOrder)
+	}
+
+	return zr
+}
+
+// predicateBound Compute the inclusive bound of a predicate: claim <= bound if upper, claim >= bound otherwise.
+//
+//	datatype string The datatype of the claim.
+//	predicate model.Predicate
+//
+// returns:
+//
+//	upper bool
+//	bound *big.Int
+//	err error
+func predicateBound(datatype string, predicate model.Predicate) (bool, *big.Int, error) {
+	value, err := encodeValue(datatype, predicate.Value)
+	if err != nil {
+		return false, nil, fmt.Errorf("predicate on '%s': %w", predicate.Claim, err)
+	}
+
+	switch predicate.Operator {
+	case model.PredicateLessThan:
+		return true, value.Sub(value, big.NewInt(1)), nil
+	case model.PredicateLessOrEqual:
+		return true, value, nil
+	case model.PredicateGreaterThan:
+		return false, value.Add(value, big.NewInt(1)), nil
+	case model.PredicateGreaterOrEqual:
+		return false, value, nil
+	default:
+		return false, nil, fmt.Errorf("predicate on '%s': unsupported operator '%s'", predicate.Claim, predicate.Operator)
+	}
+}
+
+// rangeOffset Compute the sign and the offset such that the difference proven in range is sign * claim + offset.
+func rangeOffset(upper bool, bound *big.Int) (int, *big.Int) {
+	if upper {
+		// bound - claim
+		return -1, new(big.Int).Set(bound)
+	}
+
+	// claim - bound
+	return 1, new(big.Int).Neg(bound)
+}
+
+// checkRequiredPredicates Check that every required predicate is implied by a proven predicate on the same claim,
+// e.g. birthDate < 2008-10-17 implies birthDate <= 2010-01-01.
+//
+//	required []model.Predicate
+//	proven []model.PredicateProof
+//
+// returns:
+//
+//	err error wrapping model.ErrPredicateNotProven
+func checkRequiredPredicates(required []model.Predicate, proven []model.PredicateProof) error {
+	for _, predicate := range required {
+		implied := false
+		for _, proof := range proven {
+			if proof.Claim != predicate.Claim {
+				continue
+			}
+			provenUpper, provenBound, err := predicateBound(proof.Datatype, proof.Predicate)
+			if err != nil {
+				return err
+			}
+			requiredUpper, requiredBound, err := predicateBound(proof.Datatype, predicate)
+			if err != nil {
+				return err
+			}
+			if provenUpper == requiredUpper && (provenUpper && provenBound.Cmp(requiredBound) <= 0 || !provenUpper && provenBound.Cmp(requiredBound) >= 0) {
+				implied = true
+				break
+			}
+		}
+		if !implied {
+			return fmt.Errorf("%w: %s %s %s", model.ErrPredicateNotProven, predicate.Claim, predicate.Operator, predicate.Value)
+		}
+	}
+
+	return nil
+}
+
+// decodePredicateProofs Parse the predicate proofs embedded in a derived proof.
+//
+//	value interface{} nil if the derived proof does not prove predicates
+//
+// returns:
+//
+//	proofs []model.PredicateProof
+//	err error
+func decodePredicateProofs(value interface{}) ([]model.PredicateProof, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var proofs []model.PredicateProof
+	if err := json.Unmarshal(valueBytes, &proofs); err != nil {
+		return nil, fmt.Errorf("The predicates are not correctly formatted: %w", err)
+	}
+
+	return proofs, nil
+}
+
+// predicateChallengeBytes Serialize the statement of a predicate proof, to compute the challenge.
+func predicateChallengeBytes(proof *model.PredicateProof) []byte {
+	return []byte(fmt.Sprintf("%s <%s> <%s> %s %s %d", c.PredicateEncodingMarker, proof.Claim, proof.Datatype, proof.Operator, proof.Value, proof.Message))
+}
+
+// rangeBases Retrieve the generators of the commitments of the range proofs, whose discrete logarithms are unknown.
+func rangeBases(curve *ml.Curve) (*ml.G1, *ml.G1) {
+	domain := []byte(c.PredicateEncodingMarker)
+
+	return curve.HashToG1WithDomain([]byte("g"), domain), curve.HashToG1WithDomain([]byte("h"), domain)
+}
+
+// A rangeProver proves that a committed difference is lower than 2^PredicateRangeBits.
+// Every bit b_i of the difference is committed with C_i = g*b_i + h*r_i, and proven to be 0 or 1 with a disjunctive proof:
+// either C_i = h*r_i or C_i - g = h*r_i. The response of the simulated branch is chosen before the challenge.
+type rangeProver struct {
+	curve *ml.Curve
+	h     *ml.G1
+
+	bits        []bool
+	blindings   []*ml.Zr // r_i
+	commitments []*ml.G1 // C_i
+	proofs      [][2]*ml.G1
+	challenges  []*ml.Zr // challenge of the simulated branch
+	responses   []*ml.Zr // response of the simulated branch
+	witnesses   []*ml.Zr // blinding of the real branch
+
+	blinding           *ml.Zr // sum of 2^i * r_i
+	blindingCommitment *ml.Zr // blinding of the proof of the opening of the sum of the bit commitments
+	openingCommitment  *ml.G1
+}
+
+// newRangeProver Commit to the bits of a difference, and to the opening of their sum linked to the hidden claim.
+//
+//	curve *ml.Curve
+//	rng io.Reader The source of the random factors.
+//	difference *big.Int sign * claim + offset, within [0, 2^PredicateRangeBits)
+//	sign int
+//	claimBlinding *ml.Zr The blinding factor of the claim in the proof of knowledge of the signature.
+//
+// returns:
+//
+//	prover *rangeProver
+//	err error if the source of randomness fails
+func newRangeProver(curve *ml.Curve, rng io.Reader, difference *big.Int, sign int, claimBlinding *ml.Zr) (*rangeProver, error) {
+	g, h := rangeBases(curve)
+	p := &rangeProver{
+		curve:       curve,
+		h:           h,
+		bits:        make([]bool, c.PredicateRangeBits),
+		blindings:   make([]*ml.Zr, c.PredicateRangeBits),
+		commitments: make([]*ml.G1, c.PredicateRangeBits),
+		proofs:      make([][2]*ml.G1, c.PredicateRangeBits),
+		challenges:  make([]*ml.Zr, c.PredicateRangeBits),
+		responses:   make([]*ml.Zr, c.PredicateRangeBits),
+		witnesses:   make([]*ml.Zr, c.PredicateRangeBits),
+		blinding:    curve.NewZrFromInt(0),
+	}
+
+	power := curve.NewZrFromInt(1)
+	for i := range p.bits {
+		p.bits[i] = difference.Bit(i) == 1
+		random, err := randomZrs(curve, rng, 4)
+		if err != nil {
+			return nil, err
+		}
+		p.blindings[i] = random[0]
+		p.commitments[i] = h.Mul(p.blindings[i])
+		if p.bits[i] {
+			p.commitments[i].Add(g)
+		}
+
+		// the branch of the actual bit is committed, the other one is simulated
+		actual, simulated := 0, 1
+		if p.bits[i] {
+			actual, simulated = 1, 0
+		}
+		p.witnesses[i], p.challenges[i], p.responses[i] = random[1], random[2], random[3]
+		p.proofs[i][actual] = h.Mul(p.witnesses[i])
+		p.proofs[i][simulated] = h.Mul2(p.responses[i], bitStatement(p.commitments[i], g, simulated), p.challenges[i])
+
+		p.blinding = curve.ModAdd(p.blinding, curve.ModMul(power, p.blindings[i], curve.GroupOrder), curve.GroupOrder)
+		power = curve.ModAdd(power, power, curve.GroupOrder)
+	}
+
+	blindingCommitment, err := randomZr(curve, rng)
+	if err != nil {
+		return nil, err
+	}
+	p.blindingCommitment = blindingCommitment
+	p.openingCommitment = signedBase(curve, g, sign).Mul2(claimBlinding, h, p.blindingCommitment)
+
+	return p, nil
+}
+
+// challengeBytes Serialize the commitments of the range proof, to compute the challenge.
+func (p *rangeProver) challengeBytes() []byte {
+	return rangeChallengeBytes(p.commitments, p.proofs, p.openingCommitment)
+}
+
+// generateProof Compute the responses of the range proof for the challenge, and serialize the proof.
+func (p *rangeProver) generateProof(challenge *ml.Zr) []byte {
+	order := p.curve.GroupOrder
+	proof := make([]byte, 0, rangeProofSize(p.curve))
+	for i, bit := range p.bits {
+		// the challenge of the real branch completes the challenge of the simulated one
+		realChallenge := p.curve.ModSub(challenge, p.challenges[i], order)
+		realResponse := p.curve.ModSub(p.witnesses[i], p.curve.ModMul(realChallenge, p.blindings[i], order), order)
+
+		challenge0, response0, response1 := realChallenge, realResponse, p.responses[i]
+		if bit {
+			challenge0, response0, response1 = p.challenges[i], p.responses[i], realResponse
+		}
+
+		proof = append(proof, p.commitments[i].Compressed()...)
+		proof = append(proof, p.proofs[i][0].Compressed()...)
+		proof = append(proof, p.proofs[i][1].Compressed()...)
+		proof = append(proof, challenge0.Bytes()...)
+		proof = append(proof, response0.Bytes()...)
+		proof = append(proof, response1.Bytes()...)
+	}
+
+	blindingResponse := p.curve.ModSub(p.blindingCommitment, p.curve.ModMul(challenge, p.blinding, order), order)
+	proof = append(proof, p.openingCommitment.Compressed()...)
+
+	return append(proof, blindingResponse.Bytes()...)
+}
+
+// A rangeProof is a parsed range proof.
+type rangeProof struct {
+	commitments       []*ml.G1
+	proofs            [][2]*ml.G1
+	challenges        []*ml.Zr // challenge of the branch 0
+	responses         [][2]*ml.Zr
+	openingCommitment *ml.G1
+	blindingResponse  *ml.Zr
+}
+
+// parseRangeProof Parse a range proof generated by rangeProver.
+func parseRangeProof(curve *ml.Curve, data []byte) (*rangeProof, error) {
+	if len(data) != rangeProofSize(curve) {
+		return nil, fmt.Errorf("invalid size of range proof: %d bytes, %d expected", len(data), rangeProofSize(curve))
+	}
+
+	var err error
+	readG1 := func() *ml.G1 {
+		point, parseErr := curve.NewG1FromCompressed(data[:curve.CompressedG1ByteSize])
+		if parseErr != nil && err == nil {
+			err = fmt.Errorf("parse range proof: %w", parseErr)
+		}
+		data = data[curve.CompressedG1ByteSize:]
+		return point
+	}
+	readZr := func() *ml.Zr {
+		scalar := curve.NewZrFromBytes(data[:curve.ScalarByteSize])
+		data = data[curve.ScalarByteSize:]
+		return scalar
+	}
+
+	proof := &rangeProof{
+		commitments: make([]*ml.G1, c.PredicateRangeBits),
+		proofs:      make([][2]*ml.G1, c.PredicateRangeBits),
+		challenges:  make([]*ml.Zr, c.PredicateRangeBits),
+		responses:   make([][2]*ml.Zr, c.PredicateRangeBits),
+	}
+	for i := range proof.commitments {
+		proof.commitments[i] = readG1()
+		proof.proofs[i] = [2]*ml.G1{readG1(), readG1()}
+		proof.challenges[i] = readZr()
+		proof.responses[i] = [2]*ml.Zr{readZr(), readZr()}
+	}
+	proof.openingCommitment = readG1()
+	proof.blindingResponse = readZr()
+	if err != nil {
+		return nil, err
+	}
+
+	return proof, nil
+}
+
+// challengeBytes Serialize the commitments of the range proof, to compute the challenge.
+func (p *rangeProof) challengeBytes() []byte {
+	return rangeChallengeBytes(p.commitments, p.proofs, p.openingCommitment)
+}
+
+// verify Verify the range proof for a challenge.
+//
+//	curve *ml.Curve
+//	challenge *ml.Zr The challenge shared with the proof of knowledge of the signature.
+//	claimResponse *ml.Zr The response of the hidden claim in the proof of knowledge of the signature.
+//	sign int
+//	offset *big.Int
+//
+// returns:
+//
+//	err error if the committed difference sign * claim + offset is not within [0, 2^PredicateRangeBits)
+func (p *rangeProof) verify(curve *ml.Curve, challenge, claimResponse *ml.Zr, sign int, offset *big.Int) error {
+	order := curve.GroupOrder
+	g, h := rangeBases(curve)
+
+	// 1. Every commitment is a commitment to 0 or 1
+	sum := curve.GenG1.Copy()
+	sum.Sub(curve.GenG1)
+	power := curve.NewZrFromInt(1)
+	for i, commitment := range p.commitments {
+		challenges := [2]*ml.Zr{p.challenges[i], curve.ModSub(challenge, p.challenges[i], order)}
+		for branch := range challenges {
+			expected := h.Mul2(p.responses[i][branch], bitStatement(commitment, g, branch), challenges[branch])
+			if !expected.Equals(p.proofs[i][branch]) {
+				return fmt.Errorf("invalid range proof: bit %d", i)
+			}
+		}
+
+		sum.Add(commitment.Mul(power))
+		power = curve.ModAdd(power, power, order)
+	}
+
+	// 2. The sum of the bit commitments minus the offset commits to sign * claim
+	sum.Sub(g.Mul(zrFromBigInt(curve, offset)))
+	expected := signedBase(curve, g, sign).Mul2(claimResponse, h, p.blindingResponse)
+	expected.Add(sum.Mul(challenge))
+	if !expected.Equals(p.openingCommitment) {
+		return fmt.Errorf("invalid range proof: the committed difference does not match the claim")
+	}
+
+	return nil
+}
+
+// bitStatement Compute the point whose discrete logarithm in base h is proven by a branch of a bit proof:
+// the commitment C for the bit 0, C - g for the bit 1.
+func bitStatement(commitment, g *ml.G1, bit int) *ml.G1 {
+	statement := commitment.Copy()
+	if bit == 1 {
+		statement.Sub(g)
+	}
+
+	return statement
+}
+
+// signedBase Return g, or -g if the sign is negative.
+func signedBase(curve *ml.Curve, g *ml.G1, sign int) *ml.G1 {
+	if sign < 0 {
+		return g.Mul(curve.ModNeg(curve.NewZrFromInt(1), curve.GroupOrder))
+	}
+
+	return g.Copy()
+}
+
+// rangeChallengeBytes Serialize the commitments of a range proof.
+func rangeChallengeBytes(commitments []*ml.G1, proofs [][2]*ml.G1, openingCommitment *ml.G1) []byte {
+	data := make([]byte, 0)
+	for i, commitment := range commitments {
+		data = append(data, commitment.Compressed()...)
+		data = append(data, proofs[i][0].Compressed()...)
+		data = append(data, proofs[i][1].Compressed()...)
+	}
+
+	return append(data, openingCommitment.Compressed()...)
+}
+
+// rangeProofSize Compute the size of a serialized range proof.
+func rangeProofSize(curve *ml.Curve) int {
+	bitProofSize := 3*curve.CompressedG1ByteSize + 3*curve.ScalarByteSize
+
+	return c.PredicateRangeBits*bitProofSize + curve.CompressedG1ByteSize + curve.ScalarByteSize
+}
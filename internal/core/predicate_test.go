@@ -0,0 +1,136 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/stretchr/testify/suite"
+)
+
+const birthDateIRI = "http://schema.org/birthDate"
+
+type PredicateTestSuite struct {
+	suite.Suite
+	options    *model.SignatureSuiteOptions
+	publicKey  []byte
+	privateKey []byte
+	credential model.JsonLdCredentialNoProof
+	frame      model.JsonLdFrame
+}
+
+func TestPredicateTestSuite(t *testing.T) {
+	suite.Run(t, new(PredicateTestSuite))
+}
+
+func (s *PredicateTestSuite) SetupTest() {
+	s.options = offlineOptions(s.T())
+	s.options.PredicateClaims = []string{birthDateIRI}
+	s.publicKey = benchmarkPublicKey(s.T())
+	s.privateKey = benchmarkPrivateKey(s.T())
+
+	credentialBytes, err := os.ReadFile("testdata/unsignedCredential.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(credentialBytes, &s.credential))
+
+	// disclose the given name instead of the birth date
+	frameBytes, err := os.ReadFile("testdata/frame.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(frameBytes, &s.frame))
+	subject := s.frame["credentialSubject"].(map[string]interface{})
+	delete(subject, "birthDate")
+	subject["givenName"] = map[string]interface{}{}
+}
+
+func (s *PredicateTestSuite) sign() model.JsonLdCredential {
+	signed, _, err := core.NewSignatureSuite2020(s.publicKey, s.privateKey, s.options).Sign(s.credential)
+	s.Require().NoError(err)
+
+	return signed
+}
+
+func (s *PredicateTestSuite) TestDeriveAndVerify() {
+	ctx := context.Background()
+	signed := s.sign()
+	s.True(core.NewSignatureSuite2020(s.publicKey, nil, s.options).Verify(signed).Success)
+
+	// the issuer and the verifiers must agree on the predicate claims
+	s.False(core.NewSignatureSuite2020(s.publicKey, nil, offlineOptions(s.T())).Verify(signed).Success)
+
+	proofSuite := core.NewSignatureProofSuite2020(s.publicKey, s.options)
+	predicate := model.Predicate{Claim: birthDateIRI, Operator: model.PredicateLessThan, Value: "2008-10-17"}
+	derived, err := proofSuite.DeriveProofWithOptions(ctx, signed, s.frame, []byte("verifier challenge"), &model.DeriveProofOptions{
+		Predicates: []model.Predicate{predicate},
+	})
+	s.Require().NoError(err)
+	s.NotContains(derived["credentialSubject"], "birthDate")
+
+	result := proofSuite.VerifyProofWithOptions(derived, &model.VerifyProofOptions{
+		RequiredPredicates: []model.Predicate{{Claim: birthDateIRI, Operator: model.PredicateLessOrEqual, Value: "2010-01-01"}},
+	})
+	s.True(result.Success, result.Error)
+
+	// a stronger predicate is not proven
+	result = proofSuite.VerifyProofWithOptions(derived, &model.VerifyProofOptions{
+		RequiredPredicates: []model.Predicate{{Claim: birthDateIRI, Operator: model.PredicateLessThan, Value: "1990-01-01"}},
+	})
+	s.ErrorIs(result.Error, model.ErrPredicateNotProven)
+
+	// the proof is bound to the bound of the predicate
+	proof := derived[c.CredentialFieldProof].(model.JsonLdProof)
+	proof[c.CredentialFieldPredicates].([]interface{})[0].(map[string]interface{})["value"] = "2020-01-01"
+	result = proofSuite.VerifyProof(derived)
+	s.False(result.Success)
+
+	// the claims can still be disclosed or hidden without predicates
+	derived, err = proofSuite.DeriveProof(signed, s.frame, []byte("verifier challenge"))
+	s.Require().NoError(err)
+	result = proofSuite.VerifyProof(derived)
+	s.True(result.Success, result.Error)
+	result = proofSuite.VerifyProofWithOptions(derived, &model.VerifyProofOptions{RequiredPredicates: []model.Predicate{predicate}})
+	s.ErrorIs(result.Error, model.ErrPredicateNotProven)
+}
+
+func (s *PredicateTestSuite) TestUnprovable() {
+	ctx := context.Background()
+	signed := s.sign()
+	proofSuite := core.NewSignatureProofSuite2020(s.publicKey, s.options)
+	challenge := []byte("verifier challenge")
+
+	_, err := proofSuite.DeriveProofWithOptions(ctx, signed, s.frame, challenge, &model.DeriveProofOptions{
+		Predicates: []model.Predicate{{Claim: birthDateIRI, Operator: model.PredicateGreaterOrEqual, Value: "2008-10-17"}},
+	})
+	s.ErrorIs(err, model.ErrPredicateNotSatisfied)
+
+	_, err = proofSuite.DeriveProofWithOptions(ctx, signed, s.frame, challenge, &model.DeriveProofOptions{
+		Predicates: []model.Predicate{{Claim: givenNameIRI, Operator: model.PredicateGreaterOrEqual, Value: "0"}},
+	})
+	s.ErrorIs(err, model.ErrPredicateClaimNotEncoded)
+
+	// the encodings are signed as committed messages
+	issuer, err := core.NewSignatureSuite2020WithSigner(ctx, plainSigner{core.NewInMemorySigner(s.publicKey, s.privateKey)}, s.options)
+	s.NoError(err)
+	_, _, err = issuer.Sign(s.credential)
+	s.ErrorIs(err, model.ErrBlindSigningUnsupported)
+}
+
+func (s *PredicateTestSuite) TestHolderBound() {
+	ctx := context.Background()
+	secret, err := core.NewHolderSecret()
+	s.NoError(err)
+	credential := issueHolderBound(s.T(), s.options, s.credential, s.publicKey, s.privateKey, secret)
+
+	proofSuite := core.NewSignatureProofSuite2020(s.publicKey, s.options)
+	derived, err := proofSuite.DeriveProofWithOptions(ctx, credential, s.frame, []byte("verifier challenge"), &model.DeriveProofOptions{
+		HolderSecret: secret,
+		Predicates:   []model.Predicate{{Claim: birthDateIRI, Operator: model.PredicateGreaterThan, Value: "1970-01-01"}},
+	})
+	s.Require().NoError(err)
+
+	result := proofSuite.VerifyProofWithOptions(derived, &model.VerifyProofOptions{RequireHolderBinding: true})
+	s.True(result.Success, result.Error)
+}
@@ -0,0 +1,208 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+)
+
+// A derived proof discloses the statements of the original proof, together with the framed claims. The privacy linter
+// reports the disclosed values that a verifier, or colluding verifiers, can use to correlate the presentations
+// of a credential, although the proofs of knowledge are unlinkable:
+//   - the identifiers of the credential and of its subjects, but the identifier of the issuer shared by all its credentials
+//   - the exact timestamps, e.g. the issuance date or the creation time of the proof
+//   - the verification methods of the proofs not controlled by the issuer, e.g. a key per holder
+//   - the values shared by few holders, according to the frequencies provided by the holder
+
+// LintFrame Report the disclosed values that make the presentations of a credential derived with a frame correlatable.
+//
+//	ctx context.Context
+//	signedCredential model.JsonLdCredential The signed JSON-LD credential.
+//	frameDocument model.JsonLDFrame The frame document.
+//	options *model.PrivacyLintOptions nullable, the frequencies of the values and their threshold
+//
+// returns:
+//
+//	warnings []model.PrivacyWarning sorted by path
+//	err error
+func (s *SignatureProofSuite2020) LintFrame(
+	ctx context.Context,
+	signedCredential model.JsonLdCredential,
+	frameDocument model.JsonLdFrame,
+	options *model.PrivacyLintOptions,
+) ([]model.PrivacyWarning, error) {
+	credWithoutProofs, proofs, err := s.getSupportedProofs(ctx, signedCredential)
+	if err != nil {
+		return nil, err
+	}
+	framedCredential, err := s.frameCredential(ctx, credWithoutProofs, frameDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	return lintFrame(credWithoutProofs, framedCredential, proofs, options), nil
+}
+
+// lintFrame Report the disclosed values of a framed credential and of its proofs that make its presentations correlatable.
+//
+//	credential model.JsonLdCredentialNoProof The unsigned JSON-LD credential.
+//	framedCredential model.JsonLdCredential The frame of the credential.
+//	proofs []model.JsonLdProof The proofs from where the proofs of the frame are derived.
+//	options *model.PrivacyLintOptions nullable
+//
+// returns:
+//
+//	warnings []model.PrivacyWarning sorted by path
+func lintFrame(
+	credential model.JsonLdCredentialNoProof,
+	framedCredential model.JsonLdCredential,
+	proofs []model.JsonLdProof,
+	options *model.PrivacyLintOptions,
+) []model.PrivacyWarning {
+	linter := &privacyLinter{options: options}
+	for key, value := range framedCredential {
+		if key != c.CredentialFieldContext && key != c.CredentialFieldProof {
+			linter.lint(key, key, value)
+		}
+	}
+	// the statements of the proofs are always disclosed
+	for _, proof := range proofs {
+		if created, ok := proof[c.CredentialFieldCreated]; ok {
+			linter.lint(c.CredentialFieldProof+"."+c.CredentialFieldCreated, c.CredentialFieldCreated, created)
+		}
+		if method, ok := proof[c.CredentialFieldVerificationMethod].(string); ok {
+			issuer, _ := credentialIssuerID(credential)
+			linter.lintVerificationMethod(issuer, method)
+		}
+	}
+
+	slices.SortFunc(linter.warnings, func(a, b model.PrivacyWarning) int { return strings.Compare(a.Path, b.Path) })
+
+	return slices.CompactFunc(linter.warnings, func(a, b model.PrivacyWarning) bool { return a == b })
+}
+
+// checkPrivacyWarnings Report the warnings of the privacy linter to the holder, and check that they are acknowledged if required.
+//
+//	warnings []model.PrivacyWarning
+//	options *model.PrivacyLintOptions
+//
+// returns:
+//
+//	err *model.PrivacyLintError if some warnings are not acknowledged
+func checkPrivacyWarnings(warnings []model.PrivacyWarning, options *model.PrivacyLintOptions) error {
+	if options.OnWarnings != nil {
+		options.OnWarnings(warnings)
+	}
+	if !options.RequireAcknowledgement {
+		return nil
+	}
+
+	unacknowledged := make([]model.PrivacyWarning, 0)
+	for _, warning := range warnings {
+		if !slices.Contains(options.Acknowledged, warning.Path) {
+			unacknowledged = append(unacknowledged, warning)
+		}
+	}
+	if len(unacknowledged) > 0 {
+		return &model.PrivacyLintError{Warnings: unacknowledged}
+	}
+
+	return nil
+}
+
+// A privacyLinter collects the warnings on the values of a disclosed document.
+type privacyLinter struct {
+	options  *model.PrivacyLintOptions // nullable
+	warnings []model.PrivacyWarning
+}
+
+// lint Check a disclosed value and, recursively, its members.
+//
+//	path string The dot-separated path of the value.
+//	key string The last key of the path.
+//	value interface{}
+func (l *privacyLinter) lint(path, key string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if literal, ok := v["@value"]; ok {
+			l.lint(path, key, literal)
+			return
+		}
+		for member, item := range v {
+			if member != c.CredentialFieldContext && member != c.CredentialFieldType {
+				l.lint(path+"."+member, member, item)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			l.lint(path, key, item)
+		}
+	case string:
+		l.lintLeaf(path, key, v)
+	case nil:
+	default:
+		l.lintLeaf(path, key, v)
+	}
+}
+
+// lintLeaf Check a disclosed literal.
+func (l *privacyLinter) lintLeaf(path, key string, value interface{}) {
+	text := fmt.Sprint(value)
+
+	// the identifier of the issuer is shared by all its credentials, blank nodes are disclosed with their canonical labels
+	isIssuer := path == c.CredentialFieldIssuer || strings.HasPrefix(path, c.CredentialFieldIssuer+".")
+	isBlankNode := strings.HasPrefix(text, "_:") || strings.HasPrefix(text, blankNodeIDPrefix+"_:")
+	if (key == c.CredentialFieldId || key == "@id") && !isIssuer && !isBlankNode {
+		l.warn(model.PrivacyWarningIdentifier, path, text)
+		return
+	}
+
+	if _, err := time.Parse(time.RFC3339Nano, text); err == nil {
+		l.warn(model.PrivacyWarningTimestamp, path, text)
+		return
+	}
+
+	if !isIssuer {
+		l.lintFrequency(path, value, text)
+	}
+}
+
+// lintVerificationMethod Check the verification method of a proof.
+// A verification method of the issuer is shared by all its credentials, unless the issuer uses a key per holder,
+// in which case the holder can report the method as rare. A did:key designates the signing key itself.
+//
+//	issuer string The identifier of the issuer, empty if the credential has none.
+//	method string The URL of the verification method.
+func (l *privacyLinter) lintVerificationMethod(issuer, method string) {
+	path := c.CredentialFieldProof + "." + c.CredentialFieldVerificationMethod
+	controller, _, _ := strings.Cut(method, "#")
+	if controller != issuer && (issuer != "" || !strings.HasPrefix(controller, "did:key:")) {
+		l.warn(model.PrivacyWarningVerificationMethod, path, method)
+		return
+	}
+
+	l.lintFrequency(path, method, method)
+}
+
+// lintFrequency Report a disclosed value shared by few holders, according to the frequencies provided by the holder.
+func (l *privacyLinter) lintFrequency(path string, value interface{}, text string) {
+	if l.options == nil || l.options.ValueFrequency == nil {
+		return
+	}
+	threshold := l.options.RareValueThreshold
+	if threshold == 0 {
+		threshold = c.DefaultRareValueThreshold
+	}
+	if frequency, known := l.options.ValueFrequency(path, value); known && frequency < threshold {
+		l.warn(model.PrivacyWarningRareValue, path, text)
+	}
+}
+
+func (l *privacyLinter) warn(kind model.PrivacyWarningKind, path, value string) {
+	l.warnings = append(l.warnings, model.PrivacyWarning{Kind: kind, Path: path, Value: value})
+}
@@ -0,0 +1,218 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"testing"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	contexts "github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/context"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/piprate/json-gold/ld"
+	"github.com/stretchr/testify/suite"
+)
+
+type PrivacyLintTestSuite struct {
+	suite.Suite
+	publicKey  []byte
+	proofSuite *core.SignatureProofSuite2020
+	credential model.JsonLdCredential
+	frame      model.JsonLdFrame
+}
+
+func TestPrivacyLintTestSuite(t *testing.T) {
+	suite.Run(t, new(PrivacyLintTestSuite))
+}
+
+func (s *PrivacyLintTestSuite) SetupTest() {
+	options := offlineOptions(s.T())
+	s.publicKey = benchmarkPublicKey(s.T())
+	s.proofSuite = core.NewSignatureProofSuite2020(s.publicKey, options)
+
+	var unsigned model.JsonLdCredentialNoProof
+	credentialBytes, err := os.ReadFile("testdata/unsignedCredential.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(credentialBytes, &unsigned))
+	s.credential, _, err = core.NewSignatureSuite2020(s.publicKey, benchmarkPrivateKey(s.T()), options).Sign(unsigned)
+	s.Require().NoError(err)
+
+	frameBytes, err := os.ReadFile("testdata/frame.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(frameBytes, &s.frame))
+}
+
+func (s *PrivacyLintTestSuite) TestLintFrame() {
+	warnings, err := s.proofSuite.LintFrame(context.Background(), s.credential, s.frame, nil)
+	s.Require().NoError(err)
+
+	kinds := make(map[string]model.PrivacyWarningKind)
+	for _, warning := range warnings {
+		kinds[warning.Path] = warning.Kind
+	}
+	s.Equal(map[string]model.PrivacyWarningKind{
+		"id":                   model.PrivacyWarningIdentifier,
+		"credentialSubject.id": model.PrivacyWarningIdentifier,
+		"issuanceDate":         model.PrivacyWarningTimestamp,
+		"proof.created":        model.PrivacyWarningTimestamp,
+	}, kinds)
+
+	// the birth date is reported as rare when few holders share it
+	warnings, err = s.proofSuite.LintFrame(context.Background(), s.credential, s.frame, &model.PrivacyLintOptions{
+		ValueFrequency: func(path string, value interface{}) (float64, bool) {
+			return 0.001, path == "credentialSubject.birthDate"
+		},
+	})
+	s.Require().NoError(err)
+	s.Contains(warnings, model.PrivacyWarning{Kind: model.PrivacyWarningRareValue, Path: "credentialSubject.birthDate", Value: "1990-11-22"})
+
+	// the blank nodes are framed with their canonical labels, which are not reported
+	unsigned := maps.Clone(s.credential)
+	delete(unsigned, "proof")
+	delete(unsigned, "id")
+	subject := maps.Clone(unsigned["credentialSubject"].(map[string]interface{}))
+	delete(subject, "id")
+	unsigned["credentialSubject"] = subject
+	signed, _, err := core.NewSignatureSuite2020(s.publicKey, benchmarkPrivateKey(s.T()), offlineOptions(s.T())).Sign(unsigned)
+	s.Require().NoError(err)
+	warnings, err = s.proofSuite.LintFrame(context.Background(), signed, s.frame, nil)
+	s.Require().NoError(err)
+	for _, warning := range warnings {
+		s.NotEqual(model.PrivacyWarningIdentifier, warning.Kind, warning.Path)
+	}
+}
+
+func (s *PrivacyLintTestSuite) TestRequireAcknowledgement() {
+	ctx := context.Background()
+	nonce := []byte("verifier challenge")
+
+	var reported []model.PrivacyWarning
+	options := &model.DeriveProofOptions{PrivacyLint: &model.PrivacyLintOptions{
+		RequireAcknowledgement: true,
+		Acknowledged:           []string{"id", "issuanceDate"},
+		OnWarnings:             func(warnings []model.PrivacyWarning) { reported = warnings },
+	}}
+	_, err := s.proofSuite.DeriveProofWithOptions(ctx, s.credential, s.frame, nonce, options)
+	s.ErrorIs(err, model.ErrPrivacyWarningsNotAcknowledged)
+	var lintErr *model.PrivacyLintError
+	s.Require().True(errors.As(err, &lintErr))
+	s.Len(lintErr.Warnings, 2)
+	s.Len(reported, 4)
+
+	// the holder acknowledges the remaining warnings
+	for _, warning := range lintErr.Warnings {
+		options.PrivacyLint.Acknowledged = append(options.PrivacyLint.Acknowledged, warning.Path)
+	}
+	derived, err := s.proofSuite.DeriveProofWithOptions(ctx, s.credential, s.frame, nonce, options)
+	s.Require().NoError(err)
+	result := s.proofSuite.VerifyProof(derived)
+	s.True(result.Success, result.Error)
+
+	// without strict mode the warnings are only reported
+	options.PrivacyLint = &model.PrivacyLintOptions{}
+	_, err = s.proofSuite.DeriveProofWithOptions(ctx, s.credential, s.frame, nonce, options)
+	s.NoError(err)
+}
+
+func (s *PrivacyLintTestSuite) TestVerificationMethod() {
+	ctx := context.Background()
+	credential := maps.Clone(s.credential)
+	delete(credential, "proof")
+	credential["issuer"] = "did:example:issuer"
+
+	sign := func(verificationMethod string) model.JsonLdCredential {
+		options := offlineOptions(s.T())
+		options.VerificationMethod = verificationMethod
+		signed, _, err := core.NewSignatureSuite2020(s.publicKey, benchmarkPrivateKey(s.T()), options).Sign(credential)
+		s.Require().NoError(err)
+		return signed
+	}
+
+	// a key per holder identifies the holder
+	warnings, err := s.proofSuite.LintFrame(ctx, sign("did:example:holder-42#key-1"), s.frame, nil)
+	s.Require().NoError(err)
+	s.Contains(warnings, model.PrivacyWarning{Kind: model.PrivacyWarningVerificationMethod, Path: "proof.verificationMethod", Value: "did:example:holder-42#key-1"})
+
+	// a key of the issuer is shared by its credentials, unless the holder knows it to be rare
+	signed := sign("did:example:issuer#key-1")
+	warnings, err = s.proofSuite.LintFrame(ctx, signed, s.frame, nil)
+	s.Require().NoError(err)
+	for _, warning := range warnings {
+		s.NotEqual("proof.verificationMethod", warning.Path)
+	}
+	warnings, err = s.proofSuite.LintFrame(ctx, signed, s.frame, &model.PrivacyLintOptions{
+		ValueFrequency: func(path string, value interface{}) (float64, bool) {
+			return 0.001, path == "proof.verificationMethod"
+		},
+	})
+	s.Require().NoError(err)
+	s.Contains(warnings, model.PrivacyWarning{Kind: model.PrivacyWarningRareValue, Path: "proof.verificationMethod", Value: "did:example:issuer#key-1"})
+}
+
+func (s *PrivacyLintTestSuite) TestLintDoesNotFrameAgain() {
+	ctx := context.Background()
+	nonce := []byte("verifier challenge")
+	loader := newCountingDocumentLoader(s.T())
+	options := offlineOptions(s.T())
+	options.DocumentLoader = loader
+	proofSuite := core.NewSignatureProofSuite2020(s.publicKey, options)
+
+	_, err := proofSuite.DeriveProofWithOptions(ctx, s.credential, s.frame, nonce, nil)
+	s.Require().NoError(err)
+	loads := loader.loads
+
+	loader.loads = 0
+	derived, err := proofSuite.DeriveProofWithOptions(ctx, s.credential, s.frame, nonce, &model.DeriveProofOptions{
+		PrivacyLint: &model.PrivacyLintOptions{},
+	})
+	s.Require().NoError(err)
+	s.Equal(loads, loader.loads)
+	result := proofSuite.VerifyProof(derived)
+	s.True(result.Success, result.Error)
+}
+
+// A countingDocumentLoader serves the contexts of the test credentials and counts the loads.
+type countingDocumentLoader struct {
+	documents map[string]string
+	loads     int
+}
+
+func newCountingDocumentLoader(t testing.TB) *countingDocumentLoader {
+	documents := map[string]string{
+		c.ContextCredentialV1:  contexts.ContextCredentialsV1,
+		c.ContextSecurityBbsV1: contexts.ContextBbsBlsSignature2020,
+		c.ContextSecurityV2:    contexts.ContextSecurityV2,
+	}
+	// the contexts of offlineOptions
+	for u, file := range map[string]string{
+		c.ContextCitizenshipV1:         "testdata/customResidentCardContext.json",
+		"https://w3id.org/security/v1": "testdata/securityV1Context.json",
+	} {
+		document, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		documents[u] = string(document)
+	}
+
+	return &countingDocumentLoader{documents: documents}
+}
+
+func (l *countingDocumentLoader) LoadDocument(u string) (*ld.RemoteDocument, error) {
+	document, ok := l.documents[u]
+	if !ok {
+		return nil, fmt.Errorf("unexpected context %s", u)
+	}
+	l.loads++
+
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(document), &decoded); err != nil {
+		return nil, err
+	}
+
+	return &ld.RemoteDocument{DocumentURL: u, Document: decoded}, nil
+}
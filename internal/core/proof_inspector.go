@@ -0,0 +1,370 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	ml "github.com/IBM/mathlib"
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/hyperledger/aries-bbs-go/bbs"
+)
+
+// A BbsBlsSignature2020 proof value is the compressed point A followed by the scalars e and s.
+// A BbsBlsSignatureProof2020 proof value starts with the number of signed messages on 2 bytes and the bit vector
+// of the disclosed messages, followed by the proof of knowledge of the signature:
+//   - the compressed points A', Ā and d
+//   - the length of the first proof of knowledge on 4 bytes, and the proof: a commitment, the number of responses
+//     on 4 bytes and the responses for the exponents of A' and h0
+//   - the second proof: a commitment, the number of responses on 4 bytes and the responses for the exponents of d, h0
+//     and the hidden messages
+//
+// The lengths are checked before parsing the points, since the parser of the library does not check them.
+
+// InspectProof Decode the proof value of a signed JSON-LD credential and map the signed messages to its statements.
+// The credential is inspected as not bound to its holder, see InspectProofWithOptions.
+//
+//	ctx context.Context
+//	credential model.JsonLdCredential
+//
+// returns:
+//
+//	inspection *model.ProofInspection
+//	err error wrapping model.ErrMalformedProofValue if the proof value is not well-formed
+func (s *SignatureSuite2020) InspectProof(ctx context.Context, credential model.JsonLdCredential) (*model.ProofInspection, error) {
+	return s.InspectProofWithOptions(ctx, credential, nil)
+}
+
+// InspectProofWithOptions Decode the proof value of a signed JSON-LD credential and map the signed messages to its statements,
+// with the holder binding layout of the signature. The signature is verified over the inspected messages, unless the credential
+// is bound to a holder secret that is not provided: a signature that does not verify is reported, not taken for another layout.
+//
+//	ctx context.Context
+//	credential model.JsonLdCredential
+//	options *model.InspectProofOptions nullable, the credential is then inspected as not bound to its holder
+//
+// returns:
+//
+//	inspection *model.ProofInspection
+//	err error wrapping model.ErrMalformedProofValue if the proof value is not well-formed
+func (s *SignatureSuite2020) InspectProofWithOptions(
+	ctx context.Context,
+	credential model.JsonLdCredential,
+	options *model.InspectProofOptions,
+) (*model.ProofInspection, error) {
+	var secret model.HolderSecret
+	bound := false
+	if options != nil {
+		secret, bound = options.HolderSecret, options.HolderBound || options.HolderSecret != nil
+	}
+	if bound {
+		if err := requireBBSPlus(s.scheme, "holder bindings"); err != nil {
+			return nil, err
+		}
+	}
+
+	proof, ok := credential[c.CredentialFieldProof].(model.JsonLdProof)
+	if !ok {
+		return nil, fmt.Errorf("provided JSON-LD credential doesn't contain object 'proof'")
+	}
+	proofValue, err := decodeProofValue(proof)
+	if err != nil {
+		return nil, err
+	}
+
+	inspection, err := s.scheme.inspectSignature(proofValue)
+	if err != nil {
+		return nil, err
+	}
+
+	signingData, err := s.provideSigningData(ctx, credential)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := encodeClaims(s.predicateClaims, signingData)
+	if err != nil {
+		return nil, err
+	}
+	statements := signingData
+	for _, claim := range encoded {
+		statements = append(statements, encodingDescriptor(claim.claim, claim.datatype), []byte(claim.value.String()))
+	}
+
+	// the messages of a credential bound to its holder end with the marker and the secret, which is not disclosed
+	messagesCount := len(statements)
+	if bound {
+		statements = append(statements, []byte(c.HolderBindingMarker))
+		messagesCount = len(statements) + 1
+	}
+	curve := newBBSCurve()
+	switch {
+	case secret != nil:
+		verified := verifySignature(curve, signatureMessages(curve, signingData, encoded, secret), proofValue, s.publicKey) == nil
+		inspection.Verified = &verified
+	case !bound:
+		verified := s.verifyMessages(signingData, encoded, proofValue) == nil
+		inspection.Verified = &verified
+	}
+
+	inspection.MessagesCount = messagesCount
+	inspection.RevealedIndexes = make([]int, len(statements))
+	for i := range statements {
+		inspection.RevealedIndexes[i] = i
+	}
+	inspection.RevealedBitmap = revealedBitmap(messagesCount, inspection.RevealedIndexes)
+	inspection.Statements = inspectedStatements(inspection.RevealedIndexes, statements)
+
+	return inspection, nil
+}
+
+// InspectProof Decode the derived proofs of a presented JSON-LD credential and map the disclosed messages to its statements.
+//
+//	ctx context.Context
+//	signedCredential model.JsonLdCredential The framed credential together with its proofs.
+//
+// returns:
+//
+//	inspections []*model.ProofInspection one per derived proof
+//	err error wrapping model.ErrMalformedProofValue if a proof value is not well-formed
+func (s *SignatureProofSuite2020) InspectProof(ctx context.Context, signedCredential model.JsonLdCredential) ([]*model.ProofInspection, error) {
+	signedCredentialCopy := deepCopyMap(signedCredential)
+	proofs, err := s.getDerivedProofs(signedCredentialCopy)
+	if err != nil {
+		return nil, err
+	}
+	delete(signedCredentialCopy, c.CredentialFieldProof)
+	credentialStatements, err := s.createVerifyDocumentData(ctx, signedCredentialCopy)
+	if err != nil {
+		return nil, err
+	}
+
+	inspections := make([]*model.ProofInspection, 0, len(proofs))
+	for _, proof := range proofs {
+		proofValue, err := decodeProofValue(proof)
+		if err != nil {
+			return nil, err
+		}
+		inspection, err := s.scheme.inspectProof(proofValue)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := s.prepareVerification(ctx, proof, credentialStatements, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(data.statements) != len(inspection.RevealedIndexes) {
+			return nil, fmt.Errorf("%w: %d disclosed messages, %d statements presented",
+				model.ErrMalformedProofValue, len(inspection.RevealedIndexes), len(data.statements))
+		}
+		inspection.Nonce = data.nonce
+		inspection.Statements = inspectedStatements(inspection.RevealedIndexes, data.statements)
+		inspections = append(inspections, inspection)
+	}
+
+	return inspections, nil
+}
+
+// decodeProofValue Decode the base64 proof value of a proof.
+func decodeProofValue(proof model.JsonLdProof) ([]byte, error) {
+	proofValueB64, ok := proof[c.CredentialFieldProofValue].(string)
+	if !ok {
+		return nil, fmt.Errorf("proof doesn't contain field '%s'", c.CredentialFieldProofValue)
+	}
+	proofValue, err := base64.StdEncoding.DecodeString(proofValueB64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: not in base64: %s", model.ErrMalformedProofValue, err.Error())
+	}
+
+	return proofValue, nil
+}
+
+// inspectSignatureValue Decode the proof value of a BbsBlsSignature2020 signature.
+//
+//	curve *ml.Curve
+//	proofValue []byte
+//
+// returns:
+//
+//	inspection *model.ProofInspection without the messages
+//	err error wrapping model.ErrMalformedProofValue
+func inspectSignatureValue(curve *ml.Curve, proofValue []byte) (*model.ProofInspection, error) {
+	reader := &proofValueReader{data: proofValue}
+	reader.read("A", curve.CompressedG1ByteSize)
+	reader.read("e", curve.ScalarByteSize)
+	reader.read("s", curve.ScalarByteSize)
+	if err := reader.finish(); err != nil {
+		return nil, err
+	}
+	if _, err := bbs.NewBBSLib(curve).ParseSignature(proofValue); err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrMalformedProofValue, err.Error())
+	}
+
+	return &model.ProofInspection{
+		Type:       c.CredentialProofTypeBbsBlsSig2020,
+		Size:       len(proofValue),
+		Components: reader.components,
+	}, nil
+}
+
+// inspectDerivedProofValue Decode the proof value of a BbsBlsSignatureProof2020 derived proof.
+//
+//	curve *ml.Curve
+//	proofValue []byte
+//
+// returns:
+//
+//	inspection *model.ProofInspection without the nonce and the statements
+//	err error wrapping model.ErrMalformedProofValue
+func inspectDerivedProofValue(curve *ml.Curve, proofValue []byte) (*model.ProofInspection, error) {
+	reader, messagesCount, revealed, err := readProofPayload(proofValue)
+	if err != nil {
+		return nil, err
+	}
+	hidden := messagesCount - len(revealed)
+	payloadSize := reader.offset
+
+	reader.read("A'", curve.CompressedG1ByteSize)
+	reader.read("Ā", curve.CompressedG1ByteSize)
+	reader.read("d", curve.CompressedG1ByteSize)
+	proof1Size := int(reader.readUint("proofVC1Length", 4))
+	reader.readProofG1(curve, "proofVC1", proof1Size, 2)
+	reader.readProofG1(curve, "proofVC2", len(proofValue)-reader.offset, 2+hidden)
+	if err := reader.finish(); err != nil {
+		return nil, err
+	}
+	if _, err := bbs.NewBBSLib(curve).ParseSignatureProof(proofValue[payloadSize:]); err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrMalformedProofValue, err.Error())
+	}
+
+	return derivedProofInspection(proofValue, reader, messagesCount, revealed), nil
+}
+
+// readProofPayload Consume the number of signed messages and the bit vector of the disclosed messages
+// that start the proof value of a derived proof.
+//
+//	proofValue []byte
+//
+// returns:
+//
+//	reader *proofValueReader positioned at the proof of knowledge
+//	messagesCount int
+//	revealed []int the sorted indexes of the disclosed messages
+//	err error wrapping model.ErrMalformedProofValue
+func readProofPayload(proofValue []byte) (*proofValueReader, int, []int, error) {
+	reader := &proofValueReader{data: proofValue}
+	messagesCount := int(reader.readUint("messagesCount", 2))
+	reader.read("revealedBitmap", messagesCount/8+1)
+	if reader.err != nil {
+		return nil, 0, nil, reader.err
+	}
+
+	// ParsePoKPayload reverses the bit vector of the revealed messages in place
+	payload, err := bbs.ParsePoKPayload(bytes.Clone(proofValue))
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("%w: %s", model.ErrMalformedProofValue, err.Error())
+	}
+	for _, index := range payload.Revealed {
+		if index >= messagesCount {
+			return nil, 0, nil, fmt.Errorf("%w: message %d disclosed, only %d messages signed", model.ErrMalformedProofValue, index, messagesCount)
+		}
+	}
+
+	return reader, messagesCount, payload.Revealed, nil
+}
+
+// derivedProofInspection Build the inspection of a derived proof value read entirely.
+func derivedProofInspection(proofValue []byte, reader *proofValueReader, messagesCount int, revealed []int) *model.ProofInspection {
+	return &model.ProofInspection{
+		Type:            c.CredentialDerivedProofTypeBbsBlsSig2020,
+		Size:            len(proofValue),
+		Components:      reader.components,
+		MessagesCount:   messagesCount,
+		RevealedIndexes: revealed,
+		RevealedBitmap:  revealedBitmap(messagesCount, revealed),
+	}
+}
+
+// A proofValueReader splits a proof value into its components, keeping the first error.
+type proofValueReader struct {
+	data       []byte
+	offset     int
+	components []model.ProofComponent
+	err        error
+}
+
+// read Consume the next component.
+func (r *proofValueReader) read(name string, size int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if size < 0 || r.offset+size > len(r.data) {
+		r.err = fmt.Errorf("%w: %s needs %d bytes at offset %d, %d bytes available",
+			model.ErrMalformedProofValue, name, size, r.offset, len(r.data)-r.offset)
+		return nil
+	}
+	r.components = append(r.components, model.ProofComponent{Name: name, Offset: r.offset, Size: size})
+	r.offset += size
+
+	return r.data[r.offset-size : r.offset]
+}
+
+// readUint Consume the next component, a big-endian unsigned integer of 2 or 4 bytes.
+func (r *proofValueReader) readUint(name string, size int) uint32 {
+	data := r.read(name, size)
+	if data == nil {
+		return 0
+	}
+	if size == 2 {
+		return uint32(binary.BigEndian.Uint16(data))
+	}
+
+	return binary.BigEndian.Uint32(data)
+}
+
+// readProofG1 Consume a proof of knowledge of exponents: a commitment, the number of responses and the responses.
+func (r *proofValueReader) readProofG1(curve *ml.Curve, name string, size, expectedResponses int) {
+	start := r.offset
+	r.read(name+".commitment", curve.CompressedG1ByteSize)
+	responses := int(r.readUint(name+".responsesCount", 4))
+	if r.err == nil && responses != expectedResponses {
+		r.err = fmt.Errorf("%w: %s has %d responses, %d expected", model.ErrMalformedProofValue, name, responses, expectedResponses)
+	}
+	r.read(name+".responses", responses*curve.ScalarByteSize)
+	if r.err == nil && r.offset-start != size {
+		r.err = fmt.Errorf("%w: %s is %d bytes, %d expected", model.ErrMalformedProofValue, name, r.offset-start, size)
+	}
+}
+
+// finish Check that the whole proof value has been consumed.
+func (r *proofValueReader) finish() error {
+	if r.err == nil && r.offset != len(r.data) {
+		r.err = fmt.Errorf("%w: %d trailing bytes", model.ErrMalformedProofValue, len(r.data)-r.offset)
+	}
+
+	return r.err
+}
+
+// revealedBitmap Represent the disclosed messages with one character per message.
+func revealedBitmap(messagesCount int, revealed []int) string {
+	bitmap := []byte(strings.Repeat("0", messagesCount))
+	for _, index := range revealed {
+		bitmap[index] = '1'
+	}
+
+	return string(bitmap)
+}
+
+// inspectedStatements Map the disclosed messages to their statements, in order.
+func inspectedStatements(revealed []int, statements [][]byte) []model.InspectedStatement {
+	inspected := make([]model.InspectedStatement, len(revealed))
+	for i, index := range revealed {
+		inspected[i] = model.InspectedStatement{Index: index, Statement: string(statements[i])}
+	}
+
+	return inspected
+}
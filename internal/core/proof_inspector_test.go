@@ -0,0 +1,165 @@
+package core_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProofInspectorTestSuite struct {
+	suite.Suite
+	signatureSuite *core.SignatureSuite2020
+	proofSuite     *core.SignatureProofSuite2020
+	credential     model.JsonLdCredential
+	frame          model.JsonLdFrame
+}
+
+func TestProofInspectorTestSuite(t *testing.T) {
+	suite.Run(t, new(ProofInspectorTestSuite))
+}
+
+func (s *ProofInspectorTestSuite) SetupTest() {
+	options := offlineOptions(s.T())
+	publicKey := benchmarkPublicKey(s.T())
+	s.signatureSuite = core.NewSignatureSuite2020(publicKey, benchmarkPrivateKey(s.T()), options)
+	s.proofSuite = core.NewSignatureProofSuite2020(publicKey, options)
+
+	var unsigned model.JsonLdCredentialNoProof
+	credentialBytes, err := os.ReadFile("testdata/unsignedCredential.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(credentialBytes, &unsigned))
+	s.credential, _, err = s.signatureSuite.Sign(unsigned)
+	s.Require().NoError(err)
+
+	frameBytes, err := os.ReadFile("testdata/frame.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(frameBytes, &s.frame))
+}
+
+func (s *ProofInspectorTestSuite) TestInspectSignature() {
+	inspection, err := s.signatureSuite.InspectProof(context.Background(), s.credential)
+	s.Require().NoError(err)
+	s.Equal(c.CredentialProofTypeBbsBlsSig2020, inspection.Type)
+	s.Equal(112, inspection.Size)
+	s.Len(inspection.Components, 3)
+	s.Equal(strings.Repeat("1", inspection.MessagesCount), inspection.RevealedBitmap)
+	s.Len(inspection.Statements, inspection.MessagesCount)
+	s.Require().NotNil(inspection.Verified)
+	s.True(*inspection.Verified)
+
+	// a signature that does not verify is reported as such
+	s.credential["credentialSubject"].(map[string]interface{})["familyName"] = "Doe"
+	inspection, err = s.signatureSuite.InspectProof(context.Background(), s.credential)
+	s.Require().NoError(err)
+	s.Equal(strings.Repeat("1", inspection.MessagesCount), inspection.RevealedBitmap)
+	s.Require().NotNil(inspection.Verified)
+	s.False(*inspection.Verified)
+}
+
+func (s *ProofInspectorTestSuite) TestInspectDerivedProof() {
+	ctx := context.Background()
+	derived, err := s.proofSuite.DeriveProof(s.credential, s.frame, []byte("verifier challenge"))
+	s.Require().NoError(err)
+
+	inspections, err := s.proofSuite.InspectProof(ctx, derived)
+	s.Require().NoError(err)
+	s.Require().Len(inspections, 1)
+	inspection := inspections[0]
+	s.Equal(c.CredentialDerivedProofTypeBbsBlsSig2020, inspection.Type)
+	s.Equal([]byte("verifier challenge"), inspection.Nonce)
+	s.Len(inspection.RevealedBitmap, inspection.MessagesCount)
+	s.Equal(len(inspection.RevealedIndexes), strings.Count(inspection.RevealedBitmap, "1"))
+	s.Less(len(inspection.RevealedIndexes), inspection.MessagesCount)
+
+	// the disclosed birth date is mapped to its index among the signed messages
+	signingData, err := s.signatureSuite.ProvideSigningData(s.credential)
+	s.Require().NoError(err)
+	var found bool
+	for _, statement := range inspection.Statements {
+		s.Equal(string(signingData[statement.Index]), statement.Statement)
+		found = found || strings.Contains(statement.Statement, "birthDate")
+	}
+	s.True(found)
+}
+
+func (s *ProofInspectorTestSuite) TestInspectHolderBoundSignature() {
+	ctx := context.Background()
+	secret, err := core.NewHolderSecret()
+	s.Require().NoError(err)
+	var unsigned model.JsonLdCredentialNoProof
+	credentialBytes, err := os.ReadFile("testdata/unsignedCredential.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(credentialBytes, &unsigned))
+	credential := issueHolderBound(s.T(), offlineOptions(s.T()), unsigned, benchmarkPublicKey(s.T()), benchmarkPrivateKey(s.T()), secret)
+
+	// without the layout, the signature is inspected as not bound, and does not verify
+	signingData, err := s.signatureSuite.ProvideSigningData(credential)
+	s.Require().NoError(err)
+	inspection, err := s.signatureSuite.InspectProof(ctx, credential)
+	s.Require().NoError(err)
+	s.Equal(len(signingData), inspection.MessagesCount)
+	s.Require().NotNil(inspection.Verified)
+	s.False(*inspection.Verified)
+
+	// the statements are followed by the disclosed marker and by the hidden secret
+	inspection, err = s.signatureSuite.InspectProofWithOptions(ctx, credential, &model.InspectProofOptions{HolderSecret: secret})
+	s.Require().NoError(err)
+	s.Require().NotNil(inspection.Verified)
+	s.True(*inspection.Verified)
+	s.Equal(len(signingData)+2, inspection.MessagesCount)
+	s.Equal(strings.Repeat("1", inspection.MessagesCount-1)+"0", inspection.RevealedBitmap)
+	s.Require().Len(inspection.Statements, inspection.MessagesCount-1)
+	s.Equal(model.InspectedStatement{Index: len(signingData), Statement: c.HolderBindingMarker}, inspection.Statements[len(signingData)])
+
+	// the same layout without the secret, or with another secret
+	bound, err := s.signatureSuite.InspectProofWithOptions(ctx, credential, &model.InspectProofOptions{HolderBound: true})
+	s.Require().NoError(err)
+	s.Nil(bound.Verified)
+	s.Equal(inspection.RevealedBitmap, bound.RevealedBitmap)
+	other, err := core.NewHolderSecret()
+	s.Require().NoError(err)
+	bound, err = s.signatureSuite.InspectProofWithOptions(ctx, credential, &model.InspectProofOptions{HolderSecret: other})
+	s.Require().NoError(err)
+	s.Require().NotNil(bound.Verified)
+	s.False(*bound.Verified)
+	s.Equal(inspection.RevealedBitmap, bound.RevealedBitmap)
+
+	// the derived proofs disclose the messages of the same layout
+	derived, err := s.proofSuite.DeriveProofWithOptions(ctx, credential, s.frame, []byte("verifier challenge"), &model.DeriveProofOptions{HolderSecret: secret})
+	s.Require().NoError(err)
+	inspections, err := s.proofSuite.InspectProof(ctx, derived)
+	s.Require().NoError(err)
+	s.Require().Len(inspections, 1)
+	s.Equal(inspection.MessagesCount, inspections[0].MessagesCount)
+	s.Equal(byte('1'), inspections[0].RevealedBitmap[inspection.MessagesCount-2])
+	s.Equal(byte('0'), inspections[0].RevealedBitmap[inspection.MessagesCount-1])
+}
+
+func (s *ProofInspectorTestSuite) TestMalformedProofValue() {
+	ctx := context.Background()
+	derived, err := s.proofSuite.DeriveProof(s.credential, s.frame, []byte("verifier challenge"))
+	s.Require().NoError(err)
+	proof := derived[c.CredentialFieldProof].(model.JsonLdProof)
+	proofValue, err := base64.StdEncoding.DecodeString(proof[c.CredentialFieldProofValue].(string))
+	s.Require().NoError(err)
+
+	// truncated or extended proof values are rejected without being parsed
+	for _, malformed := range [][]byte{proofValue[:1], proofValue[:len(proofValue)/2], proofValue[:len(proofValue)-1], append(proofValue, 0)} {
+		proof[c.CredentialFieldProofValue] = base64.StdEncoding.EncodeToString(malformed)
+		_, err = s.proofSuite.InspectProof(ctx, derived)
+		s.ErrorIs(err, model.ErrMalformedProofValue)
+	}
+
+	signatureProof := s.credential[c.CredentialFieldProof].(model.JsonLdProof)
+	signatureProof[c.CredentialFieldProofValue] = base64.StdEncoding.EncodeToString(make([]byte, 100))
+	_, err = s.signatureSuite.InspectProof(ctx, s.credential)
+	s.ErrorIs(err, model.ErrMalformedProofValue)
+}
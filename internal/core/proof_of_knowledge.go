@@ -0,0 +1,319 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	ml "github.com/IBM/mathlib"
+	"github.com/hyperledger/aries-bbs-go/bbs"
+)
+
+// The proofs of knowledge of a signature combined with other proofs, e.g. linked proofs and predicate proofs,
+// are generated with a challenge computed over the commitments of all the proofs. A hidden message shared with
+// another proof is committed with a blinding factor chosen by the caller, so that its response,
+// blinding - challenge * message, can be checked by the other proof.
+
+// publicKeyGenerators Compute the generators of a public key.
+//
+//	lib *bbs.BBSLib
+//	publicKey []byte
+//	messagesCount int
+//
+// returns:
+//
+//	generators *bbs.PublicKeyWithGenerators
+//	err error
+func publicKeyGenerators(lib *bbs.BBSLib, publicKey []byte, messagesCount int) (*bbs.PublicKeyWithGenerators, error) {
+	parsed, err := lib.UnmarshalPublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+	generators, err := parsed.ToPublicKeyWithGenerators(messagesCount)
+	if err != nil {
+		return nil, fmt.Errorf("build generators from public key: %w", err)
+	}
+
+	return generators, nil
+}
+
+// A proofOfKnowledge is the commitment of a prover to a proof of knowledge of a signature, as bbs.PoKOfSignature,
+// with its random factors drawn from a chosen source.
+type proofOfKnowledge struct {
+	aPrime   *ml.G1
+	aBar     *ml.G1
+	d        *ml.G1
+	vc1      *bbs.ProverCommittedG1
+	secrets1 []*ml.Zr
+	vc2      *bbs.ProverCommittedG1
+	secrets2 []*ml.Zr
+}
+
+// newProofOfKnowledge Commit to a proof of knowledge of a signature, with the given blinding factors for hidden messages.
+//
+//	curve *ml.Curve
+//	lib *bbs.BBSLib The library of the curve.
+//	rng io.Reader The source of the random factors.
+//	generators *bbs.PublicKeyWithGenerators
+//	signature []byte
+//	messages []*bbs.SignatureMessage All the signed messages.
+//	revealed []int The sorted indexes of the disclosed messages.
+//	blindings map[int]*ml.Zr The blinding factors of hidden messages, by index. The others are random.
+//
+// returns:
+//
+//	proofOfKnowledge *proofOfKnowledge
+//	err error if the signature is not valid
+func newProofOfKnowledge(
+	curve *ml.Curve,
+	lib *bbs.BBSLib,
+	rng io.Reader,
+	generators *bbs.PublicKeyWithGenerators,
+	signature []byte,
+	messages []*bbs.SignatureMessage,
+	revealed []int,
+	blindings map[int]*ml.Zr,
+) (*proofOfKnowledge, error) {
+	parsed, err := lib.ParseSignature(signature)
+	if err != nil {
+		return nil, fmt.Errorf("parse signature: %w", err)
+	}
+	if err := parsed.Verify(messages, generators); err != nil {
+		return nil, fmt.Errorf("init proof of knowledge signature: verify input signature: %w", err)
+	}
+	if len(messages) < len(revealed) {
+		return nil, fmt.Errorf("init proof of knowledge signature: %d revealed indexes is larger than %d messages", len(revealed), len(messages))
+	}
+
+	// A' = A * r1, Ā = b * r1 - A' * e, d = b * r1 - h0 * r2, with b = g1 * h0^s * h_i^m_i
+	b := bbs.ComputeB(parsed.S, messages, generators, curve)
+	r1, err := randomZr(curve, rng)
+	if err != nil {
+		return nil, err
+	}
+	r2, err := randomZr(curve, rng)
+	if err != nil {
+		return nil, err
+	}
+	aPrime := parsed.A.Mul(r1)
+	aBar := b.Mul(r1)
+	aBar.Sub(aPrime.Mul(parsed.E))
+	r2Neg := r2.Copy()
+	r2Neg.Neg()
+	dBuilder := bbs.NewCommitmentBuilder(2)
+	dBuilder.Add(b, r1)
+	dBuilder.Add(generators.H0, r2Neg)
+	d := dBuilder.Build()
+
+	// s' = s - r2 / r1
+	r3 := r1.Copy()
+	r3.InvModP(curve.GroupOrder)
+	sPrime := r2.Mul(r3)
+	sPrime.Neg()
+	sPrime = sPrime.Plus(parsed.S)
+	r3Neg := r3.Copy()
+	r3Neg.Neg()
+	eNeg := parsed.E.Copy()
+	eNeg.Neg()
+
+	// 1. A' * -e + h0 * r2 = Ā - d
+	vc1, err := commitG1(curve, rng, []*ml.G1{aPrime, generators.H0}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. d * -r3 + h0 * s' + h_j * m_j = g1 + h_i * m_i, for the hidden messages j and the revealed messages i
+	bases := []*ml.G1{d, generators.H0}
+	fixed := make(map[int]*ml.Zr, len(blindings))
+	secrets2 := []*ml.Zr{r3Neg, sPrime}
+	disclosed := make(map[int]bool, len(revealed))
+	for _, index := range revealed {
+		disclosed[messages[index].Idx] = true
+	}
+	for _, message := range messages {
+		if disclosed[message.Idx] {
+			continue
+		}
+		if blinding, ok := blindings[message.Idx]; ok {
+			fixed[len(bases)] = blinding
+		}
+		bases = append(bases, generators.H[message.Idx])
+		secrets2 = append(secrets2, message.FR.Copy())
+	}
+	vc2, err := commitG1(curve, rng, bases, fixed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proofOfKnowledge{
+		aPrime:   aPrime,
+		aBar:     aBar,
+		d:        d,
+		vc1:      vc1,
+		secrets1: []*ml.Zr{eNeg, r2},
+		vc2:      vc2,
+		secrets2: secrets2,
+	}, nil
+}
+
+// commitG1 Commit to secrets with random blinding factors, except the given ones.
+//
+//	curve *ml.Curve
+//	rng io.Reader
+//	bases []*ml.G1
+//	fixed map[int]*ml.Zr nullable, the blinding factors that are not random, by base index
+func commitG1(curve *ml.Curve, rng io.Reader, bases []*ml.G1, fixed map[int]*ml.Zr) (*bbs.ProverCommittedG1, error) {
+	blindings := make([]*ml.Zr, len(bases))
+	builder := bbs.NewCommitmentBuilder(len(bases))
+	for i, base := range bases {
+		blinding, ok := fixed[i]
+		if !ok {
+			var err error
+			if blinding, err = randomZr(curve, rng); err != nil {
+				return nil, err
+			}
+		}
+		blindings[i] = blinding
+		builder.Add(base, blinding)
+	}
+
+	return &bbs.ProverCommittedG1{
+		Bases:           bases,
+		BlindingFactors: blindings,
+		Commitment:      builder.Build(),
+	}, nil
+}
+
+// ToBytes Serialize the commitments of the proof, to compute the challenge, as bbs.PoKOfSignature.
+func (p *proofOfKnowledge) ToBytes() []byte {
+	challengeBytes := p.aBar.Bytes()
+	challengeBytes = append(challengeBytes, p.vc1.ToBytes()...)
+
+	return append(challengeBytes, p.vc2.ToBytes()...)
+}
+
+// GenerateProof Generate the proof for a challenge, serialized as bbs.PoKOfSignatureProof.
+func (p *proofOfKnowledge) GenerateProof(challenge *ml.Zr) []byte {
+	proofVC1 := p.vc1.GenerateProof(challenge, p.secrets1).ToBytes()
+
+	proof := make([]byte, 0)
+	proof = append(proof, p.aPrime.Compressed()...)
+	proof = append(proof, p.aBar.Compressed()...)
+	proof = append(proof, p.d.Compressed()...)
+	proof = binary.BigEndian.AppendUint32(proof, uint32(len(proofVC1)))
+	proof = append(proof, proofVC1...)
+
+	return append(proof, p.vc2.GenerateProof(challenge, p.secrets2).ToBytes()...)
+}
+
+// proofOfKnowledgeValue Serialize a proof of knowledge of a signature, in the format of the derived proofs.
+//
+//	proof []byte The serialized proof of knowledge.
+//	messagesCount int
+//	revealed []int The sorted indexes of the disclosed messages.
+func proofOfKnowledgeValue(proof []byte, messagesCount int, revealed []int) ([]byte, error) {
+	payload, err := bbs.NewPoKPayload(messagesCount, revealed).ToBytes()
+	if err != nil {
+		return nil, fmt.Errorf("derive proof: %w", err)
+	}
+
+	return append(payload, proof...), nil
+}
+
+// proofOfKnowledgeChallenge Compute the challenge of combined proofs, from their commitments and the nonce of the verifier.
+func proofOfKnowledgeChallenge(curve *ml.Curve, challengeBytes, nonceBytes []byte) *ml.Zr {
+	challengeBytes = append(challengeBytes, bbs.ParseProofNonce(nonceBytes, curve).ToBytes()...)
+
+	return bbs.FrFromOKM(challengeBytes, curve)
+}
+
+// hiddenMessageResponse Retrieve the response of a hidden message in a proof of knowledge of a signature.
+// The responses of the hidden messages follow the responses of the two blinding factors of the signature.
+//
+//	proof *bbs.PoKOfSignatureProof
+//	revealed map[int]*bbs.SignatureMessage The disclosed messages, by index.
+//	index int The index of the hidden message.
+//
+// returns:
+//
+//	response *ml.Zr
+//	err error if the message is disclosed or the response is missing
+func hiddenMessageResponse(proof *bbs.PoKOfSignatureProof, revealed map[int]*bbs.SignatureMessage, index int) (*ml.Zr, error) {
+	if _, disclosed := revealed[index]; disclosed || index < 0 {
+		return nil, fmt.Errorf("message %d is not hidden", index)
+	}
+
+	responseIndex := 2
+	for hidden := 0; hidden < index; hidden++ {
+		if _, disclosed := revealed[hidden]; !disclosed {
+			responseIndex++
+		}
+	}
+	if responseIndex >= len(proof.ProofVC2.Responses) {
+		return nil, fmt.Errorf("message %d is out of range", index)
+	}
+
+	return proof.ProofVC2.Responses[responseIndex], nil
+}
+
+// A parsedProofOfKnowledge is the proof of knowledge of a derived proof, with the messages it discloses.
+type parsedProofOfKnowledge struct {
+	signatureProof *bbs.PoKOfSignatureProof
+	generators     *bbs.PublicKeyWithGenerators
+	revealed       map[int]*bbs.SignatureMessage // the disclosed messages, by index
+	messages       []*bbs.SignatureMessage       // the disclosed messages, in order
+}
+
+// parseProofOfKnowledge Parse the proof of knowledge of a derived proof.
+//
+//	curve *ml.Curve
+//	lib *bbs.BBSLib The library of the curve.
+//	publicKey []byte
+//	data *verificationData
+//
+// returns:
+//
+//	proof *parsedProofOfKnowledge
+//	err error
+func parseProofOfKnowledge(curve *ml.Curve, lib *bbs.BBSLib, publicKey []byte, data *verificationData) (*parsedProofOfKnowledge, error) {
+	// ParsePoKPayload reverses the bit vector of the revealed messages in place
+	payload, err := bbs.ParsePoKPayload(bytes.Clone(data.proofValue))
+	if err != nil {
+		return nil, fmt.Errorf("parse signature proof: %w", err)
+	}
+	if len(payload.Revealed) != len(data.statements) {
+		return nil, fmt.Errorf("%d disclosed messages, %d expected", len(data.statements), len(payload.Revealed))
+	}
+	signatureProof, err := lib.ParseSignatureProof(data.proofValue[payload.LenInBytes():])
+	if err != nil {
+		return nil, fmt.Errorf("parse signature proof: %w", err)
+	}
+	generators, err := publicKeyGenerators(lib, publicKey, payload.MessagesCount)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := bbs.MessagesToFr(data.statements, curve)
+	revealed := make(map[int]*bbs.SignatureMessage, len(messages))
+	for i, index := range payload.Revealed {
+		revealed[index] = messages[i]
+	}
+
+	return &parsedProofOfKnowledge{
+		signatureProof: signatureProof,
+		generators:     generators,
+		revealed:       revealed,
+		messages:       messages,
+	}, nil
+}
+
+// challengeBytes Retrieve the commitments of the proof, to compute the challenge.
+func (p *parsedProofOfKnowledge) challengeBytes() []byte {
+	return p.signatureProof.GetBytesForChallenge(p.revealed, p.generators)
+}
+
+// verify Verify the proof of knowledge for a challenge.
+func (p *parsedProofOfKnowledge) verify(challenge *ml.Zr) error {
+	return p.signatureProof.Verify(challenge, p.generators, p.revealed, p.messages)
+}
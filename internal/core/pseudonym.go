@@ -0,0 +1,98 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+
+	ml "github.com/IBM/mathlib"
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+)
+
+// The pseudonym of a holder for a verifier is P = H(verifierID) * secret, where H hashes the identifier of the verifier
+// to G1 and secret is the holder secret, signed as the last message of the credentials bound to the holder.
+// The proof of knowledge of the secret shares its blinding factor r with the proof of knowledge of the signature:
+// the holder commits to T = H(verifierID) * r, and the verifier checks T = H(verifierID) * response + P * challenge
+// with the response of the hidden secret.
+
+// pseudonymBase Hash the identifier of a verifier to the base of its pseudonyms.
+func pseudonymBase(curve *ml.Curve, verifierID string) *ml.G1 {
+	return curve.HashToG1WithDomain([]byte(verifierID), []byte(c.PseudonymDomain))
+}
+
+// newPseudonym Compute the pseudonym of a holder for a verifier, and commit to the proof of knowledge of the secret.
+//
+//	curve *ml.Curve
+//	verifierID string
+//	secret *ml.Zr The holder secret, as signed.
+//	blinding *ml.Zr The blinding factor of the secret in the proof of knowledge of the signature.
+func newPseudonym(curve *ml.Curve, verifierID string, secret, blinding *ml.Zr) *model.Pseudonym {
+	base := pseudonymBase(curve, verifierID)
+
+	return &model.Pseudonym{
+		VerifierID: verifierID,
+		Value:      base.Mul(secret).Compressed(),
+		Proof:      base.Mul(blinding).Compressed(),
+	}
+}
+
+// pseudonymChallengeBytes Serialize a pseudonym and the commitment of its proof, to compute the challenge.
+func pseudonymChallengeBytes(pseudonym *model.Pseudonym) []byte {
+	data := []byte(c.PseudonymDomain + " " + pseudonym.VerifierID + " ")
+	data = append(data, pseudonym.Value...)
+
+	return append(data, pseudonym.Proof...)
+}
+
+// verifyPseudonym Verify that a pseudonym is computed from the hidden holder secret.
+//
+//	curve *ml.Curve
+//	pseudonym *model.Pseudonym
+//	challenge *ml.Zr The challenge shared with the proof of knowledge of the signature.
+//	secretResponse *ml.Zr The response of the hidden secret in the proof of knowledge of the signature.
+//
+// returns:
+//
+//	err error
+func verifyPseudonym(curve *ml.Curve, pseudonym *model.Pseudonym, challenge, secretResponse *ml.Zr) error {
+	value, err := curve.NewG1FromCompressed(pseudonym.Value)
+	if err != nil {
+		return fmt.Errorf("parse pseudonym: %w", err)
+	}
+	commitment, err := curve.NewG1FromCompressed(pseudonym.Proof)
+	if err != nil {
+		return fmt.Errorf("parse pseudonym proof: %w", err)
+	}
+
+	expected := pseudonymBase(curve, pseudonym.VerifierID).Mul2(secretResponse, value, challenge)
+	if !expected.Equals(commitment) {
+		return fmt.Errorf("invalid pseudonym proof")
+	}
+
+	return nil
+}
+
+// decodePseudonym Parse the pseudonym embedded in a derived proof.
+//
+//	value interface{} nil if the derived proof does not carry a pseudonym
+//
+// returns:
+//
+//	pseudonym *model.Pseudonym nil if the derived proof does not carry a pseudonym
+//	err error
+func decodePseudonym(value interface{}) (*model.Pseudonym, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var pseudonym model.Pseudonym
+	if err := json.Unmarshal(valueBytes, &pseudonym); err != nil {
+		return nil, fmt.Errorf("The pseudonym is not correctly formatted: %w", err)
+	}
+
+	return &pseudonym, nil
+}
@@ -0,0 +1,104 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	shopVerifierID = "https://shop.example"
+	bankVerifierID = "https://bank.example"
+)
+
+type PseudonymTestSuite struct {
+	suite.Suite
+	options    *model.SignatureSuiteOptions
+	publicKey  []byte
+	secret     model.HolderSecret
+	credential model.JsonLdCredential
+	frame      model.JsonLdFrame
+}
+
+func TestPseudonymTestSuite(t *testing.T) {
+	suite.Run(t, new(PseudonymTestSuite))
+}
+
+func (s *PseudonymTestSuite) SetupTest() {
+	s.options = offlineOptions(s.T())
+	s.publicKey = benchmarkPublicKey(s.T())
+
+	var unsigned model.JsonLdCredentialNoProof
+	credentialBytes, err := os.ReadFile("testdata/unsignedCredential.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(credentialBytes, &unsigned))
+	frameBytes, err := os.ReadFile("testdata/frame.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(frameBytes, &s.frame))
+
+	s.secret, err = core.NewHolderSecret()
+	s.Require().NoError(err)
+	s.credential = issueHolderBound(s.T(), s.options, unsigned, s.publicKey, benchmarkPrivateKey(s.T()), s.secret)
+}
+
+// derive Derive a proof carrying the pseudonym of the holder for a verifier.
+func (s *PseudonymTestSuite) derive(verifierID string, nonce string) model.JsonLdCredential {
+	derived, err := core.NewSignatureProofSuite2020(s.publicKey, s.options).DeriveProofWithOptions(
+		context.Background(), s.credential, s.frame, []byte(nonce), &model.DeriveProofOptions{HolderSecret: s.secret, VerifierID: verifierID},
+	)
+	s.Require().NoError(err)
+
+	return derived
+}
+
+func (s *PseudonymTestSuite) TestPseudonym() {
+	proofSuite := core.NewSignatureProofSuite2020(s.publicKey, s.options)
+	options := &model.VerifyProofOptions{VerifierID: shopVerifierID}
+
+	// the same verifier recognizes the holder across presentations
+	first := proofSuite.VerifyProofWithOptions(s.derive(shopVerifierID, "first challenge"), options)
+	s.True(first.Success, first.Error)
+	s.NotEmpty(first.Pseudonym)
+	second := proofSuite.VerifyProofWithOptions(s.derive(shopVerifierID, "second challenge"), options)
+	s.True(second.Success, second.Error)
+	s.Equal(first.Pseudonym, second.Pseudonym)
+
+	// another verifier gets another pseudonym
+	other := proofSuite.VerifyProofWithOptions(s.derive(bankVerifierID, "first challenge"), &model.VerifyProofOptions{VerifierID: bankVerifierID})
+	s.True(other.Success, other.Error)
+	s.NotEqual(first.Pseudonym, other.Pseudonym)
+}
+
+func (s *PseudonymTestSuite) TestInvalidPseudonym() {
+	ctx := context.Background()
+	proofSuite := core.NewSignatureProofSuite2020(s.publicKey, s.options)
+	options := &model.VerifyProofOptions{VerifierID: shopVerifierID}
+
+	// a proof for another verifier
+	result := proofSuite.VerifyProofWithOptions(s.derive(bankVerifierID, "challenge"), options)
+	s.ErrorIs(result.Error, model.ErrPseudonymVerifierMismatch)
+
+	// a proof without pseudonym
+	derived, err := proofSuite.DeriveProofWithOptions(ctx, s.credential, s.frame, []byte("challenge"), &model.DeriveProofOptions{HolderSecret: s.secret})
+	s.Require().NoError(err)
+	result = proofSuite.VerifyProofWithOptions(derived, options)
+	s.ErrorIs(result.Error, model.ErrPseudonymRequired)
+
+	// the pseudonym of another verifier replayed
+	derived = s.derive(shopVerifierID, "challenge")
+	replayed := s.derive(bankVerifierID, "challenge")[c.CredentialFieldProof].(model.JsonLdProof)[c.CredentialFieldPseudonym].(map[string]interface{})
+	pseudonym := derived[c.CredentialFieldProof].(model.JsonLdProof)[c.CredentialFieldPseudonym].(map[string]interface{})
+	pseudonym["value"] = replayed["value"]
+	result = proofSuite.VerifyProofWithOptions(derived, options)
+	s.False(result.Success)
+
+	// a pseudonym requires the holder secret
+	_, err = proofSuite.DeriveProofWithOptions(ctx, s.credential, s.frame, []byte("challenge"), &model.DeriveProofOptions{VerifierID: shopVerifierID})
+	s.ErrorIs(err, model.ErrHolderBindingRequired)
+}
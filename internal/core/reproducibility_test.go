@@ -0,0 +1,173 @@
+package core_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/stretchr/testify/suite"
+)
+
+// A seededReader is a deterministic source of randomness: the SHA-256 hashes of a seed and a counter.
+type seededReader struct {
+	seed    []byte
+	counter uint64
+	buffer  []byte
+}
+
+func (r *seededReader) Read(p []byte) (int, error) {
+	for n := 0; n < len(p); {
+		if len(r.buffer) == 0 {
+			block := sha256.Sum256(binary.BigEndian.AppendUint64(append([]byte(nil), r.seed...), r.counter))
+			r.buffer = block[:]
+			r.counter++
+		}
+		copied := copy(p[n:], r.buffer)
+		r.buffer = r.buffer[copied:]
+		n += copied
+	}
+
+	return len(p), nil
+}
+
+type ReproducibilityTestSuite struct {
+	suite.Suite
+	publicKey  []byte
+	privateKey []byte
+	created    time.Time
+	credential model.JsonLdCredentialNoProof
+	frame      model.JsonLdFrame
+}
+
+func TestReproducibilityTestSuite(t *testing.T) {
+	suite.Run(t, new(ReproducibilityTestSuite))
+}
+
+func (s *ReproducibilityTestSuite) SetupTest() {
+	s.publicKey = benchmarkPublicKey(s.T())
+	s.privateKey = benchmarkPrivateKey(s.T())
+	s.created = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	credentialBytes, err := os.ReadFile("testdata/unsignedCredential.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(credentialBytes, &s.credential))
+	frameBytes, err := os.ReadFile("testdata/frame.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(frameBytes, &s.frame))
+}
+
+// options Create suite options with the fixed clock and a source of randomness seeded with seed.
+func (s *ReproducibilityTestSuite) options(seed string) *model.SignatureSuiteOptions {
+	options := offlineOptions(s.T())
+	options.Clock = func() time.Time { return s.created }
+	options.Rand = &seededReader{seed: []byte(seed)}
+
+	return options
+}
+
+// sign Sign the credential with a new suite.
+func (s *ReproducibilityTestSuite) sign(seed string) model.JsonLdCredential {
+	signed, _, err := core.NewSignatureSuite2020(s.publicKey, s.privateKey, s.options(seed)).Sign(s.credential)
+	s.Require().NoError(err)
+
+	return signed
+}
+
+// derive Derive a proof from a signed credential with a new suite.
+func (s *ReproducibilityTestSuite) derive(signed model.JsonLdCredential, seed string) model.JsonLdCredential {
+	derived, err := core.NewSignatureProofSuite2020(s.publicKey, s.options(seed)).DeriveProof(signed, s.frame, []byte("verifier challenge"))
+	s.Require().NoError(err)
+
+	return derived
+}
+
+func (s *ReproducibilityTestSuite) TestSign() {
+	signed := s.sign("seed")
+	proof := signed["proof"].(map[string]interface{})
+	s.Equal("2024-05-01T12:00:00Z", proof["created"])
+	result := core.NewSignatureSuite2020(s.publicKey, nil, offlineOptions(s.T())).Verify(signed)
+	s.True(result.Success, result.Error)
+
+	// the same seed gives the same signature
+	s.Equal(signed, s.sign("seed"))
+	s.NotEqual(proof["proofValue"], s.sign("other seed")["proof"].(map[string]interface{})["proofValue"])
+}
+
+func (s *ReproducibilityTestSuite) TestDeriveProof() {
+	signed := s.sign("seed")
+	derived := s.derive(signed, "seed")
+	result := core.NewSignatureProofSuite2020(s.publicKey, offlineOptions(s.T())).VerifyProof(derived)
+	s.True(result.Success, result.Error)
+
+	// the same seed gives the same proof
+	derivedJSON, err := json.Marshal(derived)
+	s.Require().NoError(err)
+	sameJSON, err := json.Marshal(s.derive(signed, "seed"))
+	s.Require().NoError(err)
+	s.Equal(string(derivedJSON), string(sameJSON))
+	otherJSON, err := json.Marshal(s.derive(signed, "other seed"))
+	s.Require().NoError(err)
+	s.NotEqual(string(derivedJSON), string(otherJSON))
+}
+
+func (s *ReproducibilityTestSuite) TestIssue() {
+	signatureSuite := core.NewSignatureSuite2020(s.publicKey, s.privateKey, s.options("seed"))
+	signed, err := core.Issue(context.Background(), signatureSuite, resident{ID: "did:example:jace", BirthDate: s.created}, &model.IssueOptions{GenerateContext: true})
+	s.Require().NoError(err)
+	s.Equal("2024-05-01T12:00:00Z", signed["issuanceDate"])
+}
+
+func (s *ReproducibilityTestSuite) TestHolderBinding() {
+	secret, err := core.NewHolderSecretWithRand(&seededReader{seed: []byte("secret")})
+	s.Require().NoError(err)
+	sameSecret, err := core.NewHolderSecretWithRand(&seededReader{seed: []byte("secret")})
+	s.Require().NoError(err)
+	s.Equal(secret, sameSecret)
+
+	request, blinding, err := core.NewHolderBindingRequestWithRand(&seededReader{seed: []byte("seed")}, secret, s.publicKey, 10, []byte("issuer nonce"))
+	s.Require().NoError(err)
+	sameRequest, sameBlinding, err := core.NewHolderBindingRequestWithRand(&seededReader{seed: []byte("seed")}, secret, s.publicKey, 10, []byte("issuer nonce"))
+	s.Require().NoError(err)
+	s.Equal(request, sameRequest)
+	s.Equal(blinding, sameBlinding)
+
+	otherRequest, _, err := core.NewHolderBindingRequestWithRand(&seededReader{seed: []byte("other seed")}, secret, s.publicKey, 10, []byte("issuer nonce"))
+	s.Require().NoError(err)
+	s.NotEqual(request, otherRequest)
+
+	_, err = core.NewHolderSecretWithRand(bytes.NewReader([]byte{1, 2, 3}))
+	s.ErrorIs(err, model.ErrRandomnessUnavailable)
+	_, _, err = core.NewHolderBindingRequestWithRand(bytes.NewReader([]byte{1, 2, 3}), secret, s.publicKey, 10, []byte("issuer nonce"))
+	s.ErrorIs(err, model.ErrRandomnessUnavailable)
+}
+
+func (s *ReproducibilityTestSuite) TestFailingRandomness() {
+	failing := func() *model.SignatureSuiteOptions {
+		options := s.options("seed")
+		options.Rand = bytes.NewReader([]byte{1, 2, 3})
+		options.PredicateClaims = []string{birthDateIRI}
+
+		return options
+	}
+	_, _, err := core.NewSignatureSuite2020(s.publicKey, s.privateKey, failing()).Sign(s.credential)
+	s.ErrorIs(err, model.ErrRandomnessUnavailable)
+
+	options := s.options("seed")
+	options.PredicateClaims = []string{birthDateIRI}
+	signed, _, err := core.NewSignatureSuite2020(s.publicKey, s.privateKey, options).Sign(s.credential)
+	s.Require().NoError(err)
+	proofSuite := core.NewSignatureProofSuite2020(s.publicKey, failing())
+	_, err = proofSuite.DeriveProof(signed, s.frame, []byte("verifier challenge"))
+	s.ErrorIs(err, model.ErrRandomnessUnavailable)
+	_, err = proofSuite.DeriveProofWithOptions(context.Background(), signed, s.frame, []byte("verifier challenge"), &model.DeriveProofOptions{
+		Predicates: []model.Predicate{{Claim: birthDateIRI, Operator: model.PredicateLessThan, Value: "2008-10-17"}},
+	})
+	s.ErrorIs(err, model.ErrRandomnessUnavailable)
+}
@@ -0,0 +1,210 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/piprate/json-gold/ld"
+)
+
+// An operation tracks the state of a single normalizer operation: its context, the limits to enforce
+// and the contexts fetched so far, reported to the trace of the context if any.
+// json-gold replaces the errors of the document loader with a generic one, therefore the first error
+// raised while loading a document is kept to be reported to the caller.
+// An operation is used by a single goroutine.
+type operation struct {
+	ctx            context.Context
+	limits         *model.ResourceLimits
+	contextFetches int
+	trace          *contextTrace // nil unless the operation is diagnosed
+	err            error
+}
+
+// record Report a context loaded by the operation to its trace, if any.
+func (op *operation) record(u string, source model.ContextSource) {
+	if op != nil && op.trace != nil {
+		op.trace.record(u, source)
+	}
+}
+
+// fail Record an error raised while loading a document, keeping only the first one.
+func (op *operation) fail(err error) error {
+	if op.err == nil {
+		op.err = err
+	}
+
+	return err
+}
+
+// result Return the error to report for a failed json-gold call: the one raised while loading a document, if any.
+func (op *operation) result(err error) error {
+	if err != nil && op.err != nil {
+		return op.err
+	}
+
+	return err
+}
+
+// An operationDocumentLoader loads documents within an operation.
+// No document is loaded once the context is done or the number of fetches is exhausted,
+// and the loaded documents exceeding the maximum context size are rejected.
+type operationDocumentLoader struct {
+	op     *operation
+	loader ld.DocumentLoader
+	source model.ContextSource // empty if the loader reports the source of the documents
+}
+
+func (l operationDocumentLoader) LoadDocument(u string) (*ld.RemoteDocument, error) {
+	if err := l.op.ctx.Err(); err != nil {
+		return nil, l.op.fail(fmt.Errorf("loading document %s interrupted: %w", u, err))
+	}
+
+	l.op.contextFetches++
+	if maxFetches := l.op.limits.MaxContextFetches; maxFetches > 0 && l.op.contextFetches > maxFetches {
+		return nil, l.op.fail(fmt.Errorf("%w: cannot load %s, limit %d", model.ErrTooManyContextFetches, u, maxFetches))
+	}
+
+	var document *ld.RemoteDocument
+	var err error
+	if loader, ok := l.loader.(model.ContextDocumentLoader); ok {
+		document, err = loader.LoadDocumentContext(l.op.ctx, u)
+	} else {
+		document, err = l.loader.LoadDocument(u)
+	}
+	if err != nil {
+		return nil, l.op.fail(fmt.Errorf("loading document %s: %w", u, err))
+	}
+
+	if maxBytes := l.op.limits.MaxContextBytes; maxBytes > 0 {
+		size, err := jsonSize(document.Document)
+		if err != nil {
+			return nil, l.op.fail(fmt.Errorf("loading document %s: %w", u, err))
+		}
+		if size > maxBytes {
+			return nil, l.op.fail(fmt.Errorf("%w: %s is %d bytes, limit %d", model.ErrContextTooLarge, u, size, maxBytes))
+		}
+	}
+	if l.source != "" {
+		l.op.record(u, l.source)
+	}
+
+	return document, nil
+}
+
+// An operationTransport issues the HTTP requests of the remote document loader within an operation:
+// pending requests are aborted once the context is done and the responses are not read beyond the maximum context size.
+type operationTransport struct {
+	op *operation
+}
+
+func (t operationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := http.DefaultTransport.RoundTrip(req.WithContext(t.op.ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	if maxBytes := t.op.limits.MaxContextBytes; maxBytes > 0 {
+		if res.ContentLength > int64(maxBytes) {
+			res.Body.Close()
+			return nil, t.op.fail(fmt.Errorf("%w: %s is %d bytes, limit %d", model.ErrContextTooLarge, req.URL, res.ContentLength, maxBytes))
+		}
+
+		res.Body = &limitedBody{
+			ReadCloser: res.Body,
+			op:         t.op,
+			url:        req.URL.String(),
+			maxBytes:   int64(maxBytes),
+		}
+	}
+
+	return res, nil
+}
+
+// A limitedBody fails once more than maxBytes have been read from the response body.
+type limitedBody struct {
+	io.ReadCloser
+	op       *operation
+	url      string
+	read     int64
+	maxBytes int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if b.read > b.maxBytes {
+		return n, b.op.fail(fmt.Errorf("%w: %s exceeds %d bytes", model.ErrContextTooLarge, b.url, b.maxBytes))
+	}
+
+	return n, err
+}
+
+// checkDocumentLimits Check the nesting depth and the size of a parsed JSON-LD document.
+// The depth is checked first, so that the document is never traversed beyond the maximum depth.
+//
+//	document interface{} The parsed JSON-LD document.
+//	limits *model.ResourceLimits
+//
+// returns:
+//
+//	err error model.ErrDocumentTooDeep or model.ErrDocumentTooLarge if a limit is exceeded
+func checkDocumentLimits(document interface{}, limits *model.ResourceLimits) error {
+	if limits.MaxNestingDepth > 0 {
+		if err := checkNestingDepth(document, 1, limits.MaxNestingDepth); err != nil {
+			return err
+		}
+	}
+
+	if limits.MaxDocumentBytes > 0 {
+		size, err := jsonSize(document)
+		if err != nil {
+			return err
+		}
+		if size > limits.MaxDocumentBytes {
+			return fmt.Errorf("%w: %d bytes, limit %d", model.ErrDocumentTooLarge, size, limits.MaxDocumentBytes)
+		}
+	}
+
+	return nil
+}
+
+func checkNestingDepth(value interface{}, depth, maxDepth int) error {
+	var children []interface{}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		children = make([]interface{}, 0, len(v))
+		for _, child := range v {
+			children = append(children, child)
+		}
+	case []interface{}:
+		children = v
+	default:
+		return nil
+	}
+
+	if depth > maxDepth {
+		return fmt.Errorf("%w: limit %d", model.ErrDocumentTooDeep, maxDepth)
+	}
+
+	for _, child := range children {
+		if err := checkNestingDepth(child, depth+1, maxDepth); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// jsonSize Return the size of the JSON serialization of a value.
+func jsonSize(value interface{}) (int, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(data), nil
+}
@@ -0,0 +1,217 @@
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type ResourceLimitsTestSuite struct {
+	suite.Suite
+}
+
+func TestResourceLimitsTestSuite(t *testing.T) {
+	suite.Run(t, new(ResourceLimitsTestSuite))
+}
+
+func (s *ResourceLimitsTestSuite) TestDefaultLimitsAcceptCredential() {
+	subject := core.NewNormalizer(&model.SignatureSuiteOptions{Limits: model.DefaultResourceLimits()})
+
+	credentialJSONLdBytes, err := os.ReadFile("testdata/unsignedPermanentResidentCard.json")
+	s.NoError(err)
+
+	_, err = subject.Normalize(string(credentialJSONLdBytes))
+	s.NoError(err)
+}
+
+func (s *ResourceLimitsTestSuite) TestDocumentTooLarge() {
+	subject := core.NewNormalizer(&model.SignatureSuiteOptions{
+		Limits: &model.ResourceLimits{MaxDocumentBytes: 64},
+	})
+
+	document := map[string]interface{}{
+		"@id":                   "urn:example:1",
+		"https://example.com/p": strings.Repeat("a", 64),
+	}
+
+	_, err := subject.NormalizeDocument(document)
+	s.ErrorIs(err, model.ErrDocumentTooLarge)
+
+	_, err = subject.Normalize(fmt.Sprintf(`{"@id": "urn:example:1", "https://example.com/p": "%s"}`, strings.Repeat("a", 64)))
+	s.ErrorIs(err, model.ErrDocumentTooLarge)
+}
+
+func (s *ResourceLimitsTestSuite) TestNoLimitsUnlessConfigured() {
+	document := map[string]interface{}{"https://example.com/p": "leaf"}
+	for i := 0; i < model.DefaultResourceLimits().MaxNestingDepth; i++ {
+		document = map[string]interface{}{"https://example.com/p": []interface{}{document}}
+	}
+
+	_, err := core.NewNormalizer(nil).NormalizeDocument(document)
+	s.NoError(err)
+	_, err = core.NewNormalizer(&model.SignatureSuiteOptions{}).NormalizeDocument(document)
+	s.NoError(err)
+
+	subject := core.NewNormalizer(&model.SignatureSuiteOptions{Limits: model.DefaultResourceLimits()})
+	_, err = subject.NormalizeDocument(document)
+	s.ErrorIs(err, model.ErrDocumentTooDeep)
+}
+
+func (s *ResourceLimitsTestSuite) TestDocumentTooDeep() {
+	subject := core.NewNormalizer(&model.SignatureSuiteOptions{
+		Limits: &model.ResourceLimits{MaxNestingDepth: 8},
+	})
+
+	document := map[string]interface{}{"https://example.com/p": "leaf"}
+	for i := 0; i < 8; i++ {
+		document = map[string]interface{}{"https://example.com/p": []interface{}{document}}
+	}
+
+	_, err := subject.NormalizeDocument(document)
+	s.ErrorIs(err, model.ErrDocumentTooDeep)
+}
+
+func (s *ResourceLimitsTestSuite) TestTooManyNQuads() {
+	subject := core.NewNormalizer(&model.SignatureSuiteOptions{
+		Limits: &model.ResourceLimits{MaxNQuads: 2},
+	})
+
+	document := map[string]interface{}{
+		"@id":                   "urn:example:1",
+		"https://example.com/p": []interface{}{"a", "b", "c"},
+	}
+
+	_, err := subject.NormalizeDocument(document)
+	s.ErrorIs(err, model.ErrTooManyNQuads)
+}
+
+func (s *ResourceLimitsTestSuite) TestTooManyBlankNodes() {
+	subject := core.NewNormalizer(&model.SignatureSuiteOptions{
+		Limits: &model.ResourceLimits{MaxBlankNodes: 3},
+	})
+
+	_, err := subject.NormalizeDocument(poisonGraph(4))
+	s.ErrorIs(err, model.ErrTooManyBlankNodes)
+}
+
+func (s *ResourceLimitsTestSuite) TestCanonicalizationBudgetExceeded() {
+	subject := core.NewNormalizer(&model.SignatureSuiteOptions{
+		Limits: &model.ResourceLimits{MaxCanonicalizationSteps: 1000},
+	})
+
+	_, err := subject.NormalizeDocument(poisonGraph(6))
+	s.ErrorIs(err, model.ErrCanonicalizationBudgetExceeded)
+
+	// a small graph of the same shape fits in the budget
+	_, err = subject.NormalizeDocument(poisonGraph(3))
+	s.NoError(err)
+}
+
+func (s *ResourceLimitsTestSuite) TestTooManyContextFetches() {
+	subject := core.NewNormalizer(&model.SignatureSuiteOptions{
+		DocumentLoader: &recordingDocumentLoader{},
+		Limits:         &model.ResourceLimits{MaxContextFetches: 1},
+	})
+
+	document := map[string]interface{}{
+		"@context": []interface{}{"https://example.com/context/v1", "https://example.com/context/v2"},
+		"@id":      "urn:example:1",
+		"name":     "Alice",
+	}
+
+	_, err := subject.NormalizeDocument(document)
+	s.ErrorIs(err, model.ErrTooManyContextFetches)
+}
+
+func (s *ResourceLimitsTestSuite) TestContextTooLarge() {
+	subject := core.NewNormalizer(&model.SignatureSuiteOptions{
+		DocumentLoader: &recordingDocumentLoader{},
+		Limits:         &model.ResourceLimits{MaxContextBytes: 16},
+	})
+
+	document := map[string]interface{}{
+		"@context": "https://example.com/context/v1",
+		"@id":      "urn:example:1",
+		"name":     "Alice",
+	}
+
+	_, err := subject.NormalizeDocument(document)
+	s.ErrorIs(err, model.ErrContextTooLarge)
+}
+
+func (s *ResourceLimitsTestSuite) TestDownloadedContextTooLarge() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/ld+json")
+		// stream the response, so that no content length is announced
+		w.(http.Flusher).Flush()
+		fmt.Fprintf(w, `{"@context": {"name": "https://example.com/name", "padding": "%s"}}`, strings.Repeat("a", 4096))
+	}))
+	defer server.Close()
+
+	subject := core.NewNormalizer(&model.SignatureSuiteOptions{
+		Limits: &model.ResourceLimits{MaxContextBytes: 1024},
+	})
+
+	document := map[string]interface{}{
+		"@context": server.URL + "/context/v1",
+		"@id":      "urn:example:1",
+		"name":     "Alice",
+	}
+
+	_, err := subject.NormalizeDocument(document)
+	s.ErrorIs(err, model.ErrContextTooLarge)
+}
+
+func (s *ResourceLimitsTestSuite) TestDocumentLoaderErrorReported() {
+	subject := core.NewNormalizer(&model.SignatureSuiteOptions{
+		DocumentLoader: &recordingDocumentLoader{},
+	})
+
+	document := map[string]interface{}{
+		"@context": "https://example.com/context/v1",
+		"@id":      "urn:example:1",
+		"name":     "Alice",
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := subject.NormalizeDocumentContext(ctx, document)
+	s.ErrorIs(err, context.Canceled)
+
+	_, err = subject.Compact(ctx, document, "https://example.com/context/v1")
+	s.ErrorIs(err, context.Canceled)
+}
+
+// poisonGraph Build a document made of a complete graph of blank nodes, all sharing the same first degree hash,
+// which makes the work of URDNA2015 grow factorially with the number of nodes.
+func poisonGraph(nodes int) map[string]interface{} {
+	graph := make([]interface{}, nodes)
+	for i := range graph {
+		related := make([]interface{}, 0, nodes-1)
+		for j := 0; j < nodes; j++ {
+			if j != i {
+				related = append(related, fmt.Sprintf("_:b%d", j))
+			}
+		}
+		graph[i] = map[string]interface{}{
+			"@id": fmt.Sprintf("_:b%d", i),
+			"p":   related,
+		}
+	}
+
+	return map[string]interface{}{
+		"@context": map[string]interface{}{
+			"p": map[string]interface{}{"@id": "https://example.com/p", "@type": "@id"},
+		},
+		"@graph": graph,
+	}
+}
@@ -1,12 +1,13 @@
 package core
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
+	"io"
 	"slices"
 
-	ml "github.com/IBM/mathlib"
 	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
 	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
 	"github.com/hyperledger/aries-bbs-go/bbs"
@@ -23,13 +24,20 @@ import (
 //   - https://w3id.org/vc-revocation-list-2020/v1
 //
 // If context is not found, document loader will try to download it from the internet
+// and keep it in the process-wide ContextCache.
+//
+// A SignatureProofSuite2020 is safe for concurrent use by multiple goroutines,
+// provided that the custom document loader, if any, is safe for concurrent use.
 type SignatureProofSuite2020 struct {
 	publicKey                  []byte
 	normalizer                 *normalizer
+	issuerBinding              *issuerBinding
+	policyEngine               *policyEngine
+	predicateClaims            []string
+	rand                       io.Reader // the source of the random factors of the derived proofs
+	scheme                     signatureScheme
 	supportedDerivedProofTypes []string
-	documentSignatureSuite     *SignatureSuite2020
 	mappedDerivedProofType     string
-	curve                      *bbs.BBSG2Pub
 }
 
 // NewSignatureProofSuite2020 initializes and returns SignatureProofSuite.
@@ -39,16 +47,21 @@ func NewSignatureProofSuite2020(
 	publicKey []byte,
 	options *model.SignatureSuiteOptions,
 ) *SignatureProofSuite2020 {
+	rng := randOption(options)
+
 	return &SignatureProofSuite2020{
-		publicKey:  publicKey,
-		normalizer: NewNormalizer(options),
+		publicKey:       publicKey,
+		normalizer:      NewNormalizer(options),
+		issuerBinding:   newIssuerBinding(options),
+		policyEngine:    newPolicyEngine(options),
+		predicateClaims: predicateClaimsOption(options),
+		rand:            rng,
+		scheme:          newSignatureScheme(ciphersuiteOption(options), rng),
 		supportedDerivedProofTypes: []string{
 			c.CredentialProofTypeBbsBlsSig2020,
 			c.CredentialProofTypeSecBbsBlsSig2020,
 		},
-		documentSignatureSuite: NewSignatureSuite2020(publicKey, nil, options),
 		mappedDerivedProofType: c.CredentialProofTypeBbsBlsSig2020,
-		curve:                  bbs.New(ml.Curves[ml.BLS12_381_BBS]),
 	}
 }
 
@@ -63,8 +76,52 @@ func NewSignatureProofSuite2020(
 //	proof model.JsonLdCredential
 //	err error
 func (s *SignatureProofSuite2020) DeriveProof(signedCredential model.JsonLdCredential, frameDocument model.JsonLdFrame, nonceBytes []byte) (model.JsonLdCredential, error) {
+	return s.DeriveProofContext(context.Background(), signedCredential, frameDocument, nonceBytes)
+}
+
+// DeriveProofContext Derive a proof for the frame of a signed credential.
+// The context is checked while loading the remote contexts and during the canonicalization.
+//
+//	ctx context.Context
+//	signedCredential model.JsonLdCredential The signed JSON-LD credential.
+//	frameDocument model.JsonLDFrame The frame document.
+//	nonceBytes []byte The bytes to use for the proof generation.
+//
+// returns:
+//
+//	proof model.JsonLdCredential
+//	err error
+func (s *SignatureProofSuite2020) DeriveProofContext(
+	ctx context.Context,
+	signedCredential model.JsonLdCredential,
+	frameDocument model.JsonLdFrame,
+	nonceBytes []byte,
+) (model.JsonLdCredential, error) {
+	return s.DeriveProofWithOptions(ctx, signedCredential, frameDocument, nonceBytes, nil)
+}
+
+// DeriveProofWithOptions Derive a proof for the frame of a signed credential, e.g. bound to its holder.
+// The context is checked while loading the remote contexts and during the canonicalization.
+//
+//	ctx context.Context
+//	signedCredential model.JsonLdCredential The signed JSON-LD credential.
+//	frameDocument model.JsonLDFrame The frame document.
+//	nonceBytes []byte The bytes to use for the proof generation, i.e. the challenge of the verifier.
+//	options *model.DeriveProofOptions nullable
+//
+// returns:
+//
+//	proof model.JsonLdCredential
+//	err error
+func (s *SignatureProofSuite2020) DeriveProofWithOptions(
+	ctx context.Context,
+	signedCredential model.JsonLdCredential,
+	frameDocument model.JsonLdFrame,
+	nonceBytes []byte,
+	options *model.DeriveProofOptions,
+) (model.JsonLdCredential, error) {
 	// 1. Retrieve all the proofs from the credential that can be used to derive our proof
-	credWithoutProofs, proofs, err := s.getSupportedProofs(signedCredential)
+	credWithoutProofs, proofs, err := s.getSupportedProofs(ctx, signedCredential)
 	if err != nil {
 		return nil, err
 	}
@@ -72,8 +129,22 @@ func (s *SignatureProofSuite2020) DeriveProof(signedCredential model.JsonLdCrede
 		return nil, fmt.Errorf("There were not any proofs provided that can be used to derive a proof with this suite.")
 	}
 
-	// 2. Compute framed cred and derivedProof
-	framedCredential, derivedProof, err := s.deriveProof(credWithoutProofs, proofs[0], frameDocument, nonceBytes)
+	// 1.1. Frame the credential once, the frame is the same for all the proofs
+	framedCredential, err := s.frameCredential(ctx, credWithoutProofs, frameDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1.2. Check that the disclosed claims do not make the presentations correlatable, if requested
+	if options != nil && options.PrivacyLint != nil {
+		warnings := lintFrame(credWithoutProofs, framedCredential, proofs, options.PrivacyLint)
+		if err := checkPrivacyWarnings(warnings, options.PrivacyLint); err != nil {
+			return nil, err
+		}
+	}
+
+	// 2. Compute derivedProof
+	derivedProof, err := s.deriveProof(ctx, credWithoutProofs, framedCredential, proofs[0], nonceBytes, options)
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +156,7 @@ func (s *SignatureProofSuite2020) DeriveProof(signedCredential model.JsonLdCrede
 		derivedProofs[0] = derivedProof
 
 		for i, proof := range proofs[1:] {
-			_, newDerivedProof, err := s.deriveProof(credWithoutProofs, proof, frameDocument, nonceBytes)
+			newDerivedProof, err := s.deriveProof(ctx, credWithoutProofs, framedCredential, proof, nonceBytes, options)
 			if err != nil {
 				return nil, err
 			}
@@ -108,6 +179,39 @@ func (s *SignatureProofSuite2020) DeriveProof(signedCredential model.JsonLdCrede
 //
 //	result *model.VerificationResult
 func (s *SignatureProofSuite2020) VerifyProof(signedCredential model.JsonLdCredential) *model.VerificationResult {
+	return s.VerifyProofWithOptions(signedCredential, nil)
+}
+
+// VerifyProofWithOptions Verify a derived proof of a framed credential, applying additional checks.
+//
+//	signedCredential model.JsonLdCredential The framed credential together with the proof.
+//	options *model.VerifyProofOptions nullable
+//
+// returns:
+//
+//	result *model.VerificationResult
+func (s *SignatureProofSuite2020) VerifyProofWithOptions(
+	signedCredential model.JsonLdCredential,
+	options *model.VerifyProofOptions,
+) *model.VerificationResult {
+	return s.VerifyProofContext(context.Background(), signedCredential, options)
+}
+
+// VerifyProofContext Verify a derived proof of a framed credential, applying additional checks.
+// The context is checked while loading the remote contexts and during the canonicalization.
+//
+//	ctx context.Context
+//	signedCredential model.JsonLdCredential The framed credential together with the proof.
+//	options *model.VerifyProofOptions nullable
+//
+// returns:
+//
+//	result *model.VerificationResult
+func (s *SignatureProofSuite2020) VerifyProofContext(
+	ctx context.Context,
+	signedCredential model.JsonLdCredential,
+	options *model.VerifyProofOptions,
+) *model.VerificationResult {
 	signedCredentialCopy := deepCopyMap(signedCredential)
 	// 1. Retrieve the proof from the credential and parse it
 	proofs, err := s.getDerivedProofs(signedCredentialCopy)
@@ -124,24 +228,49 @@ func (s *SignatureProofSuite2020) VerifyProof(signedCredential model.JsonLdCrede
 		}
 	}
 
-	for _, proof := range proofs {
-		proofValueB64, ok := proof[c.CredentialFieldProofValue].(string)
-		if !ok {
+	// 2. Recreate the unsigned credential and normalize it once for all the proofs
+	unsignedCredential := signedCredentialCopy
+	delete(unsignedCredential, c.CredentialFieldProof)
+
+	credentialStatements, err := s.createRevealedDocumentData(ctx, unsignedCredential)
+	if err != nil {
+		return &model.VerificationResult{
+			Success: false,
+			Error:   err,
+		}
+	}
+
+	var proofNonce []byte
+	var predicates []model.PredicateProof
+	var pseudonym *model.Pseudonym
+	for i, proof := range proofs {
+		// 3-5. Retrieve the proof of knowledge, its nonce and the disclosed statements
+		data, err := s.prepareVerification(ctx, proof, credentialStatements, options)
+		if err != nil {
 			return &model.VerificationResult{
 				Success: false,
-				Error:   fmt.Errorf("Cannot retrieve the proofValue from within the proof."),
+				Error:   err,
+			}
+		}
+
+		// 6. Perform the proof verification, together with the predicate proofs and the pseudonym
+		if len(data.predicates) > 0 || data.pseudonym != nil {
+			err = requireBBSPlus(s.scheme, "predicate proofs and pseudonyms")
+			if err == nil {
+				err = verifyCombinedProof(s.publicKey, data)
 			}
+		} else {
+			err = s.scheme.verifyProof(s.publicKey, data.proofValue, data.statements, data.nonce)
 		}
-		proofValueBytes, err := base64.StdEncoding.DecodeString(proofValueB64)
 		if err != nil {
 			return &model.VerificationResult{
 				Success: false,
-				Error:   fmt.Errorf("The proofValue is not in base64: %w", err),
+				Error:   err,
 			}
 		}
 
-		// 2. Strip off the signature and nonce from the proof in order to recompute the signed statements
-		unsignedProof, _, err := s.createVerifyProofData(proof)
+		// 7. Check that the verification method is controlled by the issuer
+		err = s.issuerBinding.check(ctx, unsignedCredential, proof, s.publicKey)
 		if err != nil {
 			return &model.VerificationResult{
 				Success: false,
@@ -149,37 +278,40 @@ func (s *SignatureProofSuite2020) VerifyProof(signedCredential model.JsonLdCrede
 			}
 		}
 
-		// 3. Retrieve and parse the nonce used to generate the proof
-		nonceB64, ok := proof[c.CredentialFieldNonce].(string)
-		if !ok {
+		// 8. All the derived proofs must have been generated for the same verifier nonce
+		if proofNonce != nil && !bytes.Equal(proofNonce, data.nonce) {
 			return &model.VerificationResult{
 				Success: false,
-				Error:   fmt.Errorf("Cannot retrieve the nonce from within the proof."),
+				Error:   model.ErrNonceMismatch,
 			}
 		}
-		nonceBytes, err := base64.StdEncoding.DecodeString(nonceB64)
-		if err != nil {
+		proofNonce = data.nonce
+		predicates = append(predicates, data.predicates...)
+
+		// 8.1. All the derived proofs must carry the same pseudonym, if any
+		if i > 0 && (pseudonym == nil) != (data.pseudonym == nil) || pseudonym != nil && !bytes.Equal(pseudonym.Value, data.pseudonym.Value) {
 			return &model.VerificationResult{
 				Success: false,
-				Error:   fmt.Errorf("The nonce is not in base64: %w", err),
+				Error:   model.ErrPseudonymMismatch,
 			}
 		}
+		pseudonym = data.pseudonym
+	}
 
-		// 4. Recreate the unsigned unsignedCredential
-		unsignedCredential := signedCredentialCopy
-		delete(signedCredentialCopy, c.CredentialFieldProof)
-
-		// 5. Retrieve the statements to verify
-		statementsToVerify, err := s.documentSignatureSuite.prepareDataForSigning(unsignedCredential, unsignedProof)
+	// 9. Check that the required predicates are implied by the proven ones
+	if options != nil && len(options.RequiredPredicates) > 0 {
+		err = checkRequiredPredicates(options.RequiredPredicates, predicates)
 		if err != nil {
 			return &model.VerificationResult{
 				Success: false,
 				Error:   err,
 			}
 		}
+	}
 
-		// 6. Perform the proof verification
-		err = s.curve.VerifyProof(statementsToVerify, proofValueBytes, nonceBytes, s.publicKey)
+	// 10. Check that the nonce has been issued by the verifier and consume it
+	if options != nil && options.NonceChecker != nil {
+		err = options.NonceChecker.ConsumeNonce(options.SessionID, proofNonce)
 		if err != nil {
 			return &model.VerificationResult{
 				Success: false,
@@ -188,71 +320,268 @@ func (s *SignatureProofSuite2020) VerifyProof(signedCredential model.JsonLdCrede
 		}
 	}
 
-	return &model.VerificationResult{
-		Success: true,
+	// 11. Evaluate the verification policy on the disclosed credential
+	result := s.policyEngine.apply(signedCredential, s.publicKey)
+	if result.Success && pseudonym != nil {
+		result.Pseudonym = pseudonym.Value
+	}
+
+	return result
+}
+
+// A verificationData holds a derived proof and the messages it discloses.
+type verificationData struct {
+	statements [][]byte // the disclosed messages
+	proofValue []byte
+	nonce      []byte
+	predicates []model.PredicateProof // sorted by message
+	pseudonym  *model.Pseudonym       // nil if the proof does not carry a pseudonym
+}
+
+// prepareVerification Retrieve the proof of knowledge of a derived proof, its nonce and the messages it discloses.
+//
+//	ctx context.Context
+//	proof model.JsonLdProof The derived proof.
+//	credentialStatements []string The normalized framed credential.
+//	options *model.VerifyProofOptions nullable
+//
+// returns:
+//
+//	data *verificationData
+//	err error
+func (s *SignatureProofSuite2020) prepareVerification(
+	ctx context.Context,
+	proof model.JsonLdProof,
+	credentialStatements []string,
+	options *model.VerifyProofOptions,
+) (*verificationData, error) {
+	if err := checkProofCiphersuite(proof, s.scheme); err != nil {
+		return nil, err
+	}
+	proofValueB64, ok := proof[c.CredentialFieldProofValue].(string)
+	if !ok {
+		return nil, fmt.Errorf("Cannot retrieve the proofValue from within the proof.")
+	}
+	proofValueBytes, err := base64.StdEncoding.DecodeString(proofValueB64)
+	if err != nil {
+		return nil, fmt.Errorf("The proofValue is not in base64: %w", err)
+	}
+
+	// 3. Strip off the signature and nonce from the proof in order to recompute the signed statements
+	_, proofStatements, err := s.createVerifyProofData(ctx, proof)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Retrieve and parse the nonce used to generate the proof
+	nonceB64, ok := proof[c.CredentialFieldNonce].(string)
+	if !ok {
+		return nil, fmt.Errorf("Cannot retrieve the nonce from within the proof.")
+	}
+	nonceBytes, err := base64.StdEncoding.DecodeString(nonceB64)
+	if err != nil {
+		return nil, fmt.Errorf("The nonce is not in base64: %w", err)
+	}
+
+	// 5. Retrieve the statements to verify, including the descriptors of the claims of the predicates
+	// and the holder binding marker if disclosed
+	predicates, err := decodePredicateProofs(proof[c.CredentialFieldPredicates])
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(predicates, func(a, b model.PredicateProof) int { return a.Message - b.Message })
+
+	statementsToVerify := combineStatementsForSigning(proofStatements, credentialStatements)
+	for i, predicate := range predicates {
+		if i == 0 || predicate.Message != predicates[i-1].Message {
+			statementsToVerify = append(statementsToVerify, encodingDescriptor(predicate.Claim, predicate.Datatype))
+		}
+	}
+	holderBound := isHolderBound(proofValueBytes, len(statementsToVerify))
+	if holderBound {
+		statementsToVerify = append(statementsToVerify, []byte(c.HolderBindingMarker))
+	} else if options != nil && options.RequireHolderBinding {
+		return nil, model.ErrHolderBindingRequired
+	}
+
+	// 5.1. Retrieve the pseudonym, derived from the holder secret, for the verifier
+	pseudonym, err := decodePseudonym(proof[c.CredentialFieldPseudonym])
+	if err != nil {
+		return nil, err
+	}
+	if pseudonym != nil && !holderBound {
+		return nil, fmt.Errorf("%w: a pseudonym is derived from the holder secret", model.ErrHolderBindingRequired)
 	}
+	if options != nil && options.VerifierID != "" {
+		if pseudonym == nil {
+			return nil, model.ErrPseudonymRequired
+		}
+		if pseudonym.VerifierID != options.VerifierID {
+			return nil, model.ErrPseudonymVerifierMismatch
+		}
+	}
+
+	return &verificationData{
+		statements: statementsToVerify,
+		proofValue: proofValueBytes,
+		nonce:      nonceBytes,
+		predicates: predicates,
+		pseudonym:  pseudonym,
+	}, nil
+}
+
+// VerifyProofBatch Verify a batch of derived proofs in parallel.
+// The derived proofs are verified independently, since the proof of knowledge does not expose the pairing
+// equation needed to combine the verifications.
+//
+//	ctx context.Context If the context is done, the credentials not yet processed will report its error.
+//	signedCredentials []model.JsonLdCredential The framed credentials together with their proofs.
+//	options *model.BatchOptions nullable
+//
+// returns:
+//
+//	results []*model.VerificationResult one result per credential, in the same order
+func (s *SignatureProofSuite2020) VerifyProofBatch(
+	ctx context.Context,
+	signedCredentials []model.JsonLdCredential,
+	options *model.BatchOptions,
+) []*model.VerificationResult {
+	results := make([]*model.VerificationResult, len(signedCredentials))
+
+	forEachConcurrently(ctx, len(signedCredentials), options, func(i int) {
+		results[i] = s.VerifyProofContext(ctx, signedCredentials[i], nil)
+	}, func(i int) {
+		results[i] = cancelledResult(ctx)
+	})
+
+	return results
 }
 
-// deriveProof Frame a signed JSON-LD credential and generate a verifiable proof.
+// deriveProof Generate a verifiable proof of the frame of a signed JSON-LD credential.
 //
+//	ctx context.Context
 //	credential model.JsonLdCredentialNoProof The unsigned JSON-LD credential.
+//	framedCredential model.JsonLdCredential The frame of the credential, see frameCredential.
 //	proof model.JsonLDProof The original proof from where derive the proof for the framed credential.
-//	frameDocument model.JsonLDFrame The frame document.
 //	nonceBytes []byte The bytes to use for the proof generation.
+//	options *model.DeriveProofOptions nullable
 //
 // returns:
 //
-//	framedCredential model.JsonLdCredential
 //	derivedProof model.JsonLDProof
 //	err error
 func (s *SignatureProofSuite2020) deriveProof(
+	ctx context.Context,
 	credential model.JsonLdCredentialNoProof,
+	framedCredential model.JsonLdCredential,
 	proof model.JsonLdProof,
-	frameDocument model.JsonLdFrame,
 	nonceBytes []byte,
-) (model.JsonLdCredential, model.JsonLdProof, error) {
+	options *model.DeriveProofOptions,
+) (model.JsonLdProof, error) {
 	// 0. Check that the nonce has been supplied
 	if len(nonceBytes) == 0 {
-		return nil, nil, fmt.Errorf("Nonce has not been supplied by the verifier.")
+		return nil, fmt.Errorf("Nonce has not been supplied by the verifier.")
+	}
+	var holderSecret model.HolderSecret
+	var predicates []model.Predicate
+	var verifierID string
+	if options != nil {
+		holderSecret, predicates, verifierID = options.HolderSecret, options.Predicates, options.VerifierID
+	}
+	if verifierID != "" && holderSecret == nil {
+		return nil, fmt.Errorf("%w: a pseudonym is derived from the holder secret", model.ErrHolderBindingRequired)
+	}
+
+	// 1-7. Compute the messages to disclose
+	derivation, err := s.prepareDerivation(ctx, credential, framedCredential, proof, holderSecret)
+	if err != nil {
+		return nil, err
 	}
 
+	// 8. Generate the new signature, proving the predicates on the hidden encodings of the claims
+	// and the pseudonym together with the signature, if any
+	combined := &combinedProof{}
+	if len(derivation.encoded) == 0 && holderSecret == nil && len(predicates) == 0 {
+		combined.proofValue, err = s.scheme.deriveProof(s.publicKey, derivation.signature, derivation.statements, derivation.revealed, nonceBytes)
+	} else if err = requireBBSPlus(s.scheme, "predicate proofs, holder bindings and pseudonyms"); err == nil {
+		combined, err = s.deriveCombinedProof(derivation, predicates, verifierID, nonceBytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 9. Embed the signature, the predicate proofs and the pseudonym in the derivedProof
+	derivedProof := newDerivedProof(proof, nonceBytes, combined.proofValue)
+	setProofCiphersuite(derivedProof, s.scheme)
+	if len(combined.predicates) > 0 {
+		derivedProof[c.CredentialFieldPredicates] = deepCopyValue(combined.predicates)
+	}
+	if combined.pseudonym != nil {
+		derivedProof[c.CredentialFieldPseudonym] = deepCopyValue(combined.pseudonym)
+	}
+
+	return derivedProof, nil
+}
+
+// A derivation holds the messages of a signed credential and the indexes of the messages to disclose.
+type derivation struct {
+	framedCredential model.JsonLdCredential
+	statements       [][]byte                // the proof statements and the credential statements
+	encoded          []*encodedClaim         // the encodings of the predicate claims
+	messages         []*bbs.SignatureMessage // the statements, the encodings and the holder binding messages
+	revealed         []int                   // sorted indexes of the disclosed messages
+	signature        []byte
+}
+
+// prepareDerivation Compute the messages of a signed JSON-LD credential to disclose in a derived proof of its frame.
+//
+//	ctx context.Context
+//	credential model.JsonLdCredentialNoProof The unsigned JSON-LD credential.
+//	framedCredential model.JsonLdCredential The frame of the credential, see frameCredential.
+//	proof model.JsonLDProof The original proof from where derive the proof for the framed credential.
+//	holderSecret model.HolderSecret nil if the credential is not bound to its holder
+//
+// returns:
+//
+//	derivation *derivation
+//	err error
+func (s *SignatureProofSuite2020) prepareDerivation(
+	ctx context.Context,
+	credential model.JsonLdCredentialNoProof,
+	framedCredential model.JsonLdCredential,
+	proof model.JsonLdProof,
+	holderSecret model.HolderSecret,
+) (*derivation, error) {
 	// 1. Retrieve original proof signature
 	signature, hasSignature := proof[c.CredentialFieldProofValue]
 	if !hasSignature {
-		return nil, nil, fmt.Errorf("Cannot derive proof: original proof does not contain proofValue")
+		return nil, fmt.Errorf("Cannot derive proof: original proof does not contain proofValue")
 	}
 	signatureB64, ok := signature.(string)
 	if !ok {
-		return nil, nil, fmt.Errorf("Signature is not a string")
+		return nil, fmt.Errorf("Signature is not a string")
 	}
 	sigBytes, err := base64.StdEncoding.DecodeString(signatureB64)
 	if err != nil {
-		return nil, nil, fmt.Errorf("The signature is not in base64: %w", err)
+		return nil, fmt.Errorf("The signature is not in base64: %w", err)
 	}
 
 	// 2. Normalize the JSON-LD credential
-	credentialStatements, err := s.createVerifyDocumentData(credential)
+	credentialStatements, err := s.createVerifyDocumentData(ctx, credential)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	// 3. Normalize the JSON-LD proof as it would have been signed by the signer
-	unsignedProof, proofStatements, err := s.createVerifyProofData(proof)
+	_, proofStatements, err := s.createVerifyProofData(ctx, proof)
 	if err != nil {
-		return nil, nil, err
-	}
-
-	// 4. Frame the credential against the frameDocument
-	framedCredentialResult, err := s.normalizer.Frame(credential, frameDocument)
-	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	framedCredentialResult[c.CredentialFieldContext] = credential[c.CredentialFieldContext]
 
-	// 5. Normalize the obtained JSON-LD frame
-	framedCredentialStatements, err := s.createVerifyDocumentData(framedCredentialResult)
+	// 4-5. Normalize the JSON-LD frame of the credential
+	framedCredentialStatements, err := s.createVerifyDocumentData(ctx, framedCredential)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	// 6. Compute the indexes of the statements to disclose
@@ -264,65 +593,134 @@ func (s *SignatureProofSuite2020) deriveProof(
 		proofIndexesToReveal[i] = i
 	}
 
-	// 6.2. Compute the indexes of the statements to disclose within the credential
+	// 6.2. Compute the indexes of the statements to disclose within the credential, whose blank nodes have been framed as IRIs
+	skolemizedStatements := skolemizeBlankNodes(credentialStatements)
 	credIndexesToReveal := make([]int, 0)
 	for _, revealedStatement := range framedCredentialStatements {
-		statementIndex := slices.Index(credentialStatements, revealedStatement)
+		statementIndex := slices.Index(skolemizedStatements, revealedStatement)
 		if statementIndex > -1 {
 			credIndexesToReveal = append(credIndexesToReveal, statementIndex+numberOfProofStatements)
 		}
 	}
 
 	if len(credIndexesToReveal) != len(framedCredentialStatements) {
-		return nil, nil, fmt.Errorf("Some statements in the frame document not found in the original proof")
+		return nil, fmt.Errorf("Some statements in the frame document not found in the original proof")
 	}
 
 	// 6.3. Merge the indexes to disclose in one array
 	indexesToReveal := append(proofIndexesToReveal, credIndexesToReveal...)
 
-	// 7. Compute all the original statements over which the original signature has been performed
-	allCredStatements, err := s.documentSignatureSuite.prepareDataForSigning(credential, unsignedProof)
+	// 7. Compute all the original statements over which the original signature has been performed,
+	// reusing the statements normalized at steps 2 and 3
+	allCredStatements := combineStatementsForSigning(proofStatements, credentialStatements)
+
+	// 7.1. Append the hidden encodings of the predicate claims
+	encoded, err := encodeClaims(s.predicateClaims, allCredStatements)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+
+	// 7.2. Disclose the holder binding marker and prove the knowledge of the hidden holder secret
+	messages := signatureMessages(newBBSCurve(), allCredStatements, encoded, holderSecret)
+	if holderSecret != nil {
+		indexesToReveal = append(indexesToReveal, len(messages)-2)
 	}
+	slices.Sort(indexesToReveal)
 
-	// 8. Generate the new signature
-	outputProof, err := s.curve.DeriveProof(allCredStatements, sigBytes, nonceBytes, s.publicKey, indexesToReveal)
+	return &derivation{
+		framedCredential: framedCredential,
+		statements:       allCredStatements,
+		encoded:          encoded,
+		messages:         messages,
+		revealed:         indexesToReveal,
+		signature:        sigBytes,
+	}, nil
+}
+
+// frameCredential Frame an unsigned JSON-LD credential against a frame document, keeping the contexts of the credential.
+// The blank nodes of the credential are framed as IRIs holding their canonical labels, see skolemizeBlankNodes.
+//
+//	ctx context.Context
+//	credential model.JsonLdCredentialNoProof The unsigned JSON-LD credential.
+//	frameDocument model.JsonLDFrame The frame document.
+//
+// returns:
+//
+//	framedCredential model.JsonLdCredential
+//	err error
+func (s *SignatureProofSuite2020) frameCredential(
+	ctx context.Context,
+	credential model.JsonLdCredentialNoProof,
+	frameDocument model.JsonLdFrame,
+) (model.JsonLdCredential, error) {
+	statements, err := s.createVerifyDocumentData(ctx, credential)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
+	skolemized, err := s.normalizer.FromRDF(ctx, skolemizeBlankNodes(statements))
+	if err != nil {
+		return nil, err
+	}
+	framedCredential, err := s.normalizer.Frame(ctx, skolemized, frameDocument)
+	if err != nil {
+		return nil, err
+	}
+	framedCredential[c.CredentialFieldContext] = credential[c.CredentialFieldContext]
+
+	return framedCredential, nil
+}
 
-	// 9. Embed the signature in the derivedProof
+// newDerivedProof Build the derived proof embedding a proof of knowledge of the original signature.
+//
+//	proof model.JsonLdProof The original proof.
+//	nonceBytes []byte The nonce of the verifier.
+//	proofValue []byte The proof of knowledge.
+func newDerivedProof(proof model.JsonLdProof, nonceBytes, proofValue []byte) model.JsonLdProof {
 	derivedProof := model.JsonLdProof{}
 	derivedProof[c.CredentialFieldType] = c.CredentialDerivedProofTypeBbsBlsSig2020
 	derivedProof[c.CredentialFieldProofPurpose] = c.CredentialProofPurpose
 	derivedProof[c.CredentialFieldVerificationMethod] = proof[c.CredentialFieldVerificationMethod]
 	derivedProof[c.CredentialFieldNonce] = base64.StdEncoding.EncodeToString(nonceBytes)
-	derivedProof[c.CredentialFieldProofValue] = base64.StdEncoding.EncodeToString(outputProof)
+	derivedProof[c.CredentialFieldProofValue] = base64.StdEncoding.EncodeToString(proofValue)
 	derivedProof[c.CredentialFieldCreated] = proof[c.CredentialFieldCreated]
 
-	return framedCredentialResult, derivedProof, nil
+	return derivedProof
 }
 
 // createVerifyDocumentData Normalize an unsigned JSON-LD credential.
 //
+//	ctx context.Context
 //	credential model.JsonLdCredentialNoProof The unsigned JSON-LD credential.
 //
 // returns:
 //
 //	normalizedCredential []string
 //	err error
-func (s *SignatureProofSuite2020) createVerifyDocumentData(credential model.JsonLdCredentialNoProof) ([]string, error) {
-	credBytes, err := json.Marshal(credential)
+func (s *SignatureProofSuite2020) createVerifyDocumentData(ctx context.Context, credential model.JsonLdCredentialNoProof) ([]string, error) {
+	return s.normalizer.NormalizeDocumentContext(ctx, credential)
+}
+
+// createRevealedDocumentData Normalize a framed JSON-LD credential, restoring the blank nodes of the original credential.
+//
+//	ctx context.Context
+//	credential model.JsonLdCredentialNoProof The framed JSON-LD credential, without its derived proofs.
+//
+// returns:
+//
+//	normalizedCredential []string
+//	err error
+func (s *SignatureProofSuite2020) createRevealedDocumentData(ctx context.Context, credential model.JsonLdCredentialNoProof) ([]string, error) {
+	statements, err := s.createVerifyDocumentData(ctx, credential)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.normalizer.Normalize(string(credBytes))
+	return restoreBlankNodes(statements), nil
 }
 
 // createVerifyProofData Normalize the proof of a JSON-LD credential.
 //
+//	ctx context.Context
 //	proof model.JsonLDProof The JSON-LD proof to normalize.
 //
 // returns:
@@ -330,7 +728,7 @@ func (s *SignatureProofSuite2020) createVerifyDocumentData(credential model.Json
 //	unsignedProof *model.CredentialProof
 //	normalizedProof []string
 //	err error
-func (s *SignatureProofSuite2020) createVerifyProofData(proof model.JsonLdProof) (model.JsonLdProof, []string, error) {
+func (s *SignatureProofSuite2020) createVerifyProofData(ctx context.Context, proof model.JsonLdProof) (model.JsonLdProof, []string, error) {
 	unsignedProof := deepCopyMap(proof)
 
 	// add proof context if it is compacted
@@ -338,13 +736,11 @@ func (s *SignatureProofSuite2020) createVerifyProofData(proof model.JsonLdProof)
 
 	delete(unsignedProof, c.CredentialFieldNonce)
 	delete(unsignedProof, c.CredentialFieldProofValue)
+	delete(unsignedProof, c.CredentialFieldPredicates)
+	delete(unsignedProof, c.CredentialFieldPseudonym)
+	delete(unsignedProof, c.CredentialFieldCiphersuite)
 
-	proofBytes, err := json.Marshal(unsignedProof)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	proofStatements, err := s.normalizer.Normalize(string(proofBytes))
+	proofStatements, err := s.normalizer.NormalizeDocumentContext(ctx, unsignedProof)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -382,6 +778,7 @@ func (s *SignatureProofSuite2020) getDerivedProofs(framedCredential model.JsonLd
 // getSupportedProofs Retrieve all the proofs within the JSON-LD credential that can be used
 // by this suite to derive a proof.
 //
+//	ctx context.Context
 //	signedCredential model.JsonLdCredential The JSON-LD framed credential.
 //
 // returns:
@@ -389,9 +786,22 @@ func (s *SignatureProofSuite2020) getDerivedProofs(framedCredential model.JsonLd
 //	unsignedCredential model.JsonLdCredentialNoProof
 //	proofs []model.JsonLDProof
 //	err error
-func (s *SignatureProofSuite2020) getSupportedProofs(signedCredential model.JsonLdCredential) (model.JsonLdCredentialNoProof, []model.JsonLdProof, error) {
+func (s *SignatureProofSuite2020) getSupportedProofs(ctx context.Context, signedCredential model.JsonLdCredential) (model.JsonLdCredentialNoProof, []model.JsonLdProof, error) {
+	// 0. Check the ciphersuite of the signatures, which is not kept by the compaction
+	if proofs, err := s.getProofs(signedCredential); err == nil {
+		for _, proof := range proofs {
+			proofType, _ := proof[c.CredentialFieldType].(string)
+			if !slices.Contains(s.supportedDerivedProofTypes, proofType) {
+				continue
+			}
+			if err := checkProofCiphersuite(proof, s.scheme); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
 	// 1. Expand the JSON-LD credential against the proof context
-	expandedCredential, err := s.normalizer.Compact(signedCredential, c.ContextSecurityV2)
+	expandedCredential, err := s.normalizer.Compact(ctx, signedCredential, c.ContextSecurityV2)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -405,7 +815,7 @@ func (s *SignatureProofSuite2020) getSupportedProofs(signedCredential model.Json
 	// 3. Filter out all proofs not supported by the current suite
 	proofs := make([]model.JsonLdProof, 0)
 	for _, proof := range credProofsArray {
-		proofType := proof[c.CredentialFieldType].(string)
+		proofType, _ := proof[c.CredentialFieldType].(string)
 
 		if slices.Contains(s.supportedDerivedProofTypes, proofType) {
 			context := make([]string, 0)
@@ -417,7 +827,7 @@ func (s *SignatureProofSuite2020) getSupportedProofs(signedCredential model.Json
 
 	// 4. Re-compact the credential without the proof
 	delete(expandedCredential, c.CredentialFieldProof)
-	compactedDoc, err := s.normalizer.Compact(expandedCredential, signedCredential[c.CredentialFieldContext])
+	compactedDoc, err := s.normalizer.Compact(ctx, expandedCredential, signedCredential[c.CredentialFieldContext])
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1,11 +1,13 @@
 package core_test
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
 	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
@@ -122,3 +124,123 @@ func (s *SignatureProofSuite2020TestSuite) TestVerifyProof() {
 	}
 	s.Equal(expectedResult, actualResult)
 }
+
+func (s *SignatureProofSuite2020TestSuite) TestVerifyProofWithOutstandingNonce() {
+	blsPublicKeyHex := "98ae11750ca7abb2e8ad1a9e55bc7226c5475a961f2bf4867285be12a519f6ddb5671f999ffd0f3ee6a6b2ea16f6cfa90086c14307bfc4e8e07d9c703603177e96874d8cba268d6d01a34cd8b418a4ffcc3ce5376b339d049cadeba06f959399"
+	publicKey, _ := hex.DecodeString(blsPublicKeyHex)
+	subject := core.NewSignatureProofSuite2020(publicKey, s.options)
+
+	// register the nonce embedded in the derived proof as outstanding
+	store := core.NewInMemoryNonceStore()
+	err := store.Save("4mmd5EVmGd0POg+/4M2l0A==", model.NonceRecord{
+		SessionID: "session-1",
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+	s.NoError(err)
+	options := &model.VerifyProofOptions{
+		NonceChecker: core.NewNonceManager(store, time.Minute),
+		SessionID:    "session-1",
+	}
+
+	// test derived proof
+	var derivedProof model.JsonLdCredential
+	derivedProofBytes, err := os.ReadFile("testdata/derivedProof.json")
+	s.NoError(err)
+	err = json.Unmarshal(derivedProofBytes, &derivedProof)
+	s.NoError(err)
+
+	// check
+	actualResult := subject.VerifyProofWithOptions(derivedProof, options)
+	expectedResult := &model.VerificationResult{
+		Success: true,
+	}
+	s.Equal(expectedResult, actualResult)
+
+	// replay
+	actualResult = subject.VerifyProofWithOptions(derivedProof, options)
+	s.False(actualResult.Success)
+	s.ErrorIs(actualResult.Error, model.ErrNonceNotFound)
+}
+
+func (s *SignatureProofSuite2020TestSuite) TestVerifyProofBatch() {
+	blsPublicKeyHex := "98ae11750ca7abb2e8ad1a9e55bc7226c5475a961f2bf4867285be12a519f6ddb5671f999ffd0f3ee6a6b2ea16f6cfa90086c14307bfc4e8e07d9c703603177e96874d8cba268d6d01a34cd8b418a4ffcc3ce5376b339d049cadeba06f959399"
+	publicKey, _ := hex.DecodeString(blsPublicKeyHex)
+	subject := core.NewSignatureProofSuite2020(publicKey, s.options)
+
+	// test derived proofs
+	derivedProofBytes, err := os.ReadFile("testdata/derivedProof.json")
+	s.NoError(err)
+	derivedProofs := make([]model.JsonLdCredential, 3)
+	for i := range derivedProofs {
+		err = json.Unmarshal(derivedProofBytes, &derivedProofs[i])
+		s.NoError(err)
+	}
+	derivedProofs[1]["issuanceDate"] = "2020-12-03T12:19:52Z"
+
+	// check
+	actualResults := subject.VerifyProofBatch(context.Background(), derivedProofs, &model.BatchOptions{Concurrency: 2})
+	s.Len(actualResults, 3)
+	s.True(actualResults[0].Success)
+	s.False(actualResults[1].Success)
+	s.True(actualResults[2].Success)
+}
+
+func BenchmarkVerifyProof(b *testing.B) {
+	subject := core.NewSignatureProofSuite2020(benchmarkPublicKey(b), benchmarkOptions(b))
+
+	var derivedProof model.JsonLdCredential
+	derivedProofBytes, err := os.ReadFile("testdata/derivedProof.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err = json.Unmarshal(derivedProofBytes, &derivedProof); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if result := subject.VerifyProof(derivedProof); !result.Success {
+			b.Fatal(result.Error)
+		}
+	}
+}
+
+func (s *SignatureProofSuite2020TestSuite) TestBlankNodes() {
+	options := offlineOptions(s.T())
+	publicKey := benchmarkPublicKey(s.T())
+	var unsigned model.JsonLdCredentialNoProof
+	unsignedBytes, err := os.ReadFile("testdata/unsignedCredential.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(unsignedBytes, &unsigned))
+	delete(unsigned, "id")
+	subject := unsigned["credentialSubject"].(map[string]interface{})
+	delete(subject, "id")
+	subject["familyName"] = "_:c14n0 <urn:bnid:_:c14n1>"
+	signed, _, err := core.NewSignatureSuite2020(publicKey, benchmarkPrivateKey(s.T()), options).Sign(unsigned)
+	s.Require().NoError(err)
+
+	// hiding the nested objects changes the canonical labels of the blank nodes of the framed credential
+	frame := model.JsonLdFrame{
+		"@context": unsigned["@context"],
+		"type":     []interface{}{"VerifiableCredential", "PermanentResidentCard"},
+		"credentialSubject": map[string]interface{}{
+			"@explicit":  true,
+			"type":       []interface{}{"PermanentResident", "Person"},
+			"familyName": map[string]interface{}{},
+		},
+	}
+	proofSuite := core.NewSignatureProofSuite2020(publicKey, options)
+	derived, err := proofSuite.DeriveProof(signed, frame, []byte("nonce"))
+	s.Require().NoError(err)
+	s.Regexp(`^urn:bnid:_:c14n\d+$`, derived["id"])
+	derivedSubject := derived["credentialSubject"].(map[string]interface{})
+	s.Equal(subject["familyName"], derivedSubject["familyName"])
+	s.NotContains(derivedSubject, "portraitMetadata")
+	result := proofSuite.VerifyProof(derived)
+	s.True(result.Success, result.Error)
+
+	// the disclosed labels are signed
+	derived["id"], derivedSubject["id"] = derivedSubject["id"], derived["id"]
+	result = proofSuite.VerifyProof(derived)
+	s.False(result.Success)
+}
@@ -0,0 +1,307 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/ietfbbs"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/hyperledger/aries-bbs-go/bbs"
+)
+
+// A signatureScheme creates and verifies the BBS signatures and the derived proofs of the suites.
+// Whatever the scheme, a derived proof starts with the number of signed messages on 2 bytes and the bit vector
+// of the disclosed messages, followed by the proof of knowledge of the signature.
+type signatureScheme interface {
+	// id The ciphersuite of the scheme.
+	id() model.BbsCiphersuite
+	// sign Sign messages with a private key.
+	sign(privateKey, publicKey []byte, messages [][]byte) ([]byte, error)
+	// verify Verify the signature of messages.
+	verify(publicKey, signature []byte, messages [][]byte) error
+	// deriveProof Derive a proof of knowledge of a signature, disclosing the messages of the sorted revealed indexes.
+	deriveProof(publicKey, signature []byte, messages [][]byte, revealed []int, nonce []byte) ([]byte, error)
+	// verifyProof Verify a proof of knowledge of a signature disclosing messages.
+	verifyProof(publicKey, proof []byte, revealed [][]byte, nonce []byte) error
+	// inspectSignature Decode the proof value of a signature, without the messages.
+	inspectSignature(proofValue []byte) (*model.ProofInspection, error)
+	// inspectProof Decode the proof value of a derived proof, without the nonce and the statements.
+	inspectProof(proofValue []byte) (*model.ProofInspection, error)
+}
+
+// ciphersuiteOption Retrieve the BBS ciphersuite from the suite options, the BBS+ scheme if none.
+func ciphersuiteOption(options *model.SignatureSuiteOptions) model.BbsCiphersuite {
+	if options == nil || options.Ciphersuite == "" {
+		return model.BbsCiphersuiteBBSPlus
+	}
+
+	return options.Ciphersuite
+}
+
+// newSignatureScheme Create the signature scheme of a ciphersuite.
+//
+//	ciphersuite model.BbsCiphersuite
+//	rng io.Reader The source of the random factors of the signatures and of the derived proofs.
+//
+// returns:
+//
+//	scheme signatureScheme failing with model.ErrUnknownCiphersuite if the ciphersuite is unknown
+func newSignatureScheme(ciphersuite model.BbsCiphersuite, rng io.Reader) signatureScheme {
+	switch ciphersuite {
+	case model.BbsCiphersuiteBBSPlus:
+		return &bbsPlusScheme{rand: rng}
+	case model.BbsCiphersuiteBLS12381Sha256:
+		return &ietfScheme{ciphersuite: ietfbbs.BLS12381Sha256, name: ciphersuite, rand: rng}
+	case model.BbsCiphersuiteBLS12381Shake256:
+		return &ietfScheme{ciphersuite: ietfbbs.BLS12381Shake256, name: ciphersuite, rand: rng}
+	default:
+		return unknownScheme(ciphersuite)
+	}
+}
+
+// setProofCiphersuite Record the ciphersuite of a scheme in a signature or in a derived proof.
+// The field is omitted for the BBS+ scheme, so that its proofs are unchanged. It is not part of the signed statements:
+// the ciphersuite is bound by the signature itself, which only verifies with its ciphersuite.
+//
+//	proof model.JsonLdProof
+//	scheme signatureScheme
+func setProofCiphersuite(proof model.JsonLdProof, scheme signatureScheme) {
+	if scheme.id() != model.BbsCiphersuiteBBSPlus {
+		proof[c.CredentialFieldCiphersuite] = string(scheme.id())
+	}
+}
+
+// checkProofCiphersuite Check that a signature or a derived proof was created with the ciphersuite of a scheme,
+// the proofs without ciphersuite being BBS+ proofs.
+//
+//	proof model.JsonLdProof
+//	scheme signatureScheme
+//
+// returns:
+//
+//	err error model.ErrCiphersuiteMismatch if the ciphersuites differ
+func checkProofCiphersuite(proof model.JsonLdProof, scheme signatureScheme) error {
+	ciphersuite := model.BbsCiphersuiteBBSPlus
+	if value, ok := proof[c.CredentialFieldCiphersuite]; ok {
+		recorded, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%w: field '%s' is not a string", model.ErrCiphersuiteMismatch, c.CredentialFieldCiphersuite)
+		}
+		ciphersuite = model.BbsCiphersuite(recorded)
+	}
+	if ciphersuite != scheme.id() {
+		return fmt.Errorf("%w: %s proof, %s expected", model.ErrCiphersuiteMismatch, ciphersuite, scheme.id())
+	}
+
+	return nil
+}
+
+// requireBBSPlus Fail if the scheme is not the BBS+ scheme, on which the extensions of the suites are built.
+//
+//	scheme signatureScheme
+//	feature string The extension, e.g. "predicate proofs".
+func requireBBSPlus(scheme signatureScheme, feature string) error {
+	switch scheme := scheme.(type) {
+	case *bbsPlusScheme:
+		return nil
+	case unknownScheme:
+		return scheme.err()
+	default:
+		return fmt.Errorf("%w: %s require the BBS+ scheme", model.ErrUnsupportedByCiphersuite, feature)
+	}
+}
+
+// A bbsPlusScheme is the BBS+ scheme of aries-bbs-go, whose signatures also contain the blinding factor s.
+type bbsPlusScheme struct {
+	rand io.Reader
+}
+
+func (s *bbsPlusScheme) id() model.BbsCiphersuite {
+	return model.BbsCiphersuiteBBSPlus
+}
+
+func (s *bbsPlusScheme) sign(privateKey, _ []byte, messages [][]byte) ([]byte, error) {
+	curve := newBBSCurve()
+	key, err := bbs.NewBBSLib(curve).UnmarshalPrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("create BBS+ signature: unmarshal private key: %w", err)
+	}
+	generators, err := key.PublicKey().ToPublicKeyWithGenerators(len(messages))
+	if err != nil {
+		return nil, fmt.Errorf("build generators from public key: %w", err)
+	}
+
+	// b = g1 * h_0^m_0 * ... * h_n^m_n
+	builder := bbs.NewCommitmentBuilder(len(messages) + 1)
+	builder.Add(curve.GenG1, curve.NewZrFromInt(1))
+	for _, message := range bbs.MessagesToFr(messages, curve) {
+		builder.Add(generators.H[message.Idx], message.FR)
+	}
+
+	return signCommitment(curve, s.rand, key, generators, builder.Build())
+}
+
+func (s *bbsPlusScheme) verify(publicKey, signature []byte, messages [][]byte) error {
+	curve := newBBSCurve()
+
+	return verifySignature(curve, bbs.MessagesToFr(messages, curve), signature, publicKey)
+}
+
+func (s *bbsPlusScheme) deriveProof(publicKey, signature []byte, messages [][]byte, revealed []int, nonce []byte) ([]byte, error) {
+	curve := newBBSCurve()
+	lib := bbs.NewBBSLib(curve)
+	generators, err := publicKeyGenerators(lib, publicKey, len(messages))
+	if err != nil {
+		return nil, err
+	}
+	committed, err := newProofOfKnowledge(curve, lib, s.rand, generators, signature, bbs.MessagesToFr(messages, curve), revealed, nil)
+	if err != nil {
+		return nil, err
+	}
+	challenge := proofOfKnowledgeChallenge(curve, committed.ToBytes(), nonce)
+
+	return proofOfKnowledgeValue(committed.GenerateProof(challenge), len(messages), revealed)
+}
+
+func (s *bbsPlusScheme) verifyProof(publicKey, proof []byte, revealed [][]byte, nonce []byte) error {
+	// aries-bbs-go panics on the proofs whose layout differs, e.g. the proofs of the other ciphersuites
+	if _, err := s.inspectProof(proof); err != nil {
+		return err
+	}
+
+	return newBBSScheme().VerifyProof(revealed, proof, nonce, publicKey)
+}
+
+func (s *bbsPlusScheme) inspectSignature(proofValue []byte) (*model.ProofInspection, error) {
+	return inspectSignatureValue(newBBSCurve(), proofValue)
+}
+
+func (s *bbsPlusScheme) inspectProof(proofValue []byte) (*model.ProofInspection, error) {
+	return inspectDerivedProofValue(newBBSCurve(), proofValue)
+}
+
+// An ietfScheme is a ciphersuite of the IETF CFRG BBS draft. The signatures have no header,
+// and the nonce of the verifier is the presentation header of the derived proofs.
+type ietfScheme struct {
+	ciphersuite *ietfbbs.Ciphersuite
+	name        model.BbsCiphersuite
+	rand        io.Reader
+}
+
+func (s *ietfScheme) id() model.BbsCiphersuite {
+	return s.name
+}
+
+func (s *ietfScheme) sign(privateKey, publicKey []byte, messages [][]byte) ([]byte, error) {
+	signature, err := s.ciphersuite.Sign(privateKey, publicKey, nil, messages)
+	if err != nil {
+		return nil, fmt.Errorf("create BBS signature: %w", err)
+	}
+
+	return signature, nil
+}
+
+func (s *ietfScheme) verify(publicKey, signature []byte, messages [][]byte) error {
+	return s.ciphersuite.Verify(publicKey, signature, nil, messages)
+}
+
+func (s *ietfScheme) deriveProof(publicKey, signature []byte, messages [][]byte, revealed []int, nonce []byte) ([]byte, error) {
+	proof, err := s.ciphersuite.ProofGen(s.rand, publicKey, signature, nil, nonce, messages, revealed)
+	if err != nil {
+		return nil, fmt.Errorf("derive proof: %w", err)
+	}
+
+	return proofOfKnowledgeValue(proof, len(messages), revealed)
+}
+
+func (s *ietfScheme) verifyProof(publicKey, proof []byte, revealed [][]byte, nonce []byte) error {
+	// ParsePoKPayload reverses the bit vector of the revealed messages in place
+	payload, err := bbs.ParsePoKPayload(bytes.Clone(proof))
+	if err != nil {
+		return fmt.Errorf("parse signature proof: %w", err)
+	}
+	if len(payload.Revealed) != len(revealed) {
+		return fmt.Errorf("%d disclosed messages, %d expected", len(revealed), len(payload.Revealed))
+	}
+
+	return s.ciphersuite.ProofVerify(publicKey, proof[payload.LenInBytes():], nil, nonce, revealed, payload.Revealed)
+}
+
+// inspectSignature A signature is the compressed point A followed by the scalar e.
+func (s *ietfScheme) inspectSignature(proofValue []byte) (*model.ProofInspection, error) {
+	curve := newBBSCurve()
+	reader := &proofValueReader{data: proofValue}
+	reader.read("A", curve.CompressedG1ByteSize)
+	reader.read("e", curve.ScalarByteSize)
+	if err := reader.finish(); err != nil {
+		return nil, err
+	}
+
+	return &model.ProofInspection{
+		Type:       c.CredentialProofTypeBbsBlsSig2020,
+		Size:       len(proofValue),
+		Components: reader.components,
+	}, nil
+}
+
+// inspectProof A proof of knowledge is the compressed points Ā, B̄ and D followed by the responses for e, r1, r3
+// and the hidden messages, and by the challenge.
+func (s *ietfScheme) inspectProof(proofValue []byte) (*model.ProofInspection, error) {
+	curve := newBBSCurve()
+	reader, messagesCount, revealed, err := readProofPayload(proofValue)
+	if err != nil {
+		return nil, err
+	}
+	hidden := messagesCount - len(revealed)
+
+	reader.read("Ā", curve.CompressedG1ByteSize)
+	reader.read("B̄", curve.CompressedG1ByteSize)
+	reader.read("D", curve.CompressedG1ByteSize)
+	reader.read("ê", curve.ScalarByteSize)
+	reader.read("r̂1", curve.ScalarByteSize)
+	reader.read("r̂3", curve.ScalarByteSize)
+	reader.read("m̂", hidden*curve.ScalarByteSize)
+	reader.read("challenge", curve.ScalarByteSize)
+	if err := reader.finish(); err != nil {
+		return nil, err
+	}
+
+	return derivedProofInspection(proofValue, reader, messagesCount, revealed), nil
+}
+
+// An unknownScheme fails every operation of a suite configured with an unknown ciphersuite.
+type unknownScheme model.BbsCiphersuite
+
+func (s unknownScheme) err() error {
+	return fmt.Errorf("%w: '%s'", model.ErrUnknownCiphersuite, string(s))
+}
+
+func (s unknownScheme) id() model.BbsCiphersuite {
+	return model.BbsCiphersuite(s)
+}
+
+func (s unknownScheme) sign([]byte, []byte, [][]byte) ([]byte, error) {
+	return nil, s.err()
+}
+
+func (s unknownScheme) verify([]byte, []byte, [][]byte) error {
+	return s.err()
+}
+
+func (s unknownScheme) deriveProof([]byte, []byte, [][]byte, []int, []byte) ([]byte, error) {
+	return nil, s.err()
+}
+
+func (s unknownScheme) verifyProof([]byte, []byte, [][]byte, []byte) error {
+	return s.err()
+}
+
+func (s unknownScheme) inspectSignature([]byte) (*model.ProofInspection, error) {
+	return nil, s.err()
+}
+
+func (s unknownScheme) inspectProof([]byte) (*model.ProofInspection, error) {
+	return nil, s.err()
+}
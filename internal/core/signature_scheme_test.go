@@ -0,0 +1,167 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"os"
+	"testing"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type SignatureSchemeTestSuite struct {
+	suite.Suite
+	publicKey  []byte
+	privateKey []byte
+	credential model.JsonLdCredentialNoProof
+	frame      model.JsonLdFrame
+}
+
+func TestSignatureSchemeTestSuite(t *testing.T) {
+	suite.Run(t, new(SignatureSchemeTestSuite))
+}
+
+func (s *SignatureSchemeTestSuite) SetupTest() {
+	s.publicKey = benchmarkPublicKey(s.T())
+	s.privateKey = benchmarkPrivateKey(s.T())
+
+	credentialBytes, err := os.ReadFile("testdata/unsignedCredential.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(credentialBytes, &s.credential))
+	frameBytes, err := os.ReadFile("testdata/frame.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(frameBytes, &s.frame))
+}
+
+// options Create suite options with a ciphersuite.
+func (s *SignatureSchemeTestSuite) options(ciphersuite model.BbsCiphersuite) *model.SignatureSuiteOptions {
+	options := offlineOptions(s.T())
+	options.Ciphersuite = ciphersuite
+
+	return options
+}
+
+func (s *SignatureSchemeTestSuite) TestSignAndDeriveProof() {
+	for _, ciphersuite := range []model.BbsCiphersuite{model.BbsCiphersuiteBLS12381Sha256, model.BbsCiphersuiteBLS12381Shake256} {
+		options := s.options(ciphersuite)
+		signed, _, err := core.NewSignatureSuite2020(s.publicKey, s.privateKey, options).Sign(s.credential)
+		s.Require().NoError(err)
+		s.Equal(string(ciphersuite), signed[c.CredentialFieldProof].(model.JsonLdProof)[c.CredentialFieldCiphersuite])
+		result := core.NewSignatureSuite2020(s.publicKey, nil, options).Verify(signed)
+		s.True(result.Success, result.Error)
+
+		// the BBS+ scheme and the other ciphersuites reject the signature
+		result = core.NewSignatureSuite2020(s.publicKey, nil, offlineOptions(s.T())).Verify(signed)
+		s.ErrorIs(result.Error, model.ErrCiphersuiteMismatch)
+		other := model.BbsCiphersuiteBLS12381Shake256
+		if ciphersuite == other {
+			other = model.BbsCiphersuiteBLS12381Sha256
+		}
+		result = core.NewSignatureSuite2020(s.publicKey, nil, s.options(other)).Verify(signed)
+		s.ErrorIs(result.Error, model.ErrCiphersuiteMismatch)
+		_, err = core.NewSignatureProofSuite2020(s.publicKey, s.options(other)).DeriveProof(signed, s.frame, []byte("verifier challenge"))
+		s.ErrorIs(err, model.ErrCiphersuiteMismatch)
+
+		proofSuite := core.NewSignatureProofSuite2020(s.publicKey, options)
+		derived, err := proofSuite.DeriveProof(signed, s.frame, []byte("verifier challenge"))
+		s.Require().NoError(err)
+		s.Equal(string(ciphersuite), derived[c.CredentialFieldProof].(model.JsonLdProof)[c.CredentialFieldCiphersuite])
+		result = proofSuite.VerifyProof(derived)
+		s.True(result.Success, result.Error)
+		result = core.NewSignatureProofSuite2020(s.publicKey, offlineOptions(s.T())).VerifyProof(derived)
+		s.ErrorIs(result.Error, model.ErrCiphersuiteMismatch)
+
+		inspections, err := proofSuite.InspectProof(context.Background(), derived)
+		s.Require().NoError(err)
+		s.Require().Len(inspections, 1)
+		s.Equal("challenge", inspections[0].Components[len(inspections[0].Components)-1].Name)
+		inspection, err := core.NewSignatureSuite2020(s.publicKey, nil, options).InspectProof(context.Background(), signed)
+		s.Require().NoError(err)
+		s.Len(inspection.Components, 2)
+
+		results := core.NewSignatureSuite2020(s.publicKey, nil, options).VerifyBatch(context.Background(), []model.JsonLdCredential{signed, derived}, nil)
+		s.True(results[0].Success, results[0].Error)
+		s.False(results[1].Success)
+	}
+}
+
+func (s *SignatureSchemeTestSuite) TestBBSPlusProofRejected() {
+	signed, _, err := core.NewSignatureSuite2020(s.publicKey, s.privateKey, offlineOptions(s.T())).Sign(s.credential)
+	s.Require().NoError(err)
+	s.NotContains(signed[c.CredentialFieldProof], c.CredentialFieldCiphersuite)
+	derived, err := core.NewSignatureProofSuite2020(s.publicKey, offlineOptions(s.T())).DeriveProof(signed, s.frame, []byte("verifier challenge"))
+	s.Require().NoError(err)
+	s.NotContains(derived[c.CredentialFieldProof], c.CredentialFieldCiphersuite)
+
+	for _, ciphersuite := range []model.BbsCiphersuite{model.BbsCiphersuiteBLS12381Sha256, model.BbsCiphersuiteBLS12381Shake256} {
+		s.False(core.NewSignatureSuite2020(s.publicKey, nil, s.options(ciphersuite)).Verify(signed).Success)
+		s.False(core.NewSignatureProofSuite2020(s.publicKey, s.options(ciphersuite)).VerifyProof(derived).Success)
+	}
+}
+
+func (s *SignatureSchemeTestSuite) TestCiphersuiteRecorded() {
+	options := s.options(model.BbsCiphersuiteBLS12381Sha256)
+	signed, _, err := core.NewSignatureSuite2020(s.publicKey, s.privateKey, options).Sign(s.credential)
+	s.Require().NoError(err)
+	derived, err := core.NewSignatureProofSuite2020(s.publicKey, options).DeriveProof(signed, s.frame, []byte("verifier challenge"))
+	s.Require().NoError(err)
+
+	tests := []struct {
+		name        string
+		ciphersuite any // nil to strip the field
+	}{
+		{"stripped", nil},
+		{"relabeled", string(model.BbsCiphersuiteBLS12381Shake256)},
+		{"not a string", 1},
+	}
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			tamper := func(credential model.JsonLdCredential) model.JsonLdCredential {
+				credential = maps.Clone(credential)
+				proof := maps.Clone(credential[c.CredentialFieldProof].(model.JsonLdProof))
+				credential[c.CredentialFieldProof] = proof
+				delete(proof, c.CredentialFieldCiphersuite)
+				if test.ciphersuite != nil {
+					proof[c.CredentialFieldCiphersuite] = test.ciphersuite
+				}
+
+				return credential
+			}
+
+			result := core.NewSignatureSuite2020(s.publicKey, nil, options).Verify(tamper(signed))
+			s.ErrorIs(result.Error, model.ErrCiphersuiteMismatch)
+			result = core.NewSignatureProofSuite2020(s.publicKey, options).VerifyProof(tamper(derived))
+			s.ErrorIs(result.Error, model.ErrCiphersuiteMismatch)
+		})
+	}
+}
+
+func (s *SignatureSchemeTestSuite) TestExtensionsRequireBBSPlus() {
+	options := s.options(model.BbsCiphersuiteBLS12381Sha256)
+	options.PredicateClaims = []string{birthDateIRI}
+	_, _, err := core.NewSignatureSuite2020(s.publicKey, s.privateKey, options).Sign(s.credential)
+	s.ErrorIs(err, model.ErrUnsupportedByCiphersuite)
+
+	options = s.options(model.BbsCiphersuiteBLS12381Sha256)
+	_, err = core.NewSignatureSuite2020(s.publicKey, s.privateKey, options).PrepareHolderBinding(context.Background(), s.credential)
+	s.ErrorIs(err, model.ErrUnsupportedByCiphersuite)
+
+	signed, _, err := core.NewSignatureSuite2020(s.publicKey, s.privateKey, options).Sign(s.credential)
+	s.Require().NoError(err)
+	secret, err := core.NewHolderSecret()
+	s.Require().NoError(err)
+	_, err = core.NewSignatureProofSuite2020(s.publicKey, options).DeriveProofWithOptions(
+		context.Background(), signed, s.frame, []byte("verifier challenge"),
+		&model.DeriveProofOptions{HolderSecret: secret, VerifierID: "https://verifier.example"},
+	)
+	s.ErrorIs(err, model.ErrUnsupportedByCiphersuite)
+}
+
+func (s *SignatureSchemeTestSuite) TestUnknownCiphersuite() {
+	_, _, err := core.NewSignatureSuite2020(s.publicKey, s.privateKey, s.options("BBS-2")).Sign(s.credential)
+	s.ErrorIs(err, model.ErrUnknownCiphersuite)
+}
@@ -1,9 +1,14 @@
 package core
 
 import (
+	"context"
+	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
+	"time"
 
 	ml "github.com/IBM/mathlib"
 	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
@@ -14,7 +19,7 @@ import (
 // SignatureSuite2020 is initialized with:
 //
 //	publicKey []byte
-//	privateKey []byte nullable, required when issuance is needed
+//	privateKey []byte nullable, required when issuance is needed unless a model.Signer is provided
 //	options *model.SignatureSuiteOptions nullable, permits to add/overwrite default document loader and pre-defined contexts
 //
 // Default document loader will has following contexts pre-loaded:
@@ -24,12 +29,21 @@ import (
 //   - https://w3id.org/vc-revocation-list-2020/v1
 //
 // If context is not found, document loader will try to download it from the internet
+// and keep it in the process-wide ContextCache.
+//
+// A SignatureSuite2020 is safe for concurrent use by multiple goroutines,
+// provided that the custom document loader, if any, is safe for concurrent use.
 type SignatureSuite2020 struct {
-	publicKey  []byte
-	privateKey []byte
-	keyEncoder *KeyEncoder
-	normalizer *normalizer
-	curve      *bbs.BBSG2Pub
+	publicKey          []byte
+	signer             model.Signer // nil if the suite can only verify
+	verificationMethod string       // empty if the did:key of the public key has to be used
+	keyEncoder         *KeyEncoder
+	normalizer         *normalizer
+	issuerBinding      *issuerBinding
+	policyEngine       *policyEngine
+	predicateClaims    []string         // IRIs of the claims signed with an integer encoding
+	clock              func() time.Time // the creation time of the proofs
+	scheme             signatureScheme
 }
 
 // NewSignatureSuite2020 initializes and returns SignatureSuite
@@ -38,17 +52,65 @@ type SignatureSuite2020 struct {
 //	privateKey []byte nullable
 //	options *model.SignatureSuiteOptions nullable
 func NewSignatureSuite2020(publicKey, privateKey []byte, options *model.SignatureSuiteOptions) *SignatureSuite2020 {
+	scheme := newSignatureScheme(ciphersuiteOption(options), randOption(options))
+	var signer model.Signer
+	if privateKey != nil {
+		signer = newInMemorySigner(publicKey, privateKey, scheme)
+	}
+
 	return &SignatureSuite2020{
-		publicKey:  publicKey,
-		privateKey: privateKey,
-		keyEncoder: &KeyEncoder{},
-		normalizer: NewNormalizer(options),
-		curve:      bbs.New(ml.Curves[ml.BLS12_381_BBS]),
+		publicKey:          publicKey,
+		signer:             signer,
+		verificationMethod: verificationMethodOption(options),
+		keyEncoder:         &KeyEncoder{},
+		normalizer:         NewNormalizer(options),
+		issuerBinding:      newIssuerBinding(options),
+		policyEngine:       newPolicyEngine(options),
+		predicateClaims:    predicateClaimsOption(options),
+		clock:              clockOption(options),
+		scheme:             scheme,
+	}
+}
+
+// NewSignatureSuite2020WithSigner initializes and returns SignatureSuite signing with a model.Signer,
+// e.g. backed by a KMS or an HSM. The public key is retrieved from the signer.
+//
+//	ctx context.Context
+//	signer model.Signer
+//	options *model.SignatureSuiteOptions nullable
+//
+// returns:
+//
+//	suite *SignatureSuite2020
+//	err error if the public key cannot be retrieved from the signer
+func NewSignatureSuite2020WithSigner(ctx context.Context, signer model.Signer, options *model.SignatureSuiteOptions) (*SignatureSuite2020, error) {
+	publicKey, err := signer.PublicKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("retrieve public key from signer: %w", err)
 	}
+
+	return &SignatureSuite2020{
+		publicKey:          publicKey,
+		signer:             signer,
+		verificationMethod: verificationMethodOption(options),
+		keyEncoder:         &KeyEncoder{},
+		normalizer:         NewNormalizer(options),
+		issuerBinding:      newIssuerBinding(options),
+		policyEngine:       newPolicyEngine(options),
+		predicateClaims:    predicateClaimsOption(options),
+		clock:              clockOption(options),
+		scheme:             newSignatureScheme(ciphersuiteOption(options), randOption(options)),
+	}, nil
+}
+
+// PublicKey Return the BBS+ public key of the suite.
+func (s *SignatureSuite2020) PublicKey() []byte {
+	return s.publicKey
 }
 
 // Sign Create a JSON-LD signed credential with a BbsBlsSignature2020 signature.
-// Requires during initialization provision of publicKey and privateKey.
+// Requires during initialization provision of publicKey and privateKey, or of a model.Signer.
+// If the credential contains predicate claims, the signer must implement model.BlindSigner.
 //
 //	credential model.JsonLdCredentialNoProof The JSON-LD credential to be signed. If issuer is not specified, it will be added to the template based on "did:key" method.
 //
@@ -58,35 +120,231 @@ func NewSignatureSuite2020(publicKey, privateKey []byte, options *model.Signatur
 //	jsonCredential string JSON representation of the credential
 //	err error
 func (s *SignatureSuite2020) Sign(credential model.JsonLdCredentialNoProof) (model.JsonLdCredential, string, error) {
-	credCopy := deepCopyMap(credential)
-	s.addCredentialIssuerIfEmpty(credCopy)
+	return s.SignContext(context.Background(), credential)
+}
 
-	proof, err := s.createUnsignedProof()
+// SignContext Sign a JSON-LD credential.
+// The context is checked while loading the remote contexts and during the canonicalization, and passed to the signer.
+//
+//	ctx context.Context
+//	credential model.JsonLdCredentialNoProof The JSON-LD credential to be signed.
+//
+// returns:
+//
+//	signedCredential model.JsonLdCredential
+//	jsonCredential string JSON representation of the credential
+//	err error
+func (s *SignatureSuite2020) SignContext(ctx context.Context, credential model.JsonLdCredentialNoProof) (model.JsonLdCredential, string, error) {
+	credCopy, proof, dataForSigning, err := s.prepareCredentialForSigning(ctx, credential)
 	if err != nil {
 		return nil, "", err
 	}
 
-	dataForSigning, err := s.prepareDataForSigning(credCopy, proof)
+	encoded, err := encodeClaims(s.predicateClaims, dataForSigning)
 	if err != nil {
 		return nil, "", err
 	}
+	if len(encoded) > 0 {
+		if err := requireBBSPlus(s.scheme, "predicate claims"); err != nil {
+			return nil, "", err
+		}
+		// the encodings are not hashed: sign them as committed messages
+		curve := newBBSCurve()
+		signature, err := s.signCommitted(ctx, dataForSigning, encodingMessages(curve, len(dataForSigning), encoded), nil)
+		if err != nil {
+			return nil, "", err
+		}
 
-	signature, err := s.createBLSSignature(dataForSigning)
+		return attachProof(credCopy, proof, base64.StdEncoding.EncodeToString(signature))
+	}
+
+	signature, err := s.createBLSSignature(ctx, dataForSigning)
 	if err != nil {
 		return nil, "", err
 	}
+	setProofCiphersuite(proof, s.scheme)
 
-	// TODO: support the possibility to add the new proof to the list of existing proofs -> support array of proofs
-	model.DeleteContextFromJsonLdProof(proof) // Delete context since it is not needed for representation -> compact proof format
-	proof[c.CredentialFieldProofValue] = signature
-	credCopy[c.CredentialFieldProof] = proof
+	return attachProof(credCopy, proof, signature)
+}
 
-	jsonLdDoc, err := json.Marshal(credCopy)
+// PrepareHolderBinding Compute the number of messages of a credential to bind to its holder,
+// that the holder needs to commit to its secret with NewHolderBindingRequest.
+//
+//	ctx context.Context
+//	credential model.JsonLdCredentialNoProof The JSON-LD credential to be signed.
+//
+// returns:
+//
+//	messagesCount int
+//	err error
+func (s *SignatureSuite2020) PrepareHolderBinding(ctx context.Context, credential model.JsonLdCredentialNoProof) (int, error) {
+	if err := requireBBSPlus(s.scheme, "holder bindings"); err != nil {
+		return 0, err
+	}
+	_, _, dataForSigning, err := s.prepareCredentialForSigning(ctx, credential)
+	if err != nil {
+		return 0, err
+	}
+	encoded, err := encodeClaims(s.predicateClaims, dataForSigning)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(dataForSigning) + 2*len(encoded) + 2, nil
+}
+
+// SignHolderBound Sign a JSON-LD credential bound to the secret its holder committed to, without learning the secret.
+// The signer of the suite must implement model.BlindSigner.
+// The signature is blinded: the holder completes it with CompleteHolderBinding.
+//
+//	ctx context.Context
+//	credential model.JsonLdCredentialNoProof The JSON-LD credential to be signed, as passed to PrepareHolderBinding.
+//	request *model.HolderBindingRequest The commitment of the holder.
+//	issuerNonce []byte The nonce sent to the holder to create the request.
+//
+// returns:
+//
+//	signedCredential model.JsonLdCredential
+//	jsonCredential string JSON representation of the credential
+//	err error
+func (s *SignatureSuite2020) SignHolderBound(
+	ctx context.Context,
+	credential model.JsonLdCredentialNoProof,
+	request *model.HolderBindingRequest,
+	issuerNonce []byte,
+) (model.JsonLdCredential, string, error) {
+	if s.signer == nil {
+		return nil, "", model.ErrNoSigner
+	}
+	if _, ok := s.signer.(model.BlindSigner); !ok {
+		return nil, "", model.ErrBlindSigningUnsupported
+	}
+	if err := requireBBSPlus(s.scheme, "holder bindings"); err != nil {
+		return nil, "", err
+	}
+
+	credCopy, proof, dataForSigning, err := s.prepareCredentialForSigning(ctx, credential)
+	if err != nil {
+		return nil, "", err
+	}
+	encoded, err := encodeClaims(s.predicateClaims, dataForSigning)
 	if err != nil {
 		return nil, "", err
 	}
 
-	return credCopy, string(jsonLdDoc), nil
+	// the secret is committed by the holder, the encodings and the marker are committed by the issuer
+	curve := newBBSCurve()
+	messages := encodingMessages(curve, len(dataForSigning), encoded)
+	messages = append(messages, bbs.ParseSignatureMessage([]byte(c.HolderBindingMarker), len(dataForSigning)+len(messages), curve))
+	expectedCount := len(dataForSigning) + len(messages) + 1
+	if request.MessagesCount != expectedCount {
+		return nil, "", fmt.Errorf("%w: %d messages committed, %d expected", model.ErrInvalidHolderBindingRequest, request.MessagesCount, expectedCount)
+	}
+	if err := verifyHolderBindingRequest(s.publicKey, request, issuerNonce); err != nil {
+		return nil, "", err
+	}
+	holderCommitment, err := curve.NewG1FromCompressed(request.Commitment)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %s", model.ErrInvalidHolderBindingRequest, err.Error())
+	}
+
+	signature, err := s.signCommitted(ctx, dataForSigning, messages, holderCommitment)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return attachProof(credCopy, proof, base64.StdEncoding.EncodeToString(signature))
+}
+
+// CompleteHolderBinding Unblind the signature of a credential signed by SignHolderBound, and verify it.
+// The suite is initialized with the public key of the issuer.
+//
+//	ctx context.Context
+//	credential model.JsonLdCredential The credential received from the issuer.
+//	secret model.HolderSecret The secret of the holder.
+//	blinding []byte The blinding factor returned by NewHolderBindingRequest.
+//
+// returns:
+//
+//	signedCredential model.JsonLdCredential
+//	err error if the signature is not valid for the secret
+func (s *SignatureSuite2020) CompleteHolderBinding(
+	ctx context.Context,
+	credential model.JsonLdCredential,
+	secret model.HolderSecret,
+	blinding []byte,
+) (model.JsonLdCredential, error) {
+	if err := requireBBSPlus(s.scheme, "holder bindings"); err != nil {
+		return nil, err
+	}
+	credCopy := deepCopyMap(credential)
+	proof, ok := credCopy[c.CredentialFieldProof].(model.JsonLdProof)
+	if !ok {
+		return nil, fmt.Errorf("provided JSON-LD credential doesn't contain object '%s'", c.CredentialFieldProof)
+	}
+	proofValue, ok := proof[c.CredentialFieldProofValue].(string)
+	if !ok {
+		return nil, fmt.Errorf("proof doesn't contain field '%s'", c.CredentialFieldProofValue)
+	}
+	blinded, err := base64.StdEncoding.DecodeString(proofValue)
+	if err != nil {
+		return nil, fmt.Errorf("proof value could not be decoded from base64 '%s'", err.Error())
+	}
+
+	signature, err := unblindSignature(blinded, blinding)
+	if err != nil {
+		return nil, err
+	}
+
+	signingData, err := s.provideSigningData(ctx, credCopy)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := encodeClaims(s.predicateClaims, signingData)
+	if err != nil {
+		return nil, err
+	}
+	err = verifySignature(newBBSCurve(), signatureMessages(newBBSCurve(), signingData, encoded, secret), signature, s.publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("signature verification failed: '%s'", err.Error())
+	}
+
+	proof[c.CredentialFieldProofValue] = base64.StdEncoding.EncodeToString(signature)
+
+	return credCopy, nil
+}
+
+// IsHolderBound Tell whether a signed credential is bound to a holder secret, from the layout of its signed messages:
+// a bound credential is signed over the holder binding marker and the secret after its statements.
+// A BBS+ signature does not record its number of messages, so the layout is found by verifying the signature
+// against the bound layout and the unbound one.
+//
+//	ctx context.Context
+//	credential model.JsonLdCredential The credential, signed by the issuer of the suite.
+//	secret model.HolderSecret The secret the credential would be bound to.
+//
+// returns:
+//
+//	bound bool
+//	err error if the signature does not verify with either layout, e.g. a credential bound to another secret
+func (s *SignatureSuite2020) IsHolderBound(ctx context.Context, credential model.JsonLdCredential, secret model.HolderSecret) (bool, error) {
+	if requireBBSPlus(s.scheme, "holder bindings") != nil {
+		return false, nil
+	}
+	statements, encoded, signature, result := s.prepareVerificationData(ctx, credential)
+	if result != nil {
+		return false, result.Error
+	}
+
+	curve := newBBSCurve()
+	if verifySignature(curve, signatureMessages(curve, statements, encoded, secret), signature, s.publicKey) == nil {
+		return true, nil
+	}
+	if err := s.verifyMessages(statements, encoded, signature); err != nil {
+		return false, fmt.Errorf("signature verification failed with and without the holder secret: '%s'", err.Error())
+	}
+
+	return false, nil
 }
 
 // ProvideSigningData prepares the array of the messages which will be signed/verified during issuance process.
@@ -103,15 +361,39 @@ func (s *SignatureSuite2020) Sign(credential model.JsonLdCredentialNoProof) (mod
 //	messages [][]byte
 //	err error
 func (s *SignatureSuite2020) ProvideSigningData(credential model.JsonLdCredential) ([][]byte, error) {
+	return s.provideSigningData(context.Background(), credential)
+}
+
+// provideSigningData Prepare the array of the messages to verify, within the context of an operation.
+func (s *SignatureSuite2020) provideSigningData(ctx context.Context, credential model.JsonLdCredential) ([][]byte, error) {
+	credCopy, proof, err := splitSignedCredential(credential)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.prepareDataForSigning(ctx, credCopy, proof)
+}
+
+// splitSignedCredential Copy a signed JSON-LD credential into the credential and the proof that are normalized for signing.
+//
+//	credential model.JsonLdCredential
+//
+// returns:
+//
+//	credential model.JsonLdCredentialNoProof
+//	proof model.JsonLdProof without the proof value, with its contexts
+//	err error if the credential doesn't contain a proof
+func splitSignedCredential(credential model.JsonLdCredential) (model.JsonLdCredentialNoProof, model.JsonLdProof, error) {
 	credCopy := deepCopyMap(credential)
 
 	proof, ok := credCopy[c.CredentialFieldProof].(model.JsonLdProof)
 	if !ok {
-		return nil, fmt.Errorf("provided JSON-LD credential doesn't contain object 'proof'")
+		return nil, nil, fmt.Errorf("provided JSON-LD credential doesn't contain object 'proof'")
 	}
 
 	delete(credCopy, c.CredentialFieldProof)
 	delete(proof, c.CredentialFieldProofValue)
+	delete(proof, c.CredentialFieldCiphersuite)
 
 	// add proof context if it is compacted
 	if proof[c.CredentialFieldContext] == nil {
@@ -121,7 +403,7 @@ func (s *SignatureSuite2020) ProvideSigningData(credential model.JsonLdCredentia
 		}
 	}
 
-	return s.prepareDataForSigning(credCopy, proof)
+	return credCopy, proof, nil
 }
 
 // Verify verifies a signed JSON-LD credential.
@@ -132,9 +414,204 @@ func (s *SignatureSuite2020) ProvideSigningData(credential model.JsonLdCredentia
 //
 //	result *model.VerificationResult
 func (s *SignatureSuite2020) Verify(credential model.JsonLdCredential) *model.VerificationResult {
-	signingData, err := s.ProvideSigningData(credential)
+	return s.VerifyContext(context.Background(), credential)
+}
+
+// VerifyContext verifies a signed JSON-LD credential.
+// The context is checked while loading the remote contexts and during the canonicalization.
+//
+//	ctx context.Context
+//	credential model.JsonLdCredential
+//
+// returns:
+//
+//	result *model.VerificationResult
+func (s *SignatureSuite2020) VerifyContext(ctx context.Context, credential model.JsonLdCredential) *model.VerificationResult {
+	statements, encoded, signature, result := s.prepareVerificationData(ctx, credential)
+	if result != nil {
+		return result
+	}
+
+	err := s.verifyMessages(statements, encoded, signature)
 	if err != nil {
 		return &model.VerificationResult{
+			Success: false,
+			Error:   fmt.Errorf("signature verification failed: '%s'", err.Error()),
+		}
+	}
+
+	return s.policyEngine.apply(credential, s.publicKey)
+}
+
+// SignBatch Sign a batch of JSON-LD credentials in parallel.
+// Requires during initialization provision of publicKey and privateKey, or of a model.Signer.
+//
+//	ctx context.Context If the context is done, the credentials not yet processed will report its error.
+//	credentials []model.JsonLdCredentialNoProof The JSON-LD credentials to be signed.
+//	options *model.BatchOptions nullable
+//
+// returns:
+//
+//	results []*model.SignResult one result per credential, in the same order
+func (s *SignatureSuite2020) SignBatch(
+	ctx context.Context,
+	credentials []model.JsonLdCredentialNoProof,
+	options *model.BatchOptions,
+) []*model.SignResult {
+	results := make([]*model.SignResult, len(credentials))
+
+	forEachConcurrently(ctx, len(credentials), options, func(i int) {
+		signedCredential, jsonCredential, err := s.SignContext(ctx, credentials[i])
+		results[i] = &model.SignResult{
+			Credential:     signedCredential,
+			JsonCredential: jsonCredential,
+			Error:          err,
+		}
+	}, func(i int) {
+		results[i] = &model.SignResult{
+			Error: fmt.Errorf("batch processing interrupted: %w", ctx.Err()),
+		}
+	})
+
+	return results
+}
+
+// VerifyBatch Verify a batch of signed JSON-LD credentials.
+// The credentials are normalized in parallel and their signatures are verified together with a randomized
+// linear combination, which requires only two pairings for the whole batch. If the batch verification fails,
+// the signatures are verified one by one to find out the invalid ones.
+//
+//	ctx context.Context If the context is done, the credentials not yet processed will report its error.
+//	credentials []model.JsonLdCredential The signed JSON-LD credentials.
+//	options *model.BatchOptions nullable
+//
+// returns:
+//
+//	results []*model.VerificationResult one result per credential, in the same order
+func (s *SignatureSuite2020) VerifyBatch(
+	ctx context.Context,
+	credentials []model.JsonLdCredential,
+	options *model.BatchOptions,
+) []*model.VerificationResult {
+	results := make([]*model.VerificationResult, len(credentials))
+
+	// the pairing equations can only be combined for the BBS+ signatures
+	if _, ok := s.scheme.(*bbsPlusScheme); !ok {
+		forEachConcurrently(ctx, len(credentials), options, func(i int) {
+			results[i] = s.VerifyContext(ctx, credentials[i])
+		}, func(i int) {
+			results[i] = cancelledResult(ctx)
+		})
+
+		return results
+	}
+
+	verifier, err := newBatchSignatureVerifier(s.publicKey)
+	if err != nil {
+		for i := range results {
+			results[i] = &model.VerificationResult{
+				Success: false,
+				Error:   fmt.Errorf("signature verification failed: '%s'", err.Error()),
+			}
+		}
+
+		return results
+	}
+
+	// 1. Normalize the credentials and compute the commitments to the signed messages
+	signatures := make([]*batchSignature, len(credentials))
+	forEachConcurrently(ctx, len(credentials), options, func(i int) {
+		statements, encoded, signature, result := s.prepareVerificationData(ctx, credentials[i])
+		if result != nil {
+			results[i] = result
+			return
+		}
+
+		prepared, err := verifier.prepare(signatureMessages(newBBSCurve(), statements, encoded, nil), signature)
+		if err != nil {
+			results[i] = &model.VerificationResult{
+				Success: false,
+				Error:   fmt.Errorf("signature verification failed: '%s'", err.Error()),
+			}
+			return
+		}
+		signatures[i] = prepared
+	}, func(i int) {
+		results[i] = cancelledResult(ctx)
+	})
+
+	if ctx.Err() != nil {
+		for i, result := range results {
+			if result == nil {
+				results[i] = cancelledResult(ctx)
+			}
+		}
+
+		return results
+	}
+
+	// 2. Verify all the prepared signatures at once
+	pending := make([]int, 0, len(signatures))
+	batch := make([]*batchSignature, 0, len(signatures))
+	for i, signature := range signatures {
+		if signature != nil {
+			pending = append(pending, i)
+			batch = append(batch, signature)
+		}
+	}
+
+	if verifier.verify(batch) == nil {
+		for _, i := range pending {
+			results[i] = s.policyEngine.apply(credentials[i], s.publicKey)
+		}
+
+		return results
+	}
+
+	// 3. At least one signature is invalid -> verify them one by one
+	forEachConcurrently(ctx, len(pending), options, func(j int) {
+		i := pending[j]
+		if err := verifier.verify([]*batchSignature{signatures[i]}); err != nil {
+			results[i] = &model.VerificationResult{
+				Success: false,
+				Error:   fmt.Errorf("signature verification failed: '%s'", err.Error()),
+			}
+			return
+		}
+		results[i] = s.policyEngine.apply(credentials[i], s.publicKey)
+	}, func(j int) {
+		results[pending[j]] = cancelledResult(ctx)
+	})
+
+	return results
+}
+
+// prepareVerificationData Retrieve the messages and the signature to verify from a signed JSON-LD credential,
+// after checking that the verification method of the proof is controlled by the issuer.
+//
+//	ctx context.Context
+//	credential model.JsonLdCredential
+//
+// returns:
+//
+//	statements [][]byte
+//	encoded []*encodedClaim the encodings of the predicate claims, signed after the statements
+//	signature []byte
+//	result *model.VerificationResult not nil if the credential cannot be verified
+func (s *SignatureSuite2020) prepareVerificationData(
+	ctx context.Context,
+	credential model.JsonLdCredential,
+) ([][]byte, []*encodedClaim, []byte, *model.VerificationResult) {
+	signingData, err := s.provideSigningData(ctx, credential)
+	if err != nil {
+		return nil, nil, nil, &model.VerificationResult{
+			Success: false,
+			Error:   err,
+		}
+	}
+	encoded, err := encodeClaims(s.predicateClaims, signingData)
+	if err != nil {
+		return nil, nil, nil, &model.VerificationResult{
 			Success: false,
 			Error:   err,
 		}
@@ -142,32 +619,61 @@ func (s *SignatureSuite2020) Verify(credential model.JsonLdCredential) *model.Ve
 
 	var signature []byte
 
-	proof := credential[c.CredentialFieldProof].(model.JsonLdProof)
+	proof, ok := credential[c.CredentialFieldProof].(model.JsonLdProof)
+	if !ok {
+		return nil, nil, nil, &model.VerificationResult{
+			Success: false,
+			Error:   fmt.Errorf("provided JSON-LD credential doesn't contain object 'proof'"),
+		}
+	}
+	if err := checkProofCiphersuite(proof, s.scheme); err != nil {
+		return nil, nil, nil, &model.VerificationResult{
+			Success: false,
+			Error:   err,
+		}
+	}
+	if err := s.issuerBinding.check(ctx, credential, proof, s.publicKey); err != nil {
+		return nil, nil, nil, &model.VerificationResult{
+			Success: false,
+			Error:   err,
+		}
+	}
+
 	if proofValue, ok := proof[c.CredentialFieldProofValue].(string); ok {
 		signature, err = base64.StdEncoding.DecodeString(proofValue)
 		if err != nil {
-			return &model.VerificationResult{
+			return nil, nil, nil, &model.VerificationResult{
 				Error: fmt.Errorf("proof value could not be decoded from base64 '%s'", err.Error()),
 			}
 		}
 	} else {
-		return &model.VerificationResult{
+		return nil, nil, nil, &model.VerificationResult{
 			Success: false,
 			Error:   fmt.Errorf("proof doesn't contain field '%s'", c.CredentialFieldProofValue),
 		}
 	}
 
-	err = s.curve.Verify(signingData, signature, s.publicKey)
-	if err != nil {
-		return &model.VerificationResult{
-			Success: false,
-			Error:   fmt.Errorf("signature verification failed: '%s'", err.Error()),
-		}
-	}
+	return signingData, encoded, signature, nil
+}
 
-	return &model.VerificationResult{
-		Success: true,
+// verifyMessages Verify the signature of statements, followed by the encodings of the predicate claims if any.
+//
+//	statements [][]byte
+//	encoded []*encodedClaim
+//	signature []byte
+//
+// returns:
+//
+//	err error
+func (s *SignatureSuite2020) verifyMessages(statements [][]byte, encoded []*encodedClaim, signature []byte) error {
+	if len(encoded) == 0 {
+		return s.scheme.verify(s.publicKey, signature, statements)
+	}
+	if err := requireBBSPlus(s.scheme, "predicate claims"); err != nil {
+		return err
 	}
+
+	return verifySignature(newBBSCurve(), signatureMessages(newBBSCurve(), statements, encoded, nil), signature, s.publicKey)
 }
 
 // createUnsignedProof Generate the skeleton of a JSON-LD proof.
@@ -177,12 +683,15 @@ func (s *SignatureSuite2020) Verify(credential model.JsonLdCredential) *model.Ve
 //	proof *model.CredentialProof
 //	err error
 func (s *SignatureSuite2020) createUnsignedProof() (model.JsonLdProof, error) {
-	// TODO add option to use custom verification method
-	verificationMethod, err := s.keyEncoder.CreateDidKeyVerificationMethod(s.publicKey)
-	if err != nil {
-		return nil, err
+	verificationMethod := s.verificationMethod
+	if verificationMethod == "" {
+		var err error
+		verificationMethod, err = s.keyEncoder.CreateDidKeyVerificationMethod(s.publicKey)
+		if err != nil {
+			return nil, err
+		}
 	}
-	proof := model.CreateDefaultJsonLDProof(verificationMethod, false)
+	proof := model.CreateDefaultJsonLDProofAt(verificationMethod, s.clock(), false)
 
 	return proof, nil
 }
@@ -190,6 +699,7 @@ func (s *SignatureSuite2020) createUnsignedProof() (model.JsonLdProof, error) {
 // prepareDataForSigning Transform a JSON-LD credential and the associated proof to a list of normalized messages
 // that can be signed according to the specifications.
 //
+//	ctx context.Context
 //	credential model.JsonLdCredentialNoProof The JSON-LD credential without the proof.
 //	unsignedProof *model.CredentialProof The JSON-LD proof.
 //
@@ -197,60 +707,248 @@ func (s *SignatureSuite2020) createUnsignedProof() (model.JsonLdProof, error) {
 //
 //	messages [][]byte
 //	err error
-func (s *SignatureSuite2020) prepareDataForSigning(credential model.JsonLdCredentialNoProof, unsignedProof model.JsonLdProof) ([][]byte, error) {
+func (s *SignatureSuite2020) prepareDataForSigning(ctx context.Context, credential model.JsonLdCredentialNoProof, unsignedProof model.JsonLdProof) ([][]byte, error) {
 	// 1. Normalize the JSON-LD unsigned credential
-	credCopy, err := json.Marshal(credential)
-	if err != nil {
-		return nil, err
-	}
-	normalizedCredential, err := s.normalizer.Normalize(string(credCopy))
+	normalizedCredential, err := s.normalizer.NormalizeDocumentContext(ctx, credential)
 	if err != nil {
 		return nil, err
 	}
 
 	// 2. Normalize the JSON-LD proof
-	credCopy, err = json.Marshal(unsignedProof)
+	normalizedProof, err := s.normalizer.NormalizeDocumentContext(ctx, unsignedProof)
 	if err != nil {
 		return nil, err
 	}
 
-	normalizedProof, err := s.normalizer.Normalize(string(credCopy))
+	return combineStatementsForSigning(normalizedProof, normalizedCredential), nil
+}
+
+// combineStatementsForSigning Merge already normalized proof and credential statements into the list of messages to sign.
+//
+//	proofStatements []string The normalized JSON-LD proof.
+//	credentialStatements []string The normalized JSON-LD credential.
+//
+// returns:
+//
+//	messages [][]byte
+func combineStatementsForSigning(proofStatements, credentialStatements []string) [][]byte {
+	// according to specification normalized proof messages come before the normalized credential messages
+	bytesForSigning := make([][]byte, 0, len(proofStatements)+len(credentialStatements))
+	for _, statement := range proofStatements {
+		bytesForSigning = append(bytesForSigning, []byte(statement))
+	}
+	for _, statement := range credentialStatements {
+		bytesForSigning = append(bytesForSigning, []byte(statement))
+	}
+
+	return bytesForSigning
+}
+
+// signCommitted Generate a BBS signature over the statements of a credential followed by messages that are not hashed,
+// e.g. the encodings of the predicate claims, signed as a commitment. The signer of the suite must implement model.BlindSigner.
+//
+//	ctx context.Context
+//	statements [][]byte The messages signed as is.
+//	committed []*bbs.SignatureMessage The messages following the statements.
+//	holderCommitment *ml.G1 nullable, the commitment of the holder to the last message
+//
+// returns:
+//
+//	signature []byte
+//	err error
+func (s *SignatureSuite2020) signCommitted(
+	ctx context.Context,
+	statements [][]byte,
+	committed []*bbs.SignatureMessage,
+	holderCommitment *ml.G1,
+) ([]byte, error) {
+	if s.signer == nil {
+		return nil, model.ErrNoSigner
+	}
+	signer, ok := s.signer.(model.BlindSigner)
+	if !ok {
+		return nil, model.ErrBlindSigningUnsupported
+	}
+
+	messagesCount := len(statements) + len(committed)
+	if holderCommitment != nil {
+		messagesCount++
+	}
+	generators, err := publicKeyGenerators(bbs.NewBBSLib(newBBSCurve()), s.publicKey, messagesCount)
 	if err != nil {
 		return nil, err
 	}
 
-	// according to specification normalized proof messages come before the normalized credential messages
-	stringsForSigning := append(normalizedProof, normalizedCredential...)
-	bytesForSigning := make([][]byte, len(stringsForSigning))
-	for i, s := range stringsForSigning {
-		bytesForSigning[i] = []byte(s)
+	builder := bbs.NewCommitmentBuilder(len(committed) + 1)
+	for _, message := range committed {
+		builder.Add(generators.H[message.Idx], message.FR)
+	}
+	commitment := builder.Build()
+	if holderCommitment != nil {
+		commitment.Add(holderCommitment)
 	}
 
-	return bytesForSigning, nil
+	return signer.SignBlind(ctx, statements, commitment.Compressed(), messagesCount)
 }
 
-// createBLSSignature Generate a BBS signature over an array of messages.
+// verifySignature Verify a BBS signature over messages that may not be hashed.
+//
+//	curve *ml.Curve
+//	messages []*bbs.SignatureMessage
+//	signature []byte
+//	publicKey []byte
 //
+// returns:
+//
+//	err error
+func verifySignature(curve *ml.Curve, messages []*bbs.SignatureMessage, signature, publicKey []byte) error {
+	lib := bbs.NewBBSLib(curve)
+	parsed, err := lib.ParseSignature(signature)
+	if err != nil {
+		return fmt.Errorf("parse signature: %w", err)
+	}
+	generators, err := publicKeyGenerators(lib, publicKey, len(messages))
+	if err != nil {
+		return err
+	}
+
+	return parsed.Verify(messages, generators)
+}
+
+// createBLSSignature Generate a BBS signature over an array of messages with the signer of the suite.
+// The signature of a signer other than an InMemorySigner, e.g. a remote service or a KMS, is verified with the public key
+// of the suite, so that a wrong key or a corrupted signature is not issued.
+//
+//	ctx context.Context
 //	dataForSigning [][]byte The messages to sign.
 //
 // returns:
 //
 //	signature string Base64 encoded string
 //	err error
-func (s *SignatureSuite2020) createBLSSignature(dataForSigning [][]byte) (string, error) {
-	signatureBytes, err := s.curve.Sign(dataForSigning, s.privateKey)
+func (s *SignatureSuite2020) createBLSSignature(ctx context.Context, dataForSigning [][]byte) (string, error) {
+	if s.signer == nil {
+		return "", model.ErrNoSigner
+	}
+
+	signatureBytes, err := s.signer.Sign(ctx, dataForSigning)
 	if err != nil {
-		return "", nil
+		return "", err
+	}
+	if _, ok := s.signer.(*InMemorySigner); !ok {
+		if err := s.scheme.verify(s.publicKey, signatureBytes, dataForSigning); err != nil {
+			return "", fmt.Errorf("%w: %v", model.ErrInvalidSignerSignature, err)
+		}
 	}
 
 	return base64.StdEncoding.EncodeToString(signatureBytes), nil
 }
 
-// addCredentialIssuerIfEmpty Add the field "issuer" to a JSON-LD credential to sign if empty.
+// prepareCredentialForSigning Complete a copy of a JSON-LD credential to sign, create its proof and the messages to sign.
+//
+//	ctx context.Context
+//	credential model.JsonLdCredentialNoProof The JSON-LD credential to be signed.
+//
+// returns:
+//
+//	credential model.JsonLdCredentialNoProof
+//	proof model.JsonLdProof
+//	messages [][]byte
+//	err error
+func (s *SignatureSuite2020) prepareCredentialForSigning(
+	ctx context.Context,
+	credential model.JsonLdCredentialNoProof,
+) (model.JsonLdCredentialNoProof, model.JsonLdProof, [][]byte, error) {
+	credCopy := deepCopyMap(credential)
+	s.addCredentialIssuerIfEmpty(credCopy)
+
+	proof, err := s.createUnsignedProof()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	dataForSigning, err := s.prepareDataForSigning(ctx, credCopy, proof)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return credCopy, proof, dataForSigning, nil
+}
+
+// attachProof Add the signed proof to a credential.
+//
+//	credential model.JsonLdCredentialNoProof
+//	proof model.JsonLdProof
+//	signature string Base64 encoded signature.
+//
+// returns:
+//
+//	signedCredential model.JsonLdCredential
+//	jsonCredential string JSON representation of the credential
+//	err error
+func attachProof(credential model.JsonLdCredentialNoProof, proof model.JsonLdProof, signature string) (model.JsonLdCredential, string, error) {
+	// TODO: support the possibility to add the new proof to the list of existing proofs -> support array of proofs
+	model.DeleteContextFromJsonLdProof(proof) // Delete context since it is not needed for representation -> compact proof format
+	proof[c.CredentialFieldProofValue] = signature
+	credential[c.CredentialFieldProof] = proof
+
+	jsonLdDoc, err := json.Marshal(credential)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return credential, string(jsonLdDoc), nil
+}
+
+// predicateClaimsOption Retrieve the IRIs of the predicate claims from the suite options, if any.
+func predicateClaimsOption(options *model.SignatureSuiteOptions) []string {
+	if options == nil {
+		return nil
+	}
+
+	return options.PredicateClaims
+}
+
+// clockOption Retrieve the clock from the suite options, time.Now if none.
+func clockOption(options *model.SignatureSuiteOptions) func() time.Time {
+	if options == nil || options.Clock == nil {
+		return time.Now
+	}
+
+	return options.Clock
+}
+
+// randOption Retrieve the source of randomness from the suite options, crypto/rand.Reader if none.
+// A custom source is wrapped so that the concurrent operations of the suite read it in turn.
+func randOption(options *model.SignatureSuiteOptions) io.Reader {
+	if options == nil || options.Rand == nil {
+		return rand.Reader
+	}
+
+	return &lockedReader{reader: options.Rand}
+}
+
+// verificationMethodOption Retrieve the custom verification method from the suite options, if any.
+func verificationMethodOption(options *model.SignatureSuiteOptions) string {
+	if options == nil {
+		return ""
+	}
+
+	return options.VerificationMethod
+}
+
+// addCredentialIssuerIfEmpty Add the field "issuer" to a JSON-LD credential to sign if empty:
+// the DID of the custom verification method if any, the did:key of the public key otherwise.
 //
 //	credential model.JsonLdCredentialNoProof
 func (s *SignatureSuite2020) addCredentialIssuerIfEmpty(credential model.JsonLdCredentialNoProof) {
-	if _, ok := credential[c.CredentialFieldIssuer]; !ok {
+	if _, ok := credential[c.CredentialFieldIssuer]; ok {
+		return
+	}
+
+	if s.verificationMethod != "" {
+		credential[c.CredentialFieldIssuer], _, _ = strings.Cut(s.verificationMethod, "#")
+	} else {
 		credential[c.CredentialFieldIssuer], _ = s.keyEncoder.CreateDidKey(s.publicKey)
 	}
 }
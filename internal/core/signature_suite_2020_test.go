@@ -1,11 +1,15 @@
 package core_test
 
 import (
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
 	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
@@ -148,3 +152,304 @@ func (s *SignatureSuite2020TestSuite) TestProvisionOfVerificationData() {
 
 	s.Equal(expectedCredential, credential)
 }
+
+func (s *SignatureSuite2020TestSuite) TestConcurrentSignatureCreationAndVerification() {
+	blsPublicKeyHex := "98ae11750ca7abb2e8ad1a9e55bc7226c5475a961f2bf4867285be12a519f6ddb5671f999ffd0f3ee6a6b2ea16f6cfa90086c14307bfc4e8e07d9c703603177e96874d8cba268d6d01a34cd8b418a4ffcc3ce5376b339d049cadeba06f959399"
+	blsPrivateKeyHex := "13e86bd1a774b4609108a920c2886394e76c8db8502fbc380d1a21f8be835cef"
+	publicKey, _ := hex.DecodeString(blsPublicKeyHex)
+	privateKey, _ := hex.DecodeString(blsPrivateKeyHex)
+	subject := core.NewSignatureSuite2020(publicKey, privateKey, s.options)
+
+	// retrieve unsigned credential
+	var docToSign model.JsonLdCredentialNoProof
+	unsignedCredentialBytes, err := os.ReadFile("testdata/unsignedCredential.json")
+	s.NoError(err)
+	err = json.Unmarshal(unsignedCredentialBytes, &docToSign)
+	s.NoError(err)
+
+	// sign and verify with the same suite from multiple goroutines
+	var wg sync.WaitGroup
+	results := make([]*model.VerificationResult, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			signedCredential, _, err := subject.Sign(docToSign)
+			if err != nil {
+				results[i] = &model.VerificationResult{Error: err}
+				return
+			}
+			results[i] = subject.Verify(signedCredential)
+		}(i)
+	}
+	wg.Wait()
+
+	// check
+	expectedResult := &model.VerificationResult{
+		Success: true,
+	}
+	for _, actualResult := range results {
+		s.Equal(expectedResult, actualResult)
+	}
+}
+
+func (s *SignatureSuite2020TestSuite) TestBatchSignatureCreationAndVerification() {
+	blsPublicKeyHex := "98ae11750ca7abb2e8ad1a9e55bc7226c5475a961f2bf4867285be12a519f6ddb5671f999ffd0f3ee6a6b2ea16f6cfa90086c14307bfc4e8e07d9c703603177e96874d8cba268d6d01a34cd8b418a4ffcc3ce5376b339d049cadeba06f959399"
+	blsPrivateKeyHex := "13e86bd1a774b4609108a920c2886394e76c8db8502fbc380d1a21f8be835cef"
+	publicKey, _ := hex.DecodeString(blsPublicKeyHex)
+	privateKey, _ := hex.DecodeString(blsPrivateKeyHex)
+	subject := core.NewSignatureSuite2020(publicKey, privateKey, s.options)
+	options := &model.BatchOptions{Concurrency: 2}
+
+	// retrieve unsigned credentials
+	credentials := make([]model.JsonLdCredentialNoProof, 5)
+	unsignedCredentialBytes, err := os.ReadFile("testdata/unsignedCredential.json")
+	s.NoError(err)
+	for i := range credentials {
+		err = json.Unmarshal(unsignedCredentialBytes, &credentials[i])
+		s.NoError(err)
+		credentials[i]["name"] = fmt.Sprintf("Permanent Resident Card %d", i)
+	}
+
+	// sign credentials
+	signResults := subject.SignBatch(context.Background(), credentials, options)
+	s.Len(signResults, len(credentials))
+	signedCredentials := make([]model.JsonLdCredential, len(signResults))
+	for i, result := range signResults {
+		s.NoError(result.Error)
+		s.Equal(credentials[i]["name"], result.Credential["name"])
+		signedCredentials[i] = result.Credential
+	}
+
+	// verify signatures
+	expectedResult := &model.VerificationResult{
+		Success: true,
+	}
+	for _, actualResult := range subject.VerifyBatch(context.Background(), signedCredentials, options) {
+		s.Equal(expectedResult, actualResult)
+	}
+
+	// tamper one credential -> only that credential fails
+	signedCredentials[3]["name"] = "Tampered"
+	actualResults := subject.VerifyBatch(context.Background(), signedCredentials, options)
+	for i, actualResult := range actualResults {
+		if i == 3 {
+			s.Equal(&model.VerificationResult{
+				Success: false,
+				Error:   fmt.Errorf("signature verification failed: 'invalid BLS12-381 signature'"),
+			}, actualResult)
+		} else {
+			s.Equal(expectedResult, actualResult)
+		}
+	}
+}
+
+func (s *SignatureSuite2020TestSuite) TestBatchVerificationCancelled() {
+	blsPublicKeyHex := "98ae11750ca7abb2e8ad1a9e55bc7226c5475a961f2bf4867285be12a519f6ddb5671f999ffd0f3ee6a6b2ea16f6cfa90086c14307bfc4e8e07d9c703603177e96874d8cba268d6d01a34cd8b418a4ffcc3ce5376b339d049cadeba06f959399"
+	publicKey, _ := hex.DecodeString(blsPublicKeyHex)
+	subject := core.NewSignatureSuite2020(publicKey, nil, s.options)
+
+	// retrieve signed credential
+	var signedCredential model.JsonLdCredential
+	signedCredentialBytes, err := os.ReadFile("testdata/signedCredential.json")
+	s.NoError(err)
+	err = json.Unmarshal(signedCredentialBytes, &signedCredential)
+	s.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// check
+	actualResults := subject.VerifyBatch(ctx, []model.JsonLdCredential{signedCredential, signedCredential}, nil)
+	s.Len(actualResults, 2)
+	for _, actualResult := range actualResults {
+		s.False(actualResult.Success)
+		s.ErrorIs(actualResult.Error, context.Canceled)
+	}
+}
+
+func (s *SignatureSuite2020TestSuite) TestBatchSigningCancelledMidway() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signer := &cancellingSigner{
+		Signer: core.NewInMemorySigner(benchmarkPublicKey(s.T()), benchmarkPrivateKey(s.T())),
+		cancel: cancel,
+	}
+	subject, err := core.NewSignatureSuite2020WithSigner(ctx, signer, s.options)
+	s.Require().NoError(err)
+
+	credentials := make([]model.JsonLdCredentialNoProof, 5)
+	for i := range credentials {
+		credentials[i] = benchmarkCredential(s.T(), 3)
+	}
+
+	// the context is done while signing the first credential -> the other ones are not signed
+	actualResults := subject.SignBatch(ctx, credentials, &model.BatchOptions{Concurrency: 1})
+	s.Len(actualResults, 5)
+	s.Equal(int32(1), signer.calls.Load())
+	for _, actualResult := range actualResults[1:] {
+		s.Nil(actualResult.Credential)
+		s.ErrorIs(actualResult.Error, context.Canceled)
+		s.ErrorContains(actualResult.Error, "batch processing interrupted")
+	}
+}
+
+// A cancellingSigner cancels a context once it has signed.
+type cancellingSigner struct {
+	model.Signer
+	cancel context.CancelFunc
+	calls  atomic.Int32
+}
+
+func (s *cancellingSigner) Sign(ctx context.Context, messages [][]byte) ([]byte, error) {
+	s.calls.Add(1)
+	defer s.cancel()
+
+	return s.Signer.Sign(ctx, messages)
+}
+
+func (s *SignatureSuite2020TestSuite) TestVerificationCancelled() {
+	blsPublicKeyHex := "98ae11750ca7abb2e8ad1a9e55bc7226c5475a961f2bf4867285be12a519f6ddb5671f999ffd0f3ee6a6b2ea16f6cfa90086c14307bfc4e8e07d9c703603177e96874d8cba268d6d01a34cd8b418a4ffcc3ce5376b339d049cadeba06f959399"
+	publicKey, _ := hex.DecodeString(blsPublicKeyHex)
+	subject := core.NewSignatureSuite2020(publicKey, nil, s.options)
+
+	// retrieve signed credential
+	var signedCredential model.JsonLdCredential
+	signedCredentialBytes, err := os.ReadFile("testdata/signedCredential.json")
+	s.NoError(err)
+	err = json.Unmarshal(signedCredentialBytes, &signedCredential)
+	s.NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+
+	// check
+	actualResult := subject.VerifyContext(ctx, signedCredential)
+	s.False(actualResult.Success)
+	s.ErrorIs(actualResult.Error, context.DeadlineExceeded)
+}
+
+func BenchmarkSign(b *testing.B) {
+	for _, claims := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("claims=%d", claims), func(b *testing.B) {
+			subject := core.NewSignatureSuite2020(benchmarkPublicKey(b), benchmarkPrivateKey(b), benchmarkOptions(b))
+			credential := benchmarkCredential(b, claims)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := subject.Sign(credential); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	for _, claims := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("claims=%d", claims), func(b *testing.B) {
+			subject := core.NewSignatureSuite2020(benchmarkPublicKey(b), benchmarkPrivateKey(b), benchmarkOptions(b))
+			signedCredential, _, err := subject.Sign(benchmarkCredential(b, claims))
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if result := subject.Verify(signedCredential); !result.Success {
+					b.Fatal(result.Error)
+				}
+			}
+		})
+	}
+}
+
+// benchmarkCredential Load the unsigned test credential and add the requested number of claims to its subject.
+func benchmarkCredential(b testing.TB, claims int) model.JsonLdCredentialNoProof {
+	var credential model.JsonLdCredentialNoProof
+	unsignedCredentialBytes, err := os.ReadFile("testdata/unsignedCredential.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err = json.Unmarshal(unsignedCredentialBytes, &credential); err != nil {
+		b.Fatal(err)
+	}
+
+	subject := credential[c.CredentialFieldCredentialSubject].(map[string]interface{})
+	for i := 0; i < claims; i++ {
+		subject[fmt.Sprintf("https://example.com/claims#claim%d", i)] = fmt.Sprintf("value %d", i)
+	}
+
+	return credential
+}
+
+func benchmarkOptions(b testing.TB) *model.SignatureSuiteOptions {
+	var contextResidentCardV1 map[string]interface{}
+	customResidentCardContextBytes, err := os.ReadFile("testdata/customResidentCardContext.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err = json.Unmarshal(customResidentCardContextBytes, &contextResidentCardV1); err != nil {
+		b.Fatal(err)
+	}
+
+	return &model.SignatureSuiteOptions{
+		Contexts: map[string]map[string]interface{}{
+			"https://w3id.org/citizenship/v1": contextResidentCardV1,
+		},
+	}
+}
+
+func benchmarkPublicKey(b testing.TB) []byte {
+	publicKey, err := hex.DecodeString("98ae11750ca7abb2e8ad1a9e55bc7226c5475a961f2bf4867285be12a519f6ddb5671f999ffd0f3ee6a6b2ea16f6cfa90086c14307bfc4e8e07d9c703603177e96874d8cba268d6d01a34cd8b418a4ffcc3ce5376b339d049cadeba06f959399")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return publicKey
+}
+
+func benchmarkPrivateKey(b testing.TB) []byte {
+	privateKey, err := hex.DecodeString("13e86bd1a774b4609108a920c2886394e76c8db8502fbc380d1a21f8be835cef")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return privateKey
+}
+
+func BenchmarkVerifyBatch(b *testing.B) {
+	subject := core.NewSignatureSuite2020(benchmarkPublicKey(b), benchmarkPrivateKey(b), benchmarkOptions(b))
+
+	credentials := make([]model.JsonLdCredentialNoProof, 100)
+	for i := range credentials {
+		credentials[i] = benchmarkCredential(b, 10)
+	}
+	signedCredentials := make([]model.JsonLdCredential, len(credentials))
+	for i, result := range subject.SignBatch(context.Background(), credentials, nil) {
+		if result.Error != nil {
+			b.Fatal(result.Error)
+		}
+		signedCredentials[i] = result.Credential
+	}
+
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, credential := range signedCredentials {
+				if result := subject.Verify(credential); !result.Success {
+					b.Fatal(result.Error)
+				}
+			}
+		}
+	})
+
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, result := range subject.VerifyBatch(context.Background(), signedCredentials, nil) {
+				if !result.Success {
+					b.Fatal(result.Error)
+				}
+			}
+		}
+	})
+}
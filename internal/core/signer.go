@@ -0,0 +1,204 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	ml "github.com/IBM/mathlib"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/hyperledger/aries-bbs-go/bbs"
+)
+
+// An InMemorySigner signs with a BBS+ private key held in memory.
+// An InMemorySigner is safe for concurrent use.
+type InMemorySigner struct {
+	mu         sync.RWMutex
+	publicKey  []byte
+	privateKey []byte          // nil once destroyed
+	scheme     signatureScheme // the scheme of the signatures, drawing their random factors from its source
+}
+
+// NewInMemorySigner initializes and returns InMemorySigner.
+//
+//	publicKey []byte The BBS+ public key.
+//	privateKey []byte The BBS+ private key. The signer keeps its own copy, cleared by Destroy.
+func NewInMemorySigner(publicKey, privateKey []byte) *InMemorySigner {
+	return newInMemorySigner(publicKey, privateKey, &bbsPlusScheme{rand: rand.Reader})
+}
+
+// newInMemorySigner Initialize an InMemorySigner signing with a chosen scheme.
+func newInMemorySigner(publicKey, privateKey []byte, scheme signatureScheme) *InMemorySigner {
+	privateKey = append([]byte(nil), privateKey...)
+
+	return &InMemorySigner{
+		publicKey:  publicKey,
+		privateKey: privateKey,
+		scheme:     scheme,
+	}
+}
+
+// Sign Create a BBS+ signature over a vector of messages.
+//
+//	ctx context.Context
+//	messages [][]byte The messages to sign.
+//
+// returns:
+//
+//	signature []byte
+//	err error
+func (s *InMemorySigner) Sign(ctx context.Context, messages [][]byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.privateKey == nil {
+		return nil, errors.New("signer has been destroyed")
+	}
+
+	if len(messages) == 0 {
+		return nil, errors.New("create BBS+ signature: messages are not defined")
+	}
+
+	return s.scheme.sign(s.privateKey, s.publicKey, messages)
+}
+
+// SignBlind Create a BBS+ signature over messagesCount messages: the known messages first,
+// then the messages committed by the holder, that the signer does not learn.
+//
+//	ctx context.Context
+//	messages [][]byte The known messages to sign.
+//	commitment []byte The compressed commitment of the holder to the remaining messages.
+//	messagesCount int The total number of messages.
+//
+// returns:
+//
+//	signature []byte the blinded signature, to be unblinded by the holder
+//	err error
+func (s *InMemorySigner) SignBlind(ctx context.Context, messages [][]byte, commitment []byte, messagesCount int) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(messages) >= messagesCount {
+		return nil, fmt.Errorf("%w: %d messages to sign, %d expected", model.ErrInvalidHolderBindingRequest, len(messages), messagesCount)
+	}
+	if err := requireBBSPlus(s.scheme, "blind signatures"); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.privateKey == nil {
+		return nil, errors.New("signer has been destroyed")
+	}
+
+	curve := newBBSCurve()
+	privateKey, err := bbs.NewBBSLib(curve).UnmarshalPrivateKey(s.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+	generators, err := privateKey.PublicKey().ToPublicKeyWithGenerators(messagesCount)
+	if err != nil {
+		return nil, fmt.Errorf("build generators from public key: %w", err)
+	}
+	committed, err := curve.NewG1FromCompressed(commitment)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrInvalidHolderBindingRequest, err.Error())
+	}
+
+	// b = g1 * h_0^m_0 * ... * h_k^m_k * commitment
+	builder := bbs.NewCommitmentBuilder(len(messages) + 2)
+	builder.Add(curve.GenG1, curve.NewZrFromInt(1))
+	for _, message := range bbs.MessagesToFr(messages, curve) {
+		builder.Add(generators.H[message.Idx], message.FR)
+	}
+	builder.Add(committed, curve.NewZrFromInt(1))
+
+	return signCommitment(curve, s.scheme.(*bbsPlusScheme).rand, privateKey, generators, builder.Build())
+}
+
+// signCommitment Create a BBS+ signature over messages committed in b, as bbs.BBSG2Pub.SignWithKeyB.
+//
+//	curve *ml.Curve
+//	rng io.Reader The source of the random factors e and s.
+//	privateKey *bbs.PrivateKey
+//	generators *bbs.PublicKeyWithGenerators The generators of the public key for the number of messages.
+//	b *ml.G1 The commitment to the messages, g1 * h_i^m_i.
+//
+// returns:
+//
+//	signature []byte A, e and s, compressed
+//	err error if the source of randomness fails
+func signCommitment(curve *ml.Curve, rng io.Reader, privateKey *bbs.PrivateKey, generators *bbs.PublicKeyWithGenerators, b *ml.G1) ([]byte, error) {
+	e, err := randomZr(curve, rng)
+	if err != nil {
+		return nil, err
+	}
+	sigS, err := randomZr(curve, rng)
+	if err != nil {
+		return nil, err
+	}
+
+	// A = (b * h0^s) ^ (1 / (x + e))
+	exponent := privateKey.FR.Copy()
+	exponent = exponent.Plus(e)
+	exponent.InvModP(curve.GroupOrder)
+	b = b.Copy()
+	b.Add(generators.H0.Mul(sigS))
+	a := b.Mul(exponent)
+
+	signature := make([]byte, curve.CompressedG1ByteSize+2*curve.ScalarByteSize)
+	copy(signature, a.Compressed())
+	copy(signature[curve.CompressedG1ByteSize:], e.Bytes())
+	copy(signature[curve.CompressedG1ByteSize+curve.ScalarByteSize:], sigS.Bytes())
+
+	return signature, nil
+}
+
+// PublicKey Return the BBS+ public key matching the private key.
+//
+//	ctx context.Context
+//
+// returns:
+//
+//	publicKey []byte
+//	err error
+func (s *InMemorySigner) PublicKey(ctx context.Context) ([]byte, error) {
+	return s.publicKey, nil
+}
+
+// Destroy Clear the private key held by the signer. The signer cannot sign anymore.
+func (s *InMemorySigner) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ZeroBytes(s.privateKey)
+	s.privateKey = nil
+}
+
+// NewInMemorySignerFromKeystore Decrypt the private key of a keystore into a new InMemorySigner.
+// The decrypted key is only held by the signer, and cleared by Destroy.
+//
+//	keystore *model.Keystore
+//	passphrase []byte
+//
+// returns:
+//
+//	signer *InMemorySigner
+//	err error
+func NewInMemorySignerFromKeystore(keystore *model.Keystore, passphrase []byte) (*InMemorySigner, error) {
+	publicKey, privateKey, err := DecryptKey(keystore, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer ZeroBytes(privateKey)
+
+	return NewInMemorySigner(publicKey, privateKey), nil
+}
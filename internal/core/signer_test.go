@@ -0,0 +1,130 @@
+package core_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ml "github.com/IBM/mathlib"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/hyperledger/aries-bbs-go/bbs"
+	"github.com/stretchr/testify/suite"
+)
+
+type SignerTestSuite struct {
+	suite.Suite
+	publicKey  []byte
+	privateKey []byte
+	options    *model.SignatureSuiteOptions
+}
+
+func TestSignerTestSuite(t *testing.T) {
+	suite.Run(t, new(SignerTestSuite))
+}
+
+func (s *SignerTestSuite) SetupTest() {
+	s.publicKey, _ = hex.DecodeString("98ae11750ca7abb2e8ad1a9e55bc7226c5475a961f2bf4867285be12a519f6ddb5671f999ffd0f3ee6a6b2ea16f6cfa90086c14307bfc4e8e07d9c703603177e96874d8cba268d6d01a34cd8b418a4ffcc3ce5376b339d049cadeba06f959399")
+	s.privateKey, _ = hex.DecodeString("13e86bd1a774b4609108a920c2886394e76c8db8502fbc380d1a21f8be835cef")
+	s.options = benchmarkOptions(s.T())
+}
+
+func (s *SignerTestSuite) TestSignWithRemoteSigner() {
+	server := httptest.NewServer(core.NewSignerHandler(core.NewInMemorySigner(s.publicKey, s.privateKey)))
+	defer server.Close()
+
+	// the issuer only knows the URL of the signing service
+	subject, err := core.NewSignatureSuite2020WithSigner(context.Background(), core.NewHTTPSigner(server.URL, nil), s.options)
+	s.NoError(err)
+
+	signedCredential, _, err := subject.Sign(benchmarkCredential(s.T(), 3))
+	s.NoError(err)
+
+	// check
+	verifier := core.NewSignatureSuite2020(s.publicKey, nil, s.options)
+	actualResult := verifier.Verify(signedCredential)
+	expectedResult := &model.VerificationResult{
+		Success: true,
+	}
+	s.Equal(expectedResult, actualResult)
+}
+
+func (s *SignerTestSuite) TestRemoteSignerError() {
+	server := httptest.NewServer(core.NewSignerHandler(failingSigner{publicKey: s.publicKey}))
+	defer server.Close()
+
+	subject, err := core.NewSignatureSuite2020WithSigner(context.Background(), core.NewHTTPSigner(server.URL, nil), s.options)
+	s.NoError(err)
+
+	_, _, err = subject.Sign(benchmarkCredential(s.T(), 3))
+	s.EqualError(err, "remote signer: key is disabled")
+}
+
+func (s *SignerTestSuite) TestRemoteSignerWrongKey() {
+	otherPublicKey, otherPrivateKey, err := bbs.NewBBSLib(ml.Curves[ml.BLS12_381_BBS]).GenerateKeyPair(sha256.New, nil)
+	s.Require().NoError(err)
+	otherPublicKeyBytes, err := otherPublicKey.Marshal()
+	s.Require().NoError(err)
+	otherPrivateKeyBytes, err := otherPrivateKey.Marshal()
+	s.Require().NoError(err)
+	server := httptest.NewServer(core.NewSignerHandler(mismatchedSigner{
+		publicKey: s.publicKey,
+		signer:    core.NewInMemorySigner(otherPublicKeyBytes, otherPrivateKeyBytes),
+	}))
+	defer server.Close()
+
+	subject, err := core.NewSignatureSuite2020WithSigner(context.Background(), core.NewHTTPSigner(server.URL, nil), s.options)
+	s.Require().NoError(err)
+
+	_, _, err = subject.Sign(benchmarkCredential(s.T(), 3))
+	s.ErrorIs(err, model.ErrInvalidSignerSignature)
+}
+
+func (s *SignerTestSuite) TestRemoteSignerResponseTooLarge() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"publicKey":"` + strings.Repeat("A", 2<<20) + `"}`))
+	}))
+	defer server.Close()
+
+	_, err := core.NewHTTPSigner(server.URL, nil).PublicKey(context.Background())
+	s.ErrorContains(err, "remote signer: invalid response")
+}
+
+func (s *SignerTestSuite) TestSignWithoutSigner() {
+	subject := core.NewSignatureSuite2020(s.publicKey, nil, s.options)
+
+	_, _, err := subject.Sign(benchmarkCredential(s.T(), 3))
+	s.ErrorIs(err, model.ErrNoSigner)
+}
+
+// A failingSigner reports a public key but refuses to sign, like a disabled KMS key.
+type failingSigner struct {
+	publicKey []byte
+}
+
+func (s failingSigner) Sign(ctx context.Context, messages [][]byte) ([]byte, error) {
+	return nil, errors.New("key is disabled")
+}
+
+func (s failingSigner) PublicKey(ctx context.Context) ([]byte, error) {
+	return s.publicKey, nil
+}
+
+// A mismatchedSigner reports a public key but signs with another key, like a KMS key misconfigured in the issuer.
+type mismatchedSigner struct {
+	publicKey []byte
+	signer    model.Signer
+}
+
+func (s mismatchedSigner) Sign(ctx context.Context, messages [][]byte) ([]byte, error) {
+	return s.signer.Sign(ctx, messages)
+}
+
+func (s mismatchedSigner) PublicKey(ctx context.Context) ([]byte, error) {
+	return s.publicKey, nil
+}
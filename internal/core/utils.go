@@ -2,17 +2,63 @@ package core
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	ml "github.com/IBM/mathlib"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/hyperledger/aries-bbs-go/bbs"
 )
 
+// deepCopyMap Copy a JSON document without serializing it.
+// The copy only contains the types produced by encoding/json, as expected by the JSON-LD processor.
 func deepCopyMap(m map[string]interface{}) map[string]interface{} {
-	raw, _ := json.Marshal(m)
-
-	var copy map[string]interface{}
-	_ = json.Unmarshal(raw, &copy)
+	copy, _ := deepCopyValue(m).(map[string]interface{})
 
 	return copy
 }
 
+// deepCopyValue Copy a JSON value, converting the Go types not produced by encoding/json.
+func deepCopyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, string, bool, float64:
+		return v
+	case map[string]interface{}:
+		copy := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copy[key] = deepCopyValue(item)
+		}
+
+		return copy
+	case []interface{}:
+		copy := make([]interface{}, len(v))
+		for i, item := range v {
+			copy[i] = deepCopyValue(item)
+		}
+
+		return copy
+	case []string:
+		copy := make([]interface{}, len(v))
+		for i, item := range v {
+			copy[i] = item
+		}
+
+		return copy
+	default:
+		// fallback for any other type (numbers, structs...) -> JSON round-trip
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil
+		}
+
+		var copy interface{}
+		_ = json.Unmarshal(raw, &copy)
+
+		return copy
+	}
+}
+
 func decodeOrPanic(doc string) map[string]interface{} {
 	var jsonRaw map[string]interface{}
 
@@ -23,3 +69,67 @@ func decodeOrPanic(doc string) map[string]interface{} {
 
 	return jsonRaw
 }
+
+// newBBSScheme Create a BBS+ signature scheme backed by a private copy of the BLS12-381 curve generators.
+// The pairing engine normalizes the generators in place, so sharing the global ml.Curves between goroutines is racy.
+func newBBSScheme() *bbs.BBSG2Pub {
+	return bbs.New(newBBSCurve())
+}
+
+// newBBSCurve Create a private copy of the BLS12-381 curve used by the BBS+ signature scheme.
+func newBBSCurve() *ml.Curve {
+	curve := *ml.Curves[ml.BLS12_381_BBS]
+	curve.GenG1 = curve.GenG1.Copy()
+	curve.GenG2 = curve.GenG2.Copy()
+
+	return &curve
+}
+
+// randomZr Draw a random scalar. Unlike ml.Curve.NewRandomZr, a failing source is reported instead of panicking.
+// Twice the size of a scalar is read and reduced modulo the group order, so that the bias is negligible.
+//
+//	curve *ml.Curve
+//	rng io.Reader
+//
+// returns:
+//
+//	scalar *ml.Zr
+//	err error wrapping model.ErrRandomnessUnavailable
+func randomZr(curve *ml.Curve, rng io.Reader) (*ml.Zr, error) {
+	buffer := make([]byte, 2*curve.ScalarByteSize)
+	if _, err := io.ReadFull(rng, buffer); err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrRandomnessUnavailable, err.Error())
+	}
+	scalar := curve.NewZrFromBytes(buffer)
+	scalar.Mod(curve.GroupOrder)
+
+	return scalar, nil
+}
+
+// randomZrs Draw count random scalars, as randomZr.
+func randomZrs(curve *ml.Curve, rng io.Reader, count int) ([]*ml.Zr, error) {
+	scalars := make([]*ml.Zr, count)
+	for i := range scalars {
+		scalar, err := randomZr(curve, rng)
+		if err != nil {
+			return nil, err
+		}
+		scalars[i] = scalar
+	}
+
+	return scalars, nil
+}
+
+// A lockedReader serializes the reads of a source of randomness that is not safe for concurrent use.
+type lockedReader struct {
+	mu     sync.Mutex
+	reader io.Reader
+}
+
+// Read Read from the source, once the concurrent reads are done.
+func (r *lockedReader) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.reader.Read(p)
+}
@@ -0,0 +1,141 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+)
+
+// The typed methods convert the credentials to their map representation and back, the unknown properties
+// being preserved, so that the typed and the map forms can be used interchangeably.
+
+// SignCredential Sign a typed credential with a BbsBlsSignature2020 signature.
+//
+//	ctx context.Context
+//	credential *model.VerifiableCredential The credential to sign. If the issuer is not specified, it is added based on the "did:key" method.
+//
+// returns:
+//
+//	signedCredential *model.VerifiableCredential
+//	err error wrapping model.ErrInvalidCredential if the signed credential is not valid
+func (s *SignatureSuite2020) SignCredential(ctx context.Context, credential *model.VerifiableCredential) (*model.VerifiableCredential, error) {
+	if credential == nil {
+		return nil, fmt.Errorf("%w: empty document", model.ErrInvalidCredential)
+	}
+	unsigned, err := credential.ToMap()
+	if err != nil {
+		return nil, err
+	}
+
+	signed, _, err := s.SignContext(ctx, unsigned)
+	if err != nil {
+		return nil, err
+	}
+
+	return validCredentialFromMap(signed)
+}
+
+// VerifyCredential Validate and verify a typed signed credential.
+//
+//	ctx context.Context
+//	credential *model.VerifiableCredential
+//
+// returns:
+//
+//	result *model.VerificationResult
+func (s *SignatureSuite2020) VerifyCredential(ctx context.Context, credential *model.VerifiableCredential) *model.VerificationResult {
+	signed, result := validCredentialToMap(credential, credential.Validate)
+	if result != nil {
+		return result
+	}
+
+	return s.VerifyContext(ctx, signed)
+}
+
+// DeriveProofCredential Derive a proof from a typed signed credential.
+//
+//	ctx context.Context
+//	signedCredential *model.VerifiableCredential
+//	frameDocument model.JsonLdFrame The frame document.
+//	nonceBytes []byte The challenge of the verifier.
+//	options *model.DeriveProofOptions nullable
+//
+// returns:
+//
+//	derivedCredential *model.VerifiableCredential
+//	err error
+func (s *SignatureProofSuite2020) DeriveProofCredential(
+	ctx context.Context,
+	signedCredential *model.VerifiableCredential,
+	frameDocument model.JsonLdFrame,
+	nonceBytes []byte,
+	options *model.DeriveProofOptions,
+) (*model.VerifiableCredential, error) {
+	signed, result := validCredentialToMap(signedCredential, signedCredential.Validate)
+	if result != nil {
+		return nil, result.Error
+	}
+
+	derived, err := s.DeriveProofWithOptions(ctx, signed, frameDocument, nonceBytes, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.VerifiableCredentialFromMap(derived)
+}
+
+// VerifyProofCredential Validate a typed framed credential and verify its derived proofs.
+//
+//	ctx context.Context
+//	credential *model.VerifiableCredential The framed credential together with its derived proofs.
+//	options *model.VerifyProofOptions nullable
+//
+// returns:
+//
+//	result *model.VerificationResult
+func (s *SignatureProofSuite2020) VerifyProofCredential(
+	ctx context.Context,
+	credential *model.VerifiableCredential,
+	options *model.VerifyProofOptions,
+) *model.VerificationResult {
+	derived, result := validCredentialToMap(credential, credential.ValidateDerived)
+	if result != nil {
+		return result
+	}
+
+	return s.VerifyProofContext(ctx, derived, options)
+}
+
+// validCredentialToMap Validate a typed credential, signed or derived, and convert it to its map representation.
+func validCredentialToMap(credential *model.VerifiableCredential, validate func() error) (model.JsonLdCredential, *model.VerificationResult) {
+	if err := validate(); err != nil {
+		return nil, &model.VerificationResult{
+			Success: false,
+			Error:   err,
+		}
+	}
+
+	m, err := credential.ToMap()
+	if err != nil {
+		return nil, &model.VerificationResult{
+			Success: false,
+			Error:   err,
+		}
+	}
+
+	return m, nil
+}
+
+// validCredentialFromMap Convert a credential to its typed representation and validate it.
+func validCredentialFromMap(credential model.JsonLdCredential) (*model.VerifiableCredential, error) {
+	typed, err := model.VerifiableCredentialFromMap(credential)
+	if err != nil {
+		return nil, err
+	}
+	if err := typed.Validate(); err != nil {
+		return nil, err
+	}
+
+	return typed, nil
+}
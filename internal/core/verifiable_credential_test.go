@@ -0,0 +1,183 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type VerifiableCredentialTestSuite struct {
+	suite.Suite
+	signatureSuite *core.SignatureSuite2020
+	proofSuite     *core.SignatureProofSuite2020
+	credentialJSON []byte
+	frame          model.JsonLdFrame
+}
+
+func TestVerifiableCredentialTestSuite(t *testing.T) {
+	suite.Run(t, new(VerifiableCredentialTestSuite))
+}
+
+func (s *VerifiableCredentialTestSuite) SetupTest() {
+	options := offlineOptions(s.T())
+	publicKey := benchmarkPublicKey(s.T())
+	s.signatureSuite = core.NewSignatureSuite2020(publicKey, benchmarkPrivateKey(s.T()), options)
+	s.proofSuite = core.NewSignatureProofSuite2020(publicKey, options)
+
+	var err error
+	s.credentialJSON, err = os.ReadFile("testdata/unsignedCredential.json")
+	s.Require().NoError(err)
+	frameBytes, err := os.ReadFile("testdata/frame.json")
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(frameBytes, &s.frame))
+}
+
+func (s *VerifiableCredentialTestSuite) TestMapInterop() {
+	credential, err := model.ParseVerifiableCredential(s.credentialJSON)
+	s.Require().NoError(err)
+	s.Equal([]string{"VerifiableCredential", "PermanentResidentCard"}, credential.Type)
+	s.Equal("did:key:z6MknntgQWCT8Zs5vpQEVoV2HvsfdYfe7b1LTnM9Lty6fD4e", credential.CredentialSubject[0].ID)
+	s.Equal("Jace", credential.CredentialSubject[0].Claims["givenName"])
+	s.Equal("Permanent Resident Card", credential.Extra["name"])
+
+	// the unknown properties are preserved
+	var expected model.JsonLdCredentialNoProof
+	s.Require().NoError(json.Unmarshal(s.credentialJSON, &expected))
+	m, err := credential.ToMap()
+	s.Require().NoError(err)
+	s.Equal(expected, m)
+
+	// a signature of the typed credential verifies in the map form, and the other way around
+	signed, err := s.signatureSuite.SignCredential(context.Background(), credential)
+	s.Require().NoError(err)
+	s.Require().Len(signed.Proof, 1)
+	s.Equal(c.CredentialProofTypeBbsBlsSig2020, signed.Proof[0].Type)
+	s.NotNil(signed.Issuer)
+	signedMap, err := signed.ToMap()
+	s.Require().NoError(err)
+	result := s.signatureSuite.Verify(signedMap)
+	s.True(result.Success, result.Error)
+
+	signedMap, _, err = s.signatureSuite.Sign(expected)
+	s.Require().NoError(err)
+	signed, err = model.VerifiableCredentialFromMap(signedMap)
+	s.Require().NoError(err)
+	result = s.signatureSuite.VerifyCredential(context.Background(), signed)
+	s.True(result.Success, result.Error)
+}
+
+func (s *VerifiableCredentialTestSuite) TestDeriveAndVerify() {
+	ctx := context.Background()
+	credential, err := model.ParseVerifiableCredential(s.credentialJSON)
+	s.Require().NoError(err)
+	signed, err := s.signatureSuite.SignCredential(ctx, credential)
+	s.Require().NoError(err)
+
+	derived, err := s.proofSuite.DeriveProofCredential(ctx, signed, s.frame, []byte("verifier challenge"), nil)
+	s.Require().NoError(err)
+	s.Equal(c.CredentialDerivedProofTypeBbsBlsSig2020, derived.Proof[0].Type)
+	s.NotEmpty(derived.Proof[0].Nonce)
+	s.NotContains(derived.CredentialSubject[0].Claims, "givenName")
+
+	result := s.proofSuite.VerifyProofCredential(ctx, derived, nil)
+	s.True(result.Success, result.Error)
+
+	// the presentation of the derived credential
+	presentation, err := model.ParseVerifiablePresentation([]byte(`{
+		"@context": ["https://www.w3.org/2018/credentials/v1"],
+		"type": "VerifiablePresentation"
+	}`))
+	s.Require().NoError(err)
+	presentation.VerifiableCredential = append(presentation.VerifiableCredential, derived)
+	s.NoError(presentation.Validate())
+	data, err := json.Marshal(presentation)
+	s.Require().NoError(err)
+	parsed, err := model.ParseVerifiablePresentation(data)
+	s.Require().NoError(err)
+	result = s.proofSuite.VerifyProofCredential(ctx, parsed.VerifiableCredential[0], nil)
+	s.True(result.Success, result.Error)
+}
+
+func (s *VerifiableCredentialTestSuite) TestDeriveUndisclosedIssuanceDate() {
+	ctx := context.Background()
+	credential, err := model.ParseVerifiableCredential(s.credentialJSON)
+	s.Require().NoError(err)
+	signed, err := s.signatureSuite.SignCredential(ctx, credential)
+	s.Require().NoError(err)
+
+	frame := model.JsonLdFrame{}
+	for key, value := range s.frame {
+		frame[key] = value
+	}
+	delete(frame, c.CredentialFieldIssuanceDate)
+	derived, err := s.proofSuite.DeriveProofCredential(ctx, signed, frame, []byte("verifier challenge"), nil)
+	s.Require().NoError(err)
+	s.Empty(derived.IssuanceDate)
+	s.ErrorIs(derived.Validate(), model.ErrInvalidCredential)
+	s.NoError(derived.ValidateDerived())
+
+	result := s.proofSuite.VerifyProofCredential(ctx, derived, nil)
+	s.True(result.Success, result.Error)
+
+	presentation, err := model.ParseVerifiablePresentation([]byte(`{
+		"@context": ["https://www.w3.org/2018/credentials/v1"],
+		"type": "VerifiablePresentation"
+	}`))
+	s.Require().NoError(err)
+	presentation.VerifiableCredential = append(presentation.VerifiableCredential, derived)
+	s.NoError(presentation.Validate())
+
+	// a disclosed issuer must still be a URI
+	derived.Issuer = &model.Issuer{ID: "issuer"}
+	s.ErrorIs(derived.ValidateDerived(), model.ErrInvalidCredential)
+	s.ErrorIs(presentation.Validate(), model.ErrInvalidCredential)
+}
+
+func (s *VerifiableCredentialTestSuite) TestValidation() {
+	credential, err := model.ParseVerifiableCredential(s.credentialJSON)
+	s.Require().NoError(err)
+	// the issuer is added while signing
+	s.ErrorIs(credential.Validate(), model.ErrInvalidCredential)
+	credential.Issuer = &model.Issuer{ID: "did:example:issuer"}
+	s.NoError(credential.Validate())
+
+	invalid := []func(vc *model.VerifiableCredential){
+		func(vc *model.VerifiableCredential) { vc.Context = vc.Context[1:] },
+		func(vc *model.VerifiableCredential) { vc.Type = []string{"PermanentResidentCard"} },
+		func(vc *model.VerifiableCredential) { vc.IssuanceDate = "" },
+		func(vc *model.VerifiableCredential) { vc.IssuanceDate = "03/12/2019" },
+		func(vc *model.VerifiableCredential) { vc.CredentialSubject = nil },
+		func(vc *model.VerifiableCredential) {
+			vc.Proof = []*model.Proof{{Type: c.CredentialProofTypeBbsBlsSig2020}}
+		},
+	}
+	for _, invalidate := range invalid {
+		vc, err := model.ParseVerifiableCredential(s.credentialJSON)
+		s.Require().NoError(err)
+		vc.Issuer = credential.Issuer
+		invalidate(vc)
+		s.ErrorIs(vc.Validate(), model.ErrInvalidCredential)
+		s.False(s.signatureSuite.VerifyCredential(context.Background(), vc).Success)
+	}
+
+	_, err = model.ParseVerifiableCredential([]byte(`{"type": 1}`))
+	s.Error(err)
+}
+
+func (s *VerifiableCredentialTestSuite) TestMalformedMap() {
+	var credential model.JsonLdCredential
+	s.Require().NoError(json.Unmarshal(s.credentialJSON, &credential))
+
+	// malformed proofs are reported instead of panicking
+	for _, proof := range []interface{}{"proof", []interface{}{"proof"}, map[string]interface{}{"type": 1}} {
+		credential[c.CredentialFieldProof] = proof
+		s.False(s.signatureSuite.Verify(credential).Success)
+		s.False(s.proofSuite.VerifyProof(credential).Success)
+	}
+}
@@ -0,0 +1,135 @@
+// Package ietfbbs implements the BBS signature scheme of the IETF CFRG draft (draft-irtf-cfrg-bbs-signatures),
+// with its BLS12-381-SHA-256 and BLS12-381-SHAKE-256 ciphersuites and the hash-to-generators operations.
+package ietfbbs
+
+import (
+	"encoding/hex"
+	"errors"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/field/hash"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	scalarSize = 32 // octet_scalar_length
+	g1Size     = 48 // octet_point_length
+	g2Size     = 96
+	expandSize = 48 // expand_len
+)
+
+var (
+	ErrInvalidKey       = errors.New("invalid BBS key")
+	ErrInvalidSignature = errors.New("invalid BBS signature")
+	ErrInvalidProof     = errors.New("invalid BBS proof")
+	ErrInvalidIndexes   = errors.New("invalid indexes of the disclosed messages")
+)
+
+// A Ciphersuite is a set of BBS parameters: the hash function, the hash-to-curve suite and the base point P1.
+type Ciphersuite struct {
+	id            string // ciphersuite_id
+	expandMessage func(msg, dst []byte, length int) []byte
+	hashToCurve   func(msg, dst []byte) bls12381.G1Affine
+	p1            bls12381.G1Affine
+}
+
+var (
+	// BLS12381Sha256 The BLS12-381-SHA-256 ciphersuite, hashing with expand_message_xmd and SHA-256.
+	BLS12381Sha256 = newCiphersuite(
+		"BBS_BLS12381G1_XMD:SHA-256_SSWU_RO_",
+		expandMessageXMD,
+		hashToCurveXMD,
+		"a8ce256102840821a3e94ea9025e4662b205762f9776b3a766c872b948f1fd225e7c59698588e70d11406d161b4e28c9",
+	)
+	// BLS12381Shake256 The BLS12-381-SHAKE-256 ciphersuite, hashing with expand_message_xof and SHAKE-256.
+	BLS12381Shake256 = newCiphersuite(
+		"BBS_BLS12381G1_XOF:SHAKE-256_SSWU_RO_",
+		expandMessageXOF,
+		hashToCurveXOF,
+		"8929dfbc7e6642c4ed9cba0856e493f8b9d7d5fcb0c31ef8fdcd34d50648a56c795e106e9eada6e0bda386b414150755",
+	)
+)
+
+func newCiphersuite(id string, expandMessage func(msg, dst []byte, length int) []byte, hashToCurve func(msg, dst []byte) bls12381.G1Affine, p1Hex string) *Ciphersuite {
+	p1Bytes, err := hex.DecodeString(p1Hex)
+	if err != nil {
+		panic(err)
+	}
+	suite := &Ciphersuite{id: id, expandMessage: expandMessage, hashToCurve: hashToCurve}
+	if _, err := suite.p1.SetBytes(p1Bytes); err != nil {
+		panic(err)
+	}
+
+	return suite
+}
+
+// ID The ciphersuite_id of the ciphersuite.
+func (s *Ciphersuite) ID() string {
+	return s.id
+}
+
+// apiID The api_id of the operations hashing the messages to scalars (H2G_HM2S).
+func (s *Ciphersuite) apiID() []byte {
+	return []byte(s.id + "H2G_HM2S_")
+}
+
+// ExpandMessage The expand_message operation of the ciphersuite, e.g. to compute the mocked random scalars
+// of the test vectors of the draft.
+func (s *Ciphersuite) ExpandMessage(msg, dst []byte, length int) []byte {
+	return s.expandMessage(msg, dst, length)
+}
+
+func expandMessageXMD(msg, dst []byte, length int) []byte {
+	out, err := hash.ExpandMsgXmd(msg, dst, length)
+	if err != nil {
+		panic(err) // only for lengths and DSTs out of the bounds of the scheme
+	}
+
+	return out
+}
+
+// expandMessageXOF The expand_message_xof operation of RFC 9380, with SHAKE-256.
+func expandMessageXOF(msg, dst []byte, length int) []byte {
+	if len(dst) > 255 {
+		oversize := make([]byte, 64)
+		sha3.ShakeSum256(oversize, append([]byte("H2C-OVERSIZE-DST-"), dst...))
+		dst = oversize
+	}
+	h := sha3.NewShake256()
+	_, _ = h.Write(msg)
+	_, _ = h.Write([]byte{byte(length >> 8), byte(length)})
+	_, _ = h.Write(dst)
+	_, _ = h.Write([]byte{byte(len(dst))})
+	out := make([]byte, length)
+	_, _ = h.Read(out)
+
+	return out
+}
+
+func hashToCurveXMD(msg, dst []byte) bls12381.G1Affine {
+	point, err := bls12381.HashToG1(msg, dst)
+	if err != nil {
+		panic(err)
+	}
+
+	return point
+}
+
+// hashToCurveXOF The BLS12381G1_XOF:SHAKE-256_SSWU_RO_ hash_to_curve suite.
+// Clearing the cofactor being linear, the mapped points can be added after clearing it.
+func hashToCurveXOF(msg, dst []byte) bls12381.G1Affine {
+	uniform := expandMessageXOF(msg, dst, 2*64)
+	var u0, u1 fp.Element
+	setBytesWide(&u0, uniform[:64])
+	setBytesWide(&u1, uniform[64:])
+	q0, q1 := bls12381.MapToG1(u0), bls12381.MapToG1(u1)
+	var sum bls12381.G1Jac
+	sum.FromAffine(&q0)
+	sum.AddMixed(&q1)
+
+	var point bls12381.G1Affine
+	point.FromJacobian(&sum)
+
+	return point
+}
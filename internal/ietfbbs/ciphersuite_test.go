@@ -0,0 +1,288 @@
+package ietfbbs_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/ietfbbs"
+	"github.com/stretchr/testify/suite"
+)
+
+// The fixtures of the test vectors of the draft, shared by the ciphersuites.
+const (
+	keyMaterialHex = "746869732d49532d6a7573742d616e2d546573742d494b4d2d746f2d67656e65726174652d246528724074232d6b6579"
+	keyInfoHex     = "746869732d49532d736f6d652d6b65792d6d657461646174612d746f2d62652d757365642d696e2d746573742d6b65792d67656e"
+	headerHex      = "11223344556677889900aabbccddeeff"
+	presentHex     = "bed231d880675ed101ead304512e043ade9958dd0241ea70b4b3957fba941501"
+	mockSeed       = "3.141592653589793238462643383279"
+)
+
+var messagesHex = []string{
+	"9872ad089e452c7b6e283dfac2a80d58e8d0ff71cc4d5e310a1debdda4a45f02",
+	"c344136d9ab02da4dd5908bbba913ae6f58c2cc844b802a6f811f5fb075f9b80",
+	"7372e9daa5ed31e6cd5c825eac1b855e84476a1d94932aa348e07b73",
+	"77fe97eb97a1ebe2e81e4e3597a3ee740a66e9ef2412472c",
+	"496694774c5604ab1b2544eababcf0f53278ff50",
+	"515ae153e22aae04ad16f759e07237b4",
+	"d183ddc6e2665aa4e2f088af",
+	"ac55fb33a75909ed",
+	"96012096",
+	"",
+}
+
+// fixturesDir The fixtures published with the draft, in the layout of its fixture_data directory,
+// e.g. testdata/fixtures/bls12-381-sha-256/generators.json and testdata/fixtures/bls12-381-sha-256/proof/*.json.
+const fixturesDir = "testdata/fixtures"
+
+// requireFixturesEnv Environment variable failing the fixture tests, instead of skipping them, if the fixtures are missing.
+const requireFixturesEnv = "IETFBBS_REQUIRE_FIXTURES"
+
+// A generatorsFixture lists the generators of a ciphersuite, compressed and hex encoded.
+type generatorsFixture struct {
+	Q1            string   `json:"Q1"`
+	MsgGenerators []string `json:"MsgGenerators"`
+}
+
+// A proofFixture is a proof generated with the mocked random scalars, over the messages of the fixtures.
+type proofFixture struct {
+	CaseName      string `json:"caseName"`
+	SignerKeyPair struct {
+		PublicKey string `json:"publicKey"`
+	} `json:"signerKeyPair"`
+	Header             string            `json:"header"`
+	PresentationHeader string            `json:"presentationHeader"`
+	Signature          string            `json:"signature"`
+	RevealedMessages   map[string]string `json:"revealedMessages"`
+	TotalMessageCount  int               `json:"totalMessageCount"`
+	Proof              string            `json:"proof"`
+	Result             struct {
+		Valid  bool   `json:"valid"`
+		Reason string `json:"reason"`
+	} `json:"result"`
+}
+
+var fixtureCiphersuites = map[string]*ietfbbs.Ciphersuite{
+	"bls12-381-sha-256":   ietfbbs.BLS12381Sha256,
+	"bls12-381-shake-256": ietfbbs.BLS12381Shake256,
+}
+
+type CiphersuiteTestSuite struct {
+	suite.Suite
+	messages [][]byte
+	header   []byte
+}
+
+func TestCiphersuiteTestSuite(t *testing.T) {
+	suite.Run(t, new(CiphersuiteTestSuite))
+}
+
+func (s *CiphersuiteTestSuite) SetupTest() {
+	s.messages = make([][]byte, len(messagesHex))
+	for i, message := range messagesHex {
+		s.messages[i] = s.decode(message)
+	}
+	s.header = s.decode(headerHex)
+}
+
+func (s *CiphersuiteTestSuite) decode(value string) []byte {
+	decoded, err := hex.DecodeString(value)
+	s.Require().NoError(err)
+
+	return decoded
+}
+
+// keyPair Derive the key pair of the fixtures.
+func (s *CiphersuiteTestSuite) keyPair(ciphersuite *ietfbbs.Ciphersuite) ([]byte, []byte) {
+	secretKey, err := ciphersuite.KeyGen(s.decode(keyMaterialHex), s.decode(keyInfoHex), nil)
+	s.Require().NoError(err)
+	publicKey, err := ciphersuite.SkToPk(secretKey)
+	s.Require().NoError(err)
+
+	return secretKey, publicKey
+}
+
+// mockedRandomScalars The source of the mocked random scalars of the draft, for count scalars.
+func (s *CiphersuiteTestSuite) mockedRandomScalars(ciphersuite *ietfbbs.Ciphersuite, count int) *bytes.Reader {
+	dst := []byte(ciphersuite.ID() + "H2G_HM2S_MOCK_RANDOM_SCALARS_DST_")
+
+	return bytes.NewReader(ciphersuite.ExpandMessage([]byte(mockSeed), dst, 48*count))
+}
+
+// skipWithoutFixtures Skip a test whose fixtures have not been imported, or fail it if they are required.
+func (s *CiphersuiteTestSuite) skipWithoutFixtures(format string, args ...any) {
+	if os.Getenv(requireFixturesEnv) != "" {
+		s.T().Fatalf(format, args...)
+	}
+	s.T().Skipf(format, args...)
+}
+
+// loadFixture Decode a fixture of the draft, skipping the test if the fixtures have not been imported.
+func (s *CiphersuiteTestSuite) loadFixture(path string, fixture any) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		s.skipWithoutFixtures("fixture %s not imported, see testdata/README.md", path)
+	}
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(content, fixture), path)
+}
+
+func (s *CiphersuiteTestSuite) TestKeyPairs() {
+	secretKey, publicKey := s.keyPair(ietfbbs.BLS12381Sha256)
+	s.Equal("60e55110f76883a13d030b2f6bd11883422d5abde717569fc0731f51237169fc", hex.EncodeToString(secretKey))
+	s.Equal("a820f230f6ae38503b86c70dc50b61c58a77e45c39ab25c0652bbaa8fa136f2851bd4781c9dcde39fc9d1d52c9e60268061e7d7632171d91aa8d460acee0e96f1e7c4cfb12d3ff9ab5d5dc91c277db75c845d649ef3c4f63aebc364cd55ded0c", hex.EncodeToString(publicKey))
+
+	secretKey, publicKey = s.keyPair(ietfbbs.BLS12381Shake256)
+	s.Equal("2eee0f60a8a3a8bec0ee942bfd46cbdae9a0738ee68f5a64e7238311cf09a079", hex.EncodeToString(secretKey))
+	s.Equal("92d37d1d6cd38fea3a873953333eab23a4c0377e3e049974eb62bd45949cdeb18fb0490edcd4429adff56e65cbce42cf188b31bddbd619e419b99c2c41b38179eb001963bc3decaae0d9f702c7a8c004f207f46c734a5eae2e8e82833f3e7ea5", hex.EncodeToString(publicKey))
+
+	_, err := ietfbbs.BLS12381Sha256.KeyGen([]byte("too short"), nil, nil)
+	s.ErrorIs(err, ietfbbs.ErrInvalidKey)
+}
+
+func (s *CiphersuiteTestSuite) TestGenerators() {
+	generators := ietfbbs.BLS12381Shake256.Generators(2)
+	q1, h1 := generators[0].Bytes(), generators[1].Bytes()
+	s.Equal("a9d40131066399fd41af51d883f4473b0dcd7d028d3d34ef17f3241d204e28507d7ecae032afa1d5490849b7678ec1f8", hex.EncodeToString(q1[:]))
+	s.Equal("903c7ca0b7e78a2017d0baf74103bd00ca8ff9bf429f834f071c75ffe6bfdec6d6dca15417e4ac08ca4ae1e78b7adc0e", hex.EncodeToString(h1[:]))
+}
+
+func (s *CiphersuiteTestSuite) TestGeneratorsFixtures() {
+	for name, ciphersuite := range fixtureCiphersuites {
+		s.Run(name, func() {
+			var fixture generatorsFixture
+			s.loadFixture(filepath.Join(fixturesDir, name, "generators.json"), &fixture)
+			s.Require().NotEmpty(fixture.MsgGenerators)
+
+			generators := ciphersuite.Generators(1 + len(fixture.MsgGenerators))
+			for i, expected := range append([]string{fixture.Q1}, fixture.MsgGenerators...) {
+				generator := generators[i].Bytes()
+				s.Equal(expected, hex.EncodeToString(generator[:]), "generator %d", i)
+			}
+		})
+	}
+}
+
+func (s *CiphersuiteTestSuite) TestSignatures() {
+	for _, vector := range []struct {
+		ciphersuite *ietfbbs.Ciphersuite
+		messages    int
+		signature   string
+	}{
+		{ietfbbs.BLS12381Sha256, 1, "84773160b824e194073a57493dac1a20b667af70cd2352d8af241c77658da5253aa8458317cca0eae615690d55b1f27164657dcafee1d5c1973947aa70e2cfbb4c892340be5969920d0916067b4565a0"},
+		{ietfbbs.BLS12381Sha256, 10, "8339b285a4acd89dec7777c09543a43e3cc60684b0a6f8ab335da4825c96e1463e28f8c5f4fd0641d19cec5920d3a8ff4bedb6c9691454597bbd298288abed3632078557b2ace7d44caed846e1a0a1e8"},
+		{ietfbbs.BLS12381Shake256, 1, "b9a622a4b404e6ca4c85c15739d2124a1deb16df750be202e2430e169bc27fb71c44d98e6d40792033e1c452145ada95030832c5dc778334f2f1b528eced21b0b97a12025a283d78b7136bb9825d04ef"},
+	} {
+		secretKey, publicKey := s.keyPair(vector.ciphersuite)
+		messages := s.messages[:vector.messages]
+
+		signature, err := vector.ciphersuite.Sign(secretKey, publicKey, s.header, messages)
+		s.Require().NoError(err)
+		s.Equal(vector.signature, hex.EncodeToString(signature), vector.ciphersuite.ID())
+		s.NoError(vector.ciphersuite.Verify(publicKey, signature, s.header, messages))
+
+		// modified messages, header or signature
+		modified := append([][]byte{[]byte("modified")}, messages[1:]...)
+		s.ErrorIs(vector.ciphersuite.Verify(publicKey, signature, s.header, modified), ietfbbs.ErrInvalidSignature)
+		s.ErrorIs(vector.ciphersuite.Verify(publicKey, signature, nil, messages), ietfbbs.ErrInvalidSignature)
+		signature[len(signature)-1] ^= 1
+		s.ErrorIs(vector.ciphersuite.Verify(publicKey, signature, s.header, messages), ietfbbs.ErrInvalidSignature)
+	}
+}
+
+func (s *CiphersuiteTestSuite) TestProofs() {
+	presentationHeader := s.decode(presentHex)
+	disclosed := []int{0, 2, 4, 6}
+	disclosedMessages := [][]byte{s.messages[0], s.messages[2], s.messages[4], s.messages[6]}
+
+	for _, ciphersuite := range []*ietfbbs.Ciphersuite{ietfbbs.BLS12381Sha256, ietfbbs.BLS12381Shake256} {
+		secretKey, publicKey := s.keyPair(ciphersuite)
+		signature, err := ciphersuite.Sign(secretKey, publicKey, s.header, s.messages)
+		s.Require().NoError(err)
+
+		// the proofs generated with the mocked random scalars are reproducible
+		proof, err := ciphersuite.ProofGen(s.mockedRandomScalars(ciphersuite, 11), publicKey, signature, s.header, presentationHeader, s.messages, disclosed)
+		s.Require().NoError(err)
+		again, err := ciphersuite.ProofGen(s.mockedRandomScalars(ciphersuite, 11), publicKey, signature, s.header, presentationHeader, s.messages, disclosed)
+		s.Require().NoError(err)
+		s.Equal(proof, again)
+		s.Len(proof, 3*48+(4+len(s.messages)-len(disclosed))*32)
+		s.NoError(ciphersuite.ProofVerify(publicKey, proof, s.header, presentationHeader, disclosedMessages, disclosed))
+
+		// the proof is bound to the presentation header, the header and the disclosed messages
+		s.ErrorIs(ciphersuite.ProofVerify(publicKey, proof, s.header, []byte("another"), disclosedMessages, disclosed), ietfbbs.ErrInvalidProof)
+		s.ErrorIs(ciphersuite.ProofVerify(publicKey, proof, nil, presentationHeader, disclosedMessages, disclosed), ietfbbs.ErrInvalidProof)
+		s.ErrorIs(ciphersuite.ProofVerify(publicKey, proof, s.header, presentationHeader, s.messages[:4], disclosed), ietfbbs.ErrInvalidProof)
+		s.ErrorIs(ciphersuite.ProofVerify(publicKey, proof, s.header, presentationHeader, disclosedMessages, []int{2, 0, 4, 6}), ietfbbs.ErrInvalidIndexes)
+
+		// no message and all the messages disclosed
+		for _, disclosed := range [][]int{nil, {0, 1, 2, 3, 4, 5, 6, 7, 8, 9}} {
+			proof, err := ciphersuite.ProofGen(rand.Reader, publicKey, signature, s.header, nil, s.messages, disclosed)
+			s.Require().NoError(err)
+			disclosedMessages := make([][]byte, len(disclosed))
+			for i, index := range disclosed {
+				disclosedMessages[i] = s.messages[index]
+			}
+			s.NoError(ciphersuite.ProofVerify(publicKey, proof, s.header, nil, disclosedMessages, disclosed))
+		}
+
+		_, err = ciphersuite.ProofGen(rand.Reader, publicKey, signature, s.header, nil, s.messages, []int{10})
+		s.ErrorIs(err, ietfbbs.ErrInvalidIndexes)
+	}
+}
+
+func (s *CiphersuiteTestSuite) TestProofsFixtures() {
+	for name, ciphersuite := range fixtureCiphersuites {
+		s.Run(name, func() {
+			paths, err := filepath.Glob(filepath.Join(fixturesDir, name, "proof", "*.json"))
+			s.Require().NoError(err)
+			if len(paths) == 0 {
+				s.skipWithoutFixtures("proof fixtures of %s not imported, see testdata/README.md", name)
+			}
+
+			for _, path := range paths {
+				var fixture proofFixture
+				s.loadFixture(path, &fixture)
+				publicKey := s.decode(fixture.SignerKeyPair.PublicKey)
+				header := s.decode(fixture.Header)
+				presentationHeader := s.decode(fixture.PresentationHeader)
+				proof := s.decode(fixture.Proof)
+
+				disclosed := make([]int, 0, len(fixture.RevealedMessages))
+				for index := range fixture.RevealedMessages {
+					i, err := strconv.Atoi(index)
+					s.Require().NoError(err, path)
+					disclosed = append(disclosed, i)
+				}
+				slices.Sort(disclosed)
+				disclosedMessages := make([][]byte, len(disclosed))
+				for i, index := range disclosed {
+					disclosedMessages[i] = s.decode(fixture.RevealedMessages[strconv.Itoa(index)])
+				}
+
+				err := ciphersuite.ProofVerify(publicKey, proof, header, presentationHeader, disclosedMessages, disclosed)
+				if !fixture.Result.Valid {
+					s.Error(err, "%s: %s", fixture.CaseName, fixture.Result.Reason)
+					continue
+				}
+				s.NoError(err, fixture.CaseName)
+
+				// the valid proofs are generated with the mocked random scalars over the messages of the fixtures
+				if fixture.TotalMessageCount > len(s.messages) {
+					continue
+				}
+				randomScalars := s.mockedRandomScalars(ciphersuite, 5+fixture.TotalMessageCount-len(disclosed))
+				generated, err := ciphersuite.ProofGen(randomScalars, publicKey, s.decode(fixture.Signature), header, presentationHeader,
+					s.messages[:fixture.TotalMessageCount], disclosed)
+				s.Require().NoError(err, fixture.CaseName)
+				s.Equal(fixture.Proof, hex.EncodeToString(generated), fixture.CaseName)
+			}
+		})
+	}
+}
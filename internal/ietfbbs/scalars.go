@@ -0,0 +1,96 @@
+package ietfbbs
+
+import (
+	"encoding/binary"
+	"io"
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// setBytesWide Reduce a big-endian integer wider than the field modulo its order.
+func setBytesWide(element *fp.Element, b []byte) {
+	element.SetBigInt(new(big.Int).SetBytes(b))
+}
+
+// scalarFromBytesWide OS2IP(b) mod r.
+func scalarFromBytesWide(b []byte) fr.Element {
+	var scalar fr.Element
+	scalar.SetBigInt(new(big.Int).SetBytes(b))
+
+	return scalar
+}
+
+// hashToScalar The hash_to_scalar operation.
+func (s *Ciphersuite) hashToScalar(msg, dst []byte) fr.Element {
+	return scalarFromBytesWide(s.expandMessage(msg, dst, expandSize))
+}
+
+// randomScalars The calculate_random_scalars operation, reading expand_len octets per scalar.
+func randomScalars(rng io.Reader, count int) ([]fr.Element, error) {
+	buffer := make([]byte, expandSize)
+	scalars := make([]fr.Element, count)
+	for i := range scalars {
+		if _, err := io.ReadFull(rng, buffer); err != nil {
+			return nil, err
+		}
+		scalars[i] = scalarFromBytesWide(buffer)
+	}
+
+	return scalars, nil
+}
+
+// octetsToScalar Parse a scalar, rejecting the values out of the range of the scalar field.
+func octetsToScalar(b []byte) (fr.Element, bool) {
+	var scalar fr.Element
+	if len(b) != scalarSize {
+		return scalar, false
+	}
+	value := new(big.Int).SetBytes(b)
+	if value.Cmp(fr.Modulus()) >= 0 {
+		return scalar, false
+	}
+	scalar.SetBigInt(value)
+
+	return scalar, true
+}
+
+// serializer The serialize operation: points are compressed, scalars and integers are big-endian.
+type serializer []byte
+
+func (s serializer) scalar(scalar *fr.Element) serializer {
+	b := scalar.Bytes()
+	return append(s, b[:]...)
+}
+
+func (s serializer) point(point *bls12381.G1Affine) serializer {
+	b := point.Bytes()
+	return append(s, b[:]...)
+}
+
+func (s serializer) integer(i int) serializer {
+	return binary.BigEndian.AppendUint64(s, uint64(i))
+}
+
+func (s serializer) octets(b []byte) serializer {
+	return append(s, b...)
+}
+
+// multiExp The sum of the points multiplied by the scalars.
+func multiExp(points []bls12381.G1Affine, scalars []fr.Element) bls12381.G1Affine {
+	var result bls12381.G1Jac
+	for i := range points {
+		var term bls12381.G1Jac
+		var exponent big.Int
+		scalars[i].BigInt(&exponent)
+		term.ScalarMultiplication(new(bls12381.G1Jac).FromAffine(&points[i]), &exponent)
+		result.AddAssign(&term)
+	}
+
+	var point bls12381.G1Affine
+	point.FromJacobian(&result)
+
+	return point
+}
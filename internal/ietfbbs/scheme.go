@@ -0,0 +1,382 @@
+package ietfbbs
+
+import (
+	"encoding/binary"
+	"io"
+	"math/big"
+	"slices"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// KeyGen Derive a secret key from key material of at least 32 octets.
+//
+//	keyMaterial []byte secret, e.g. random octets
+//	keyInfo []byte nullable, public information bound to the key
+//	keyDST []byte nullable, defaults to api_id || "KEYGEN_DST_"
+//
+// returns:
+//
+//	secretKey []byte
+//	err error
+func (s *Ciphersuite) KeyGen(keyMaterial, keyInfo, keyDST []byte) ([]byte, error) {
+	if len(keyMaterial) < 32 || len(keyInfo) > 65535 {
+		return nil, ErrInvalidKey
+	}
+	if keyDST == nil {
+		keyDST = append(s.apiID(), "KEYGEN_DST_"...)
+	}
+	input := append(append(slices.Clone(keyMaterial), byte(len(keyInfo)>>8), byte(len(keyInfo))), keyInfo...)
+	secretKey := s.hashToScalar(input, keyDST)
+	if secretKey.IsZero() {
+		return nil, ErrInvalidKey
+	}
+	b := secretKey.Bytes()
+
+	return b[:], nil
+}
+
+// SkToPk The public key of a secret key: a compressed point of G2.
+func (s *Ciphersuite) SkToPk(secretKey []byte) ([]byte, error) {
+	sk, ok := octetsToScalar(secretKey)
+	if !ok || sk.IsZero() {
+		return nil, ErrInvalidKey
+	}
+	_, _, _, g2 := bls12381.Generators()
+	var exponent big.Int
+	var w bls12381.G2Affine
+	w.ScalarMultiplication(&g2, sk.BigInt(&exponent))
+	b := w.Bytes()
+
+	return b[:], nil
+}
+
+// Generators The create_generators operation: count points of G1 derived from the api_id.
+func (s *Ciphersuite) Generators(count int) []bls12381.G1Affine {
+	apiID := s.apiID()
+	seedDST := append(slices.Clone(apiID), "SIG_GENERATOR_SEED_"...)
+	generatorDST := append(slices.Clone(apiID), "SIG_GENERATOR_DST_"...)
+	v := s.expandMessage(append(slices.Clone(apiID), "MESSAGE_GENERATOR_SEED"...), seedDST, expandSize)
+
+	generators := make([]bls12381.G1Affine, count)
+	for i := range generators {
+		v = s.expandMessage(binary.BigEndian.AppendUint64(v, uint64(i+1)), seedDST, expandSize)
+		generators[i] = s.hashToCurve(v, generatorDST)
+	}
+
+	return generators
+}
+
+// messagesToScalars The messages_to_scalars operation.
+func (s *Ciphersuite) messagesToScalars(messages [][]byte) []fr.Element {
+	dst := append(s.apiID(), "MAP_MSG_TO_SCALAR_AS_HASH_"...)
+	scalars := make([]fr.Element, len(messages))
+	for i, message := range messages {
+		scalars[i] = s.hashToScalar(message, dst)
+	}
+
+	return scalars
+}
+
+// domain The calculate_domain operation, binding the public key, the generators and the header.
+func (s *Ciphersuite) domain(publicKey []byte, generators []bls12381.G1Affine, header []byte) fr.Element {
+	apiID := s.apiID()
+	input := serializer(slices.Clone(publicKey)).integer(len(generators) - 1)
+	for i := range generators {
+		input = input.point(&generators[i])
+	}
+	input = input.octets(apiID).integer(len(header)).octets(header)
+
+	return s.hashToScalar(input, append(apiID, "H2S_"...))
+}
+
+// commitment B = P1 + Q_1 * domain + H_1 * msg_1 + ... + H_L * msg_L.
+func (s *Ciphersuite) commitment(generators []bls12381.G1Affine, domain fr.Element, messages []fr.Element) bls12381.G1Affine {
+	points := append([]bls12381.G1Affine{s.p1}, generators...)
+	scalars := append([]fr.Element{fr.One(), domain}, messages...)
+
+	return multiExp(points, scalars)
+}
+
+// Sign Sign messages and a header with a secret key.
+//
+//	secretKey []byte
+//	publicKey []byte of the secret key
+//	header []byte nullable, context of the signature
+//	messages [][]byte
+//
+// returns:
+//
+//	signature []byte A || e
+//	err error
+func (s *Ciphersuite) Sign(secretKey, publicKey, header []byte, messages [][]byte) ([]byte, error) {
+	sk, ok := octetsToScalar(secretKey)
+	if !ok || sk.IsZero() {
+		return nil, ErrInvalidKey
+	}
+	if _, err := parsePublicKey(publicKey); err != nil {
+		return nil, err
+	}
+	scalars := s.messagesToScalars(messages)
+	generators := s.Generators(len(messages) + 1)
+	domain := s.domain(publicKey, generators, header)
+
+	input := serializer(nil).scalar(&sk)
+	for i := range scalars {
+		input = input.scalar(&scalars[i])
+	}
+	input = input.scalar(&domain)
+	e := s.hashToScalar(input, append(s.apiID(), "H2S_"...))
+
+	var inverse fr.Element
+	inverse.Add(&sk, &e)
+	if inverse.IsZero() {
+		return nil, ErrInvalidSignature
+	}
+	inverse.Inverse(&inverse)
+	b := s.commitment(generators, domain, scalars)
+	a := multiExp([]bls12381.G1Affine{b}, []fr.Element{inverse})
+
+	return serializer(nil).point(&a).scalar(&e), nil
+}
+
+// Verify Verify the signature of messages and of a header.
+func (s *Ciphersuite) Verify(publicKey, signature, header []byte, messages [][]byte) error {
+	w, err := parsePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+	a, e, err := parseSignature(signature)
+	if err != nil {
+		return err
+	}
+	scalars := s.messagesToScalars(messages)
+	generators := s.Generators(len(messages) + 1)
+	domain := s.domain(publicKey, generators, header)
+	b := s.commitment(generators, domain, scalars)
+
+	// e(A, W + BP2 * e) * e(B, -BP2) == 1
+	_, _, _, g2 := bls12381.Generators()
+	var exponent big.Int
+	var we bls12381.G2Jac
+	we.ScalarMultiplication(new(bls12381.G2Jac).FromAffine(&g2), e.BigInt(&exponent))
+	we.AddMixed(&w)
+	var weAffine, negG2 bls12381.G2Affine
+	weAffine.FromJacobian(&we)
+	negG2.Neg(&g2)
+	valid, err := bls12381.PairingCheck([]bls12381.G1Affine{a, b}, []bls12381.G2Affine{weAffine, negG2})
+	if err != nil || !valid {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// ProofGen Derive a proof of knowledge of a signature, disclosing some of its messages.
+//
+//	rng io.Reader source of the random scalars
+//	publicKey []byte
+//	signature []byte
+//	header []byte nullable, header of the signature
+//	presentationHeader []byte nullable, e.g. the nonce of the verifier
+//	messages [][]byte all the signed messages
+//	disclosed []int indexes of the disclosed messages
+//
+// returns:
+//
+//	proof []byte
+//	err error
+func (s *Ciphersuite) ProofGen(rng io.Reader, publicKey, signature, header, presentationHeader []byte, messages [][]byte, disclosed []int) ([]byte, error) {
+	if _, err := parsePublicKey(publicKey); err != nil {
+		return nil, err
+	}
+	a, e, err := parseSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+	disclosed, undisclosed, err := splitIndexes(disclosed, len(messages))
+	if err != nil {
+		return nil, err
+	}
+	random, err := randomScalars(rng, 5+len(undisclosed))
+	if err != nil {
+		return nil, err
+	}
+	scalars := s.messagesToScalars(messages)
+	generators := s.Generators(len(messages) + 1)
+	domain := s.domain(publicKey, generators, header)
+
+	// ProofInit
+	r1, r2, eTilde, r1Tilde, r3Tilde, mTilde := random[0], random[1], random[2], random[3], random[4], random[5:]
+	b := s.commitment(generators, domain, scalars)
+	d := multiExp([]bls12381.G1Affine{b}, []fr.Element{r2})
+	var r1r2, negE fr.Element
+	r1r2.Mul(&r1, &r2)
+	negE.Neg(&e)
+	aBar := multiExp([]bls12381.G1Affine{a}, []fr.Element{r1r2})
+	bBar := multiExp([]bls12381.G1Affine{d, aBar}, []fr.Element{r1, negE})
+	t1 := multiExp([]bls12381.G1Affine{aBar, d}, []fr.Element{eTilde, r1Tilde})
+	t2Points, t2Scalars := []bls12381.G1Affine{d}, []fr.Element{r3Tilde}
+	for i, j := range undisclosed {
+		t2Points, t2Scalars = append(t2Points, generators[j+1]), append(t2Scalars, mTilde[i])
+	}
+	t2 := multiExp(t2Points, t2Scalars)
+
+	challenge := s.challenge(aBar, bBar, d, t1, t2, domain, disclosed, scalars, presentationHeader)
+
+	// ProofFinalize
+	var r3, eHat, r1Hat, r3Hat, term fr.Element
+	r3.Inverse(&r2)
+	eHat.Add(&eTilde, term.Mul(&e, &challenge))
+	r1Hat.Sub(&r1Tilde, term.Mul(&r1, &challenge))
+	r3Hat.Sub(&r3Tilde, term.Mul(&r3, &challenge))
+	proof := serializer(nil).point(&aBar).point(&bBar).point(&d).scalar(&eHat).scalar(&r1Hat).scalar(&r3Hat)
+	for i, j := range undisclosed {
+		var mHat fr.Element
+		mHat.Add(&mTilde[i], term.Mul(&scalars[j], &challenge))
+		proof = proof.scalar(&mHat)
+	}
+
+	return proof.scalar(&challenge), nil
+}
+
+// ProofVerify Verify a proof of knowledge of a signature.
+//
+//	publicKey []byte
+//	proof []byte
+//	header []byte nullable, header of the signature
+//	presentationHeader []byte nullable
+//	disclosedMessages [][]byte messages of the disclosed indexes, in the same order
+//	disclosed []int indexes of the disclosed messages, in ascending order
+//
+// returns:
+//
+//	err error
+func (s *Ciphersuite) ProofVerify(publicKey, proof, header, presentationHeader []byte, disclosedMessages [][]byte, disclosed []int) error {
+	w, err := parsePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+	fixed := 3*g1Size + 4*scalarSize
+	if len(proof) < fixed || (len(proof)-fixed)%scalarSize != 0 || len(disclosedMessages) != len(disclosed) {
+		return ErrInvalidProof
+	}
+	count := len(disclosed) + (len(proof)-fixed)/scalarSize
+	sortedDisclosed, undisclosed, err := splitIndexes(disclosed, count)
+	if err != nil || !slices.Equal(sortedDisclosed, disclosed) {
+		return ErrInvalidIndexes
+	}
+
+	var points [3]bls12381.G1Affine
+	for i := range points {
+		if _, err := points[i].SetBytes(proof[i*g1Size : (i+1)*g1Size]); err != nil || points[i].IsInfinity() {
+			return ErrInvalidProof
+		}
+	}
+	aBar, bBar, d := points[0], points[1], points[2]
+	proofScalars := make([]fr.Element, (len(proof)-3*g1Size)/scalarSize)
+	for i := range proofScalars {
+		offset := 3*g1Size + i*scalarSize
+		var ok bool
+		if proofScalars[i], ok = octetsToScalar(proof[offset : offset+scalarSize]); !ok {
+			return ErrInvalidProof
+		}
+	}
+	eHat, r1Hat, r3Hat, mHat, cp := proofScalars[0], proofScalars[1], proofScalars[2], proofScalars[3:len(proofScalars)-1], proofScalars[len(proofScalars)-1]
+
+	generators := s.Generators(count + 1)
+	domain := s.domain(publicKey, generators, header)
+	disclosedScalars := s.messagesToScalars(disclosedMessages)
+
+	// ProofVerifyInit
+	t1 := multiExp([]bls12381.G1Affine{bBar, aBar, d}, []fr.Element{cp, eHat, r1Hat})
+	bvPoints, bvScalars := []bls12381.G1Affine{s.p1, generators[0]}, []fr.Element{fr.One(), domain}
+	for i, j := range disclosed {
+		bvPoints, bvScalars = append(bvPoints, generators[j+1]), append(bvScalars, disclosedScalars[i])
+	}
+	bv := multiExp(bvPoints, bvScalars)
+	t2Points, t2Scalars := []bls12381.G1Affine{bv, d}, []fr.Element{cp, r3Hat}
+	for i, j := range undisclosed {
+		t2Points, t2Scalars = append(t2Points, generators[j+1]), append(t2Scalars, mHat[i])
+	}
+	t2 := multiExp(t2Points, t2Scalars)
+
+	scalars := make([]fr.Element, count)
+	for i, j := range disclosed {
+		scalars[j] = disclosedScalars[i]
+	}
+	challenge := s.challenge(aBar, bBar, d, t1, t2, domain, disclosed, scalars, presentationHeader)
+	if !challenge.Equal(&cp) {
+		return ErrInvalidProof
+	}
+
+	// e(Abar, W) * e(Bbar, -BP2) == 1
+	_, _, _, g2 := bls12381.Generators()
+	var negG2 bls12381.G2Affine
+	negG2.Neg(&g2)
+	valid, err := bls12381.PairingCheck([]bls12381.G1Affine{aBar, bBar}, []bls12381.G2Affine{w, negG2})
+	if err != nil || !valid {
+		return ErrInvalidProof
+	}
+
+	return nil
+}
+
+// challenge The ProofChallengeCalculate operation.
+func (s *Ciphersuite) challenge(aBar, bBar, d, t1, t2 bls12381.G1Affine, domain fr.Element, disclosed []int, scalars []fr.Element, presentationHeader []byte) fr.Element {
+	input := serializer(nil).integer(len(disclosed))
+	for _, i := range disclosed {
+		input = input.integer(i).scalar(&scalars[i])
+	}
+	input = input.point(&aBar).point(&bBar).point(&d).point(&t1).point(&t2).scalar(&domain)
+	input = input.integer(len(presentationHeader)).octets(presentationHeader)
+
+	return s.hashToScalar(input, append(s.apiID(), "H2S_"...))
+}
+
+// splitIndexes Sort the disclosed indexes, and list the undisclosed ones.
+func splitIndexes(disclosed []int, count int) ([]int, []int, error) {
+	sorted := slices.Clone(disclosed)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+	if len(sorted) > 0 && (sorted[0] < 0 || sorted[len(sorted)-1] >= count) {
+		return nil, nil, ErrInvalidIndexes
+	}
+	undisclosed := make([]int, 0, count-len(sorted))
+	for i := 0; i < count; i++ {
+		if _, found := slices.BinarySearch(sorted, i); !found {
+			undisclosed = append(undisclosed, i)
+		}
+	}
+
+	return sorted, undisclosed, nil
+}
+
+func parsePublicKey(publicKey []byte) (bls12381.G2Affine, error) {
+	var w bls12381.G2Affine
+	if len(publicKey) != g2Size {
+		return w, ErrInvalidKey
+	}
+	if _, err := w.SetBytes(publicKey); err != nil || w.IsInfinity() {
+		return w, ErrInvalidKey
+	}
+
+	return w, nil
+}
+
+func parseSignature(signature []byte) (bls12381.G1Affine, fr.Element, error) {
+	var a bls12381.G1Affine
+	if len(signature) != g1Size+scalarSize {
+		return a, fr.Element{}, ErrInvalidSignature
+	}
+	if _, err := a.SetBytes(signature[:g1Size]); err != nil || a.IsInfinity() {
+		return a, fr.Element{}, ErrInvalidSignature
+	}
+	e, ok := octetsToScalar(signature[g1Size:])
+	if !ok || e.IsZero() {
+		return a, fr.Element{}, ErrInvalidSignature
+	}
+
+	return a, e, nil
+}
@@ -0,0 +1,149 @@
+package jsonldbbs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+)
+
+// An Issuer signs credentials with BbsBlsSignature2020 signatures.
+// An Issuer is safe for concurrent use, provided that its signer and document loader are.
+type Issuer struct {
+	suite *core.SignatureSuite2020
+}
+
+// NewIssuer creates new issuer
+// arguments:
+//
+//	ctx context.Context
+//	options ...Option WithKeyPair or WithSigner is required
+//
+// returns:
+//
+//	issuer *Issuer
+//	err error
+func NewIssuer(ctx context.Context, options ...Option) (*Issuer, error) {
+	cfg, err := newConfig(options)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.signer != nil {
+		suite, err := core.NewSignatureSuite2020WithSigner(ctx, cfg.signer, &cfg.suiteOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Issuer{suite: suite}, nil
+	}
+	if cfg.privateKey == nil {
+		return nil, fmt.Errorf("%w: a key pair or a signer is required", model.ErrNoSigner)
+	}
+
+	return &Issuer{suite: core.NewSignatureSuite2020(cfg.publicKey, cfg.privateKey, &cfg.suiteOptions)}, nil
+}
+
+// PublicKey returns the BBS+ public key of the issuer, to share with the holders and the verifiers.
+func (i *Issuer) PublicKey() []byte {
+	return i.suite.PublicKey()
+}
+
+// Issue signs a credential. If the issuer is not specified, it is added based on the "did:key" method.
+// arguments:
+//
+//	ctx context.Context
+//	credential model.JsonLdCredentialNoProof
+//
+// returns:
+//
+//	signedCredential model.JsonLdCredential
+//	err error
+func (i *Issuer) Issue(ctx context.Context, credential model.JsonLdCredentialNoProof) (model.JsonLdCredential, error) {
+	signed, _, err := i.suite.SignContext(ctx, credential)
+
+	return signed, err
+}
+
+// IssueCredential signs a typed credential.
+// arguments:
+//
+//	ctx context.Context
+//	credential *model.VerifiableCredential
+//
+// returns:
+//
+//	signedCredential *model.VerifiableCredential
+//	err error
+func (i *Issuer) IssueCredential(ctx context.Context, credential *model.VerifiableCredential) (*model.VerifiableCredential, error) {
+	return i.suite.SignCredential(ctx, credential)
+}
+
+// IssueBatch signs a batch of credentials in parallel.
+// arguments:
+//
+//	ctx context.Context
+//	credentials []model.JsonLdCredentialNoProof
+//	options *model.BatchOptions nullable
+//
+// returns:
+//
+//	results []*model.SignResult one result per credential, in the same order
+func (i *Issuer) IssueBatch(ctx context.Context, credentials []model.JsonLdCredentialNoProof, options *model.BatchOptions) []*model.SignResult {
+	return i.suite.SignBatch(ctx, credentials, options)
+}
+
+// PrepareHolderBinding computes the number of messages of a credential to bind to its holder,
+// to send to the holder together with a nonce.
+// arguments:
+//
+//	ctx context.Context
+//	credential model.JsonLdCredentialNoProof
+//
+// returns:
+//
+//	messagesCount int
+//	err error
+func (i *Issuer) PrepareHolderBinding(ctx context.Context, credential model.JsonLdCredentialNoProof) (int, error) {
+	return i.suite.PrepareHolderBinding(ctx, credential)
+}
+
+// IssueHolderBound signs a credential bound to the secret its holder committed to. A signer configured with WithSigner must implement model.BlindSigner.
+// arguments:
+//
+//	ctx context.Context
+//	credential model.JsonLdCredentialNoProof as passed to PrepareHolderBinding
+//	request *model.HolderBindingRequest sent by the holder
+//	issuerNonce []byte sent to the holder
+//
+// returns:
+//
+//	signedCredential model.JsonLdCredential to complete by the holder
+//	err error
+func (i *Issuer) IssueHolderBound(
+	ctx context.Context,
+	credential model.JsonLdCredentialNoProof,
+	request *model.HolderBindingRequest,
+	issuerNonce []byte,
+) (model.JsonLdCredential, error) {
+	signed, _, err := i.suite.SignHolderBound(ctx, credential, request, issuerNonce)
+
+	return signed, err
+}
+
+// IssueSubject signs a credential whose subject is a Go struct, whose fields are tagged with the IRIs of their JSON-LD terms
+// arguments:
+//
+//	ctx context.Context
+//	issuer *Issuer
+//	subject T a struct, or a pointer to a struct
+//	options *model.IssueOptions nullable
+//
+// returns:
+//
+//	signedCredential model.JsonLdCredential
+//	err error
+func IssueSubject[T any](ctx context.Context, issuer *Issuer, subject T, options *model.IssueOptions) (model.JsonLdCredential, error) {
+	return core.Issue(ctx, issuer.suite, subject, options)
+}
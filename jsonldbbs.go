@@ -1,10 +1,37 @@
 package jsonldbbs
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
 	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
 	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
 )
 
+// The types returned by the constructors of the package, so that they can be named outside of the module.
+type (
+	// SignatureSuite2020 Signs and verifies BbsBlsSignature2020 credentials.
+	SignatureSuite2020 = core.SignatureSuite2020
+	// SignatureProofSuite2020 Derives and verifies BbsBlsSignatureProof2020 proofs.
+	SignatureProofSuite2020 = core.SignatureProofSuite2020
+	// NonceManager Issues the nonces of a verifier and consumes them once.
+	NonceManager = core.NonceManager
+	// InMemoryNonceStore Stores the outstanding nonces of a NonceManager in memory.
+	InMemoryNonceStore = core.InMemoryNonceStore
+	// InMemorySigner Signs with a private key held in memory.
+	InMemorySigner = core.InMemorySigner
+	// HTTPSigner Delegates the signatures to a remote signing service.
+	HTTPSigner = core.HTTPSigner
+	// SignerHandler Exposes a signer with the endpoints expected by the HTTPSigner.
+	SignerHandler = core.SignerHandler
+	// MethodDidResolver Resolves DIDs with a resolver per DID method.
+	MethodDidResolver = core.MethodDidResolver
+	// DidDocumentBuilder Builds the DID document of an issuer.
+	DidDocumentBuilder = core.DidDocumentBuilder
+)
+
 // NewJsonLDBBSSignatureSuite2020 creates new signature suite
 // arguments:
 //
@@ -14,15 +41,34 @@ import (
 //
 // returns:
 //
-//	suite *core.SignatureSuite2020
+//	suite *SignatureSuite2020
 func NewJsonLDBBSSignatureSuite2020(
 	publicKey,
 	privateKey []byte,
 	options *model.SignatureSuiteOptions,
-) *core.SignatureSuite2020 {
+) *SignatureSuite2020 {
 	return core.NewSignatureSuite2020(publicKey, privateKey, options)
 }
 
+// NewJsonLDBBSSignatureSuite2020WithSigner creates new signature suite signing with an external signer
+// arguments:
+//
+//	ctx context.Context
+//	signer model.Signer The signer holding the private key, e.g. backed by a KMS or an HSM.
+//	options *model.SignatureSuiteOptions nullable
+//
+// returns:
+//
+//	suite *SignatureSuite2020
+//	err error if the public key cannot be retrieved from the signer
+func NewJsonLDBBSSignatureSuite2020WithSigner(
+	ctx context.Context,
+	signer model.Signer,
+	options *model.SignatureSuiteOptions,
+) (*SignatureSuite2020, error) {
+	return core.NewSignatureSuite2020WithSigner(ctx, signer, options)
+}
+
 // NewJsonLDBBSSignatureProofSuite2020 creates new signature proof suite
 // arguments:
 //
@@ -31,10 +77,517 @@ func NewJsonLDBBSSignatureSuite2020(
 //
 // returns:
 //
-//	suite *core.SignatureProofSuite2020
+//	suite *SignatureProofSuite2020
 func NewJsonLDBBSSignatureProofSuite2020(
 	publicKey []byte,
 	options *model.SignatureSuiteOptions,
-) *core.SignatureProofSuite2020 {
+) *SignatureProofSuite2020 {
 	return core.NewSignatureProofSuite2020(publicKey, options)
 }
+
+// NewNonceManager creates new verifier nonce manager
+// arguments:
+//
+//	store model.NonceStore nullable The storage backend for the outstanding nonces. If not provided, an in-memory store will be used.
+//	ttl time.Duration The validity of the issued nonces.
+//
+// returns:
+//
+//	manager *NonceManager
+func NewNonceManager(store model.NonceStore, ttl time.Duration) *NonceManager {
+	return core.NewNonceManager(store, ttl)
+}
+
+// NewNonceManagerWithOptions creates new verifier nonce manager with a custom clock or source of nonces
+// arguments:
+//
+//	store model.NonceStore nullable The storage backend for the outstanding nonces. If not provided, an in-memory store using the clock of the options will be used.
+//	ttl time.Duration The validity of the issued nonces.
+//	options *model.NonceManagerOptions nullable
+//
+// returns:
+//
+//	manager *NonceManager
+func NewNonceManagerWithOptions(store model.NonceStore, ttl time.Duration, options *model.NonceManagerOptions) *NonceManager {
+	return core.NewNonceManagerWithOptions(store, ttl, options)
+}
+
+// NewInMemoryNonceStore creates new in-memory nonce store
+//
+// returns:
+//
+//	store *InMemoryNonceStore
+func NewInMemoryNonceStore() *InMemoryNonceStore {
+	return core.NewInMemoryNonceStore()
+}
+
+// NewInMemoryNonceStoreWithClock creates new in-memory nonce store purging the expired nonces with a custom clock
+// arguments:
+//
+//	clock func() time.Time nullable, if not provided time.Now will be used
+//
+// returns:
+//
+//	store *InMemoryNonceStore
+func NewInMemoryNonceStoreWithClock(clock func() time.Time) *InMemoryNonceStore {
+	return core.NewInMemoryNonceStoreWithClock(clock)
+}
+
+// ConfigureContextCache changes the limits of the process-wide cache of remote JSON-LD contexts
+// shared by all the suites using the default document loader.
+// arguments:
+//
+//	maxEntries int The maximum number of contexts to keep, 0 means unbounded.
+//	ttl time.Duration The time a context is kept in the cache, 0 means no expiration.
+func ConfigureContextCache(maxEntries int, ttl time.Duration) {
+	core.SharedContextCache().Configure(maxEntries, ttl)
+}
+
+// NewInMemorySigner creates new signer holding the private key in memory
+// arguments:
+//
+//	publicKey []byte The BBS+ public key.
+//	privateKey []byte The BBS+ private key.
+//
+// returns:
+//
+//	signer *InMemorySigner
+func NewInMemorySigner(publicKey, privateKey []byte) *InMemorySigner {
+	return core.NewInMemorySigner(publicKey, privateKey)
+}
+
+// NewHTTPSigner creates new signer delegating the signatures to a remote signing service
+// arguments:
+//
+//	baseURL string The URL of the signing service.
+//	client *http.Client nullable If not provided, http.DefaultClient will be used.
+//
+// returns:
+//
+//	signer *HTTPSigner
+func NewHTTPSigner(baseURL string, client *http.Client) *HTTPSigner {
+	return core.NewHTTPSigner(baseURL, client)
+}
+
+// NewSignerHandler creates new HTTP handler exposing a signer with the endpoints expected by the HTTPSigner.
+// It is a stand-in for a remote signing service, meant for tests and local development.
+// arguments:
+//
+//	signer model.Signer The signer to expose.
+//
+// returns:
+//
+//	handler *SignerHandler
+func NewSignerHandler(signer model.Signer) *SignerHandler {
+	return core.NewSignerHandler(signer)
+}
+
+// NewInMemorySignerFromKeystore creates new in-memory signer from an encrypted keystore
+// arguments:
+//
+//	keystore *model.Keystore The keystore, e.g. read with LoadKeystore.
+//	passphrase []byte
+//
+// returns:
+//
+//	signer *InMemorySigner
+//	err error
+func NewInMemorySignerFromKeystore(keystore *model.Keystore, passphrase []byte) (*InMemorySigner, error) {
+	return core.NewInMemorySignerFromKeystore(keystore, passphrase)
+}
+
+// EncryptKey encrypts a BLS12-381 private key into a keystore
+// arguments:
+//
+//	publicKey []byte The BBS+ public key.
+//	privateKey []byte The BBS+ private key.
+//	passphrase []byte
+//	options *model.KeystoreOptions nullable
+//
+// returns:
+//
+//	keystore *model.Keystore
+//	err error
+func EncryptKey(publicKey, privateKey, passphrase []byte, options *model.KeystoreOptions) (*model.Keystore, error) {
+	return core.EncryptKey(publicKey, privateKey, passphrase, options)
+}
+
+// DecryptKey decrypts the private key of a keystore. The private key should be cleared with ZeroBytes after use.
+// arguments:
+//
+//	keystore *model.Keystore
+//	passphrase []byte
+//
+// returns:
+//
+//	publicKey []byte
+//	privateKey []byte
+//	err error
+func DecryptKey(keystore *model.Keystore, passphrase []byte) ([]byte, []byte, error) {
+	return core.DecryptKey(keystore, passphrase)
+}
+
+// SaveKeystore writes a keystore to a new file readable only by its owner
+// arguments:
+//
+//	path string
+//	keystore *model.Keystore
+//
+// returns:
+//
+//	err error
+func SaveKeystore(path string, keystore *model.Keystore) error {
+	return core.SaveKeystore(path, keystore)
+}
+
+// LoadKeystore reads a keystore file
+// arguments:
+//
+//	path string
+//
+// returns:
+//
+//	keystore *model.Keystore
+//	err error
+func LoadKeystore(path string) (*model.Keystore, error) {
+	return core.LoadKeystore(path)
+}
+
+// ReadPassphrase reads a passphrase from the first line of a reader, e.g. os.Stdin.
+// A terminal is read with its echo disabled.
+// arguments:
+//
+//	r io.Reader
+//
+// returns:
+//
+//	passphrase []byte
+//	err error
+func ReadPassphrase(r io.Reader) ([]byte, error) {
+	return core.ReadPassphrase(r)
+}
+
+// PassphraseFromEnv reads a passphrase from an environment variable and removes the variable from the environment
+// arguments:
+//
+//	name string
+//
+// returns:
+//
+//	passphrase []byte
+//	err error
+func PassphraseFromEnv(name string) ([]byte, error) {
+	return core.PassphraseFromEnv(name)
+}
+
+// ZeroBytes overwrites key material with zeros
+// arguments:
+//
+//	b []byte
+func ZeroBytes(b []byte) {
+	core.ZeroBytes(b)
+}
+
+// NewDidResolver creates new DID resolver supporting the did:key and did:web methods
+// arguments:
+//
+//	client *http.Client nullable The client used to fetch did:web documents. If not provided, http.DefaultClient will be used.
+//
+// returns:
+//
+//	resolver *MethodDidResolver
+func NewDidResolver(client *http.Client) *MethodDidResolver {
+	return core.NewDidResolver(client)
+}
+
+// DereferenceVerificationMethod resolves a verification method URL and checks that it is authorized for a proof purpose
+// arguments:
+//
+//	ctx context.Context
+//	resolver model.DidResolver
+//	verificationMethodURL string
+//	proofPurpose string e.g. "assertionMethod"
+//
+// returns:
+//
+//	verificationMethod *model.VerificationMethod
+//	publicKey []byte
+//	err error
+func DereferenceVerificationMethod(
+	ctx context.Context,
+	resolver model.DidResolver,
+	verificationMethodURL string,
+	proofPurpose string,
+) (*model.VerificationMethod, []byte, error) {
+	return core.DereferenceVerificationMethod(ctx, resolver, verificationMethodURL, proofPurpose)
+}
+
+// NewDidKeyDocumentBuilder creates new builder of the DID document of a did:key
+// arguments:
+//
+//	key model.DidDocumentKey The key of the DID.
+//
+// returns:
+//
+//	builder *DidDocumentBuilder
+//	err error
+func NewDidKeyDocumentBuilder(key model.DidDocumentKey) (*DidDocumentBuilder, error) {
+	return core.NewDidKeyDocumentBuilder(key)
+}
+
+// NewDidWebDocumentBuilder creates new builder of the DID document of a did:web
+// arguments:
+//
+//	did string The did:web of the issuer.
+//	key model.DidDocumentKey The main key of the issuer.
+//
+// returns:
+//
+//	builder *DidDocumentBuilder
+//	err error
+func NewDidWebDocumentBuilder(did string, key model.DidDocumentKey) (*DidDocumentBuilder, error) {
+	return core.NewDidWebDocumentBuilder(did, key)
+}
+
+// LoadVerificationPolicy reads a verification policy from a JSON or YAML (".yaml", ".yml") file
+// arguments:
+//
+//	path string
+//
+// returns:
+//
+//	policy *model.VerificationPolicy to set in model.SignatureSuiteOptions
+//	err error
+func LoadVerificationPolicy(path string) (*model.VerificationPolicy, error) {
+	return core.LoadVerificationPolicy(path)
+}
+
+// ParseVerificationPolicyJSON parses a verification policy from JSON
+// arguments:
+//
+//	data []byte
+//
+// returns:
+//
+//	policy *model.VerificationPolicy
+//	err error
+func ParseVerificationPolicyJSON(data []byte) (*model.VerificationPolicy, error) {
+	return core.ParseVerificationPolicyJSON(data)
+}
+
+// ParseVerificationPolicyYAML parses a verification policy from YAML
+// arguments:
+//
+//	data []byte
+//
+// returns:
+//
+//	policy *model.VerificationPolicy
+//	err error
+func ParseVerificationPolicyYAML(data []byte) (*model.VerificationPolicy, error) {
+	return core.ParseVerificationPolicyYAML(data)
+}
+
+// NewHolderSecret generates a random holder secret, to which credentials can be bound
+// returns:
+//
+//	secret model.HolderSecret to keep private
+//	err error
+func NewHolderSecret() (model.HolderSecret, error) {
+	return core.NewHolderSecret()
+}
+
+// NewHolderSecretWithRand generates a holder secret from a source of randomness, e.g. a seeded generator
+// arguments:
+//
+//	rand io.Reader
+//
+// returns:
+//
+//	secret model.HolderSecret to keep private
+//	err error
+func NewHolderSecretWithRand(rand io.Reader) (model.HolderSecret, error) {
+	return core.NewHolderSecretWithRand(rand)
+}
+
+// NewHolderBindingRequest commits to the holder secret in order to obtain a credential bound to it
+// arguments:
+//
+//	secret model.HolderSecret
+//	issuerPublicKey []byte
+//	messagesCount int returned by the issuer's PrepareHolderBinding
+//	issuerNonce []byte sent by the issuer
+//
+// returns:
+//
+//	request *model.HolderBindingRequest to send to the issuer
+//	blinding []byte to keep private until the credential is completed
+//	err error
+func NewHolderBindingRequest(
+	secret model.HolderSecret,
+	issuerPublicKey []byte,
+	messagesCount int,
+	issuerNonce []byte,
+) (*model.HolderBindingRequest, []byte, error) {
+	return core.NewHolderBindingRequest(secret, issuerPublicKey, messagesCount, issuerNonce)
+}
+
+// NewHolderBindingRequestWithRand commits to the holder secret as NewHolderBindingRequest,
+// drawing the blinding factor from a source of randomness, e.g. a seeded generator
+// arguments:
+//
+//	rand io.Reader
+//	secret model.HolderSecret
+//	issuerPublicKey []byte
+//	messagesCount int returned by the issuer's PrepareHolderBinding
+//	issuerNonce []byte sent by the issuer
+//
+// returns:
+//
+//	request *model.HolderBindingRequest to send to the issuer
+//	blinding []byte to keep private until the credential is completed
+//	err error
+func NewHolderBindingRequestWithRand(
+	rand io.Reader,
+	secret model.HolderSecret,
+	issuerPublicKey []byte,
+	messagesCount int,
+	issuerNonce []byte,
+) (*model.HolderBindingRequest, []byte, error) {
+	return core.NewHolderBindingRequestWithRand(rand, secret, issuerPublicKey, messagesCount, issuerNonce)
+}
+
+// Issue signs a credential whose subject is a Go struct, whose fields are tagged with the IRIs of their JSON-LD terms
+// arguments:
+//
+//	ctx context.Context
+//	suite *SignatureSuite2020
+//	subject T a struct, or a pointer to a struct
+//	options *model.IssueOptions nullable
+//
+// returns:
+//
+//	signedCredential model.JsonLdCredential
+//	err error
+func Issue[T any](ctx context.Context, suite *SignatureSuite2020, subject T, options *model.IssueOptions) (model.JsonLdCredential, error) {
+	return core.Issue(ctx, suite, subject, options)
+}
+
+// SubjectContext generates the JSON-LD context defining the terms of a Go struct
+// returns:
+//
+//	context map[string]interface{} the term definitions
+//	err error
+func SubjectContext[T any]() (map[string]interface{}, error) {
+	return core.SubjectContext[T]()
+}
+
+// ExtractSubject decodes the only subject of a credential, e.g. a verified derived credential, into a Go struct
+// arguments:
+//
+//	credential model.JsonLdCredential
+//
+// returns:
+//
+//	subject T
+//	err error wrapping model.ErrClaimNotDisclosed if a required claim is not disclosed
+func ExtractSubject[T any](credential model.JsonLdCredential) (T, error) {
+	return core.ExtractSubject[T](credential)
+}
+
+// ExtractSubjects decodes the subjects of a credential into Go structs
+// arguments:
+//
+//	credential model.JsonLdCredential
+//
+// returns:
+//
+//	subjects []T
+//	err error wrapping model.ErrClaimNotDisclosed if a required claim is not disclosed
+func ExtractSubjects[T any](credential model.JsonLdCredential) ([]T, error) {
+	return core.ExtractSubjects[T](credential)
+}
+
+// LoadConformanceVectors loads the conformance vectors of the JSON files of a directory, sorted by file name
+// arguments:
+//
+//	dir string
+//
+// returns:
+//
+//	vectors []*model.ConformanceVector
+//	err error
+func LoadConformanceVectors(dir string) ([]*model.ConformanceVector, error) {
+	return core.LoadConformanceVectors(dir)
+}
+
+// RunConformanceVector verifies the signed credential of a conformance vector, derives a proof from it and verifies
+// the derived credential of the vector, e.g. produced by another BbsBlsSignature2020 implementation
+// arguments:
+//
+//	ctx context.Context
+//	vector *model.ConformanceVector
+//	options *model.SignatureSuiteOptions nullable
+//
+// returns:
+//
+//	results []*model.ConformanceResult one result per check run
+//	err error if the vector is not well-formed
+func RunConformanceVector(ctx context.Context, vector *model.ConformanceVector, options *model.SignatureSuiteOptions) ([]*model.ConformanceResult, error) {
+	return core.RunConformanceVector(ctx, vector, options)
+}
+
+// LoadBbsVectors loads the BBS vectors of the JSON files of a directory, sorted by file name
+// arguments:
+//
+//	dir string
+//
+// returns:
+//
+//	vectors []*model.BbsVector
+//	err error
+func LoadBbsVectors(dir string) ([]*model.BbsVector, error) {
+	return core.LoadBbsVectors(dir)
+}
+
+// RunBbsVector verifies the signature or the derived proof of a BBS vector over its raw messages,
+// e.g. published by another BBS implementation
+// arguments:
+//
+//	vector *model.BbsVector
+//
+// returns:
+//
+//	results []*model.ConformanceResult one result per check run
+//	err error if the vector is not well-formed
+func RunBbsVector(vector *model.BbsVector) ([]*model.ConformanceResult, error) {
+	return core.RunBbsVector(vector)
+}
+
+// ExportConformanceVector signs a credential and derives a proof from it, as a conformance vector for other implementations
+// arguments:
+//
+//	ctx context.Context
+//	name string
+//	publicKey []byte
+//	privateKey []byte
+//	credential model.JsonLdCredentialNoProof
+//	frame model.JsonLdFrame
+//	nonce []byte
+//	options *model.SignatureSuiteOptions nullable, with a clock and a source of randomness for reproducible vectors
+//
+// returns:
+//
+//	vector *model.ConformanceVector
+//	err error
+func ExportConformanceVector(
+	ctx context.Context,
+	name string,
+	publicKey, privateKey []byte,
+	credential model.JsonLdCredentialNoProof,
+	frame model.JsonLdFrame,
+	nonce []byte,
+	options *model.SignatureSuiteOptions,
+) (*model.ConformanceVector, error) {
+	return core.ExportConformanceVector(ctx, name, publicKey, privateKey, credential, frame, nonce, options)
+}
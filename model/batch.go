@@ -0,0 +1,13 @@
+package model
+
+// BatchOptions Set of options to use to customize the processing of a batch of credentials.
+type BatchOptions struct {
+	Concurrency int // maximum number of credentials processed in parallel. If not provided, GOMAXPROCS will be used
+}
+
+// SignResult The result of the signature of a single credential within a batch.
+type SignResult struct {
+	Credential     JsonLdCredential // the signed credential, nil if the signature failed
+	JsonCredential string           // JSON representation of the signed credential
+	Error          error
+}
@@ -0,0 +1,14 @@
+package model
+
+// BbsCiphersuite A BBS signature scheme of the signatures and of the derived proofs of the suites.
+type BbsCiphersuite string
+
+const (
+	// BbsCiphersuiteBBSPlus The BBS+ scheme of aries-bbs-go, the only one supporting the predicate proofs,
+	// the holder binding, the pseudonyms, the linked proofs and the batch verification of the signatures.
+	BbsCiphersuiteBBSPlus BbsCiphersuite = "BBS+"
+	// BbsCiphersuiteBLS12381Sha256 The BLS12-381-SHA-256 ciphersuite of the IETF CFRG BBS draft.
+	BbsCiphersuiteBLS12381Sha256 BbsCiphersuite = "BLS12-381-SHA-256"
+	// BbsCiphersuiteBLS12381Shake256 The BLS12-381-SHAKE-256 ciphersuite of the IETF CFRG BBS draft.
+	BbsCiphersuiteBLS12381Shake256 BbsCiphersuite = "BLS12-381-SHAKE-256"
+)
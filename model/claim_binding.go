@@ -0,0 +1,11 @@
+package model
+
+// IssueOptions Set of options to issue a credential about a Go struct.
+type IssueOptions struct {
+	// the credential to which the subject is added: contexts, types, issuer, dates and other properties.
+	// If nil, a VerifiableCredential with the base contexts issued now.
+	Template *VerifiableCredential
+	// if true, a context defining the terms of the subject from their `jsonld` tags is embedded in the credential,
+	// otherwise the terms must be defined by the contexts of the template
+	GenerateContext bool
+}
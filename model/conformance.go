@@ -0,0 +1,65 @@
+package model
+
+// ConformanceVector A test vector exchanged with other BbsBlsSignature2020 implementations:
+// a signed credential, optionally a frame and a derived credential, with the expected verification outcomes.
+type ConformanceVector struct {
+	Name           string `json:"name"`
+	Description    string `json:"description,omitempty"`
+	Implementation string `json:"implementation"` // the implementation that produced the vector, e.g. jsonld-signatures-bbs
+	// the URL where a vector of another implementation has been published, and the license it is published under
+	Source  string `json:"source,omitempty"`
+	License string `json:"license,omitempty"`
+	// the Bls12381G2Key2020 public key of the issuer, in base58
+	PublicKeyBase58 string `json:"publicKeyBase58"`
+	// the BBS ciphersuite of the signature and of the derived proof, BbsCiphersuiteBBSPlus if empty
+	Ciphersuite BbsCiphersuite `json:"ciphersuite,omitempty"`
+	// the contexts used by the documents that are not preloaded by the suites, by URL
+	Contexts         map[string]map[string]interface{} `json:"contexts,omitempty"`
+	SignedCredential JsonLdCredential                  `json:"signedCredential"`
+	// the frame selecting the claims to disclose, required to derive a proof from the signed credential
+	Frame JsonLdFrame `json:"frame,omitempty"`
+	// the nonce of the derived credential, in base64
+	Nonce             string           `json:"nonce,omitempty"`
+	DerivedCredential JsonLdCredential `json:"derivedCredential,omitempty"`
+	// false if the signature of the signed credential must be rejected
+	SignatureValid bool `json:"signatureValid"`
+	// false if the proof of the derived credential must be rejected
+	DerivedProofValid bool `json:"derivedProofValid"`
+}
+
+// BbsVector A test vector of the BBS signature scheme alone, below the JSON-LD layer: a signature or a derived proof
+// over raw messages, published by another BBS implementation.
+type BbsVector struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Source      string `json:"source"`  // the URL where the vector has been published
+	License     string `json:"license"` // the license of the source
+	// the BBS ciphersuite of the signature or of the proof, BbsCiphersuiteBBSPlus if empty
+	Ciphersuite BbsCiphersuite `json:"ciphersuite,omitempty"`
+	PublicKey   string         `json:"publicKey"` // in base64
+	Messages    []string       `json:"messages"`  // the signed messages, as UTF-8 strings
+	Signature   string         `json:"signature,omitempty"`
+	// the derived proof, in base64, disclosing the messages of the revealed indexes
+	Proof    string `json:"proof,omitempty"`
+	Revealed []int  `json:"revealed,omitempty"`
+	Nonce    string `json:"nonce,omitempty"` // the nonce of the proof, in base64
+	// false if the signature or the proof must be rejected
+	Valid bool `json:"valid"`
+}
+
+// ConformanceCheck An operation run on a conformance vector.
+type ConformanceCheck string
+
+const (
+	ConformanceVerify      ConformanceCheck = "verify"      // verify the signed credential
+	ConformanceDeriveProof ConformanceCheck = "deriveProof" // derive a proof from the signed credential with the frame, and verify it
+	ConformanceVerifyProof ConformanceCheck = "verifyProof" // verify the derived credential
+)
+
+// ConformanceResult The outcome of a check on a conformance vector.
+type ConformanceResult struct {
+	Vector string
+	Check  ConformanceCheck
+	Passed bool  // true if the outcome is the expected one
+	Error  error // the error of the operation, if any, expected or not
+}
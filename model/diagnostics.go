@@ -0,0 +1,48 @@
+package model
+
+// ContextSource Where a JSON-LD context was loaded from during an operation.
+type ContextSource string
+
+const (
+	ContextSourcePreloaded      ContextSource = "preloaded"      // shipped with the library or provided in SignatureSuiteOptions.Contexts
+	ContextSourceCache          ContextSource = "cache"          // the process-wide cache of remote contexts
+	ContextSourceRemote         ContextSource = "remote"         // downloaded from the internet
+	ContextSourceDocumentLoader ContextSource = "documentLoader" // the custom document loader of SignatureSuiteOptions
+)
+
+// LoadedContext A JSON-LD context loaded while normalizing a document.
+type LoadedContext struct {
+	URL    string        `json:"url"`
+	Source ContextSource `json:"source"`
+}
+
+// StatementDiffKind The side of a statement-level diff on which a canonical statement appears.
+type StatementDiffKind string
+
+const (
+	StatementMissing    StatementDiffKind = "missing"    // in the reference, but not computed from the credential
+	StatementUnexpected StatementDiffKind = "unexpected" // computed from the credential, but not in the reference
+)
+
+// StatementDiff A canonical statement that differs between the credential and the reference.
+type StatementDiff struct {
+	Kind      StatementDiffKind `json:"kind"`
+	Statement string            `json:"statement"`
+}
+
+// VerificationDiagnostics Explanation of the verification of a signed credential.
+type VerificationDiagnostics struct {
+	Result *VerificationResult
+	// the canonical statements of the credential, as they are signed after the ones of the proof
+	Statements []string
+	// the canonical statements of the proof, as they are signed
+	ProofStatements []string
+	// the contexts loaded to normalize the credential and its proof, in load order
+	Contexts []LoadedContext
+	// dot-separated paths of the properties not defined by the contexts, thus not signed
+	DroppedTerms []string
+	// the statement-level diff of the credential statements with the reference, empty if no reference is provided or if the statements match
+	Diff []StatementDiff
+	// the error raised while computing the statements, if any
+	Error error
+}
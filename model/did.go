@@ -0,0 +1,101 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+)
+
+// DidResolver Resolves a DID to its DID document.
+type DidResolver interface {
+	Resolve(ctx context.Context, did string) (*DidDocument, error)
+}
+
+// DidDocument A DID document (https://www.w3.org/TR/did-core/), limited to the properties needed to verify proofs.
+type DidDocument struct {
+	Context              interface{}                `json:"@context"`
+	ID                   string                     `json:"id"`
+	Controller           interface{}                `json:"controller,omitempty"` // a DID or an array of DIDs
+	VerificationMethod   []VerificationMethod       `json:"verificationMethod,omitempty"`
+	Authentication       []VerificationRelationship `json:"authentication,omitempty"`
+	AssertionMethod      []VerificationRelationship `json:"assertionMethod,omitempty"`
+	CapabilityInvocation []VerificationRelationship `json:"capabilityInvocation,omitempty"`
+	CapabilityDelegation []VerificationRelationship `json:"capabilityDelegation,omitempty"`
+}
+
+// Relationship Return the entries of the verification relationship of a proof purpose.
+//
+//	proofPurpose string e.g. "assertionMethod"
+//
+// returns:
+//
+//	entries []VerificationRelationship nil if the proof purpose is unknown
+func (d *DidDocument) Relationship(proofPurpose string) []VerificationRelationship {
+	switch proofPurpose {
+	case c.ProofPurposeAssertionMethod:
+		return d.AssertionMethod
+	case c.ProofPurposeAuthentication:
+		return d.Authentication
+	case c.ProofPurposeCapabilityInvocation:
+		return d.CapabilityInvocation
+	case c.ProofPurposeCapabilityDelegation:
+		return d.CapabilityDelegation
+	default:
+		return nil
+	}
+}
+
+// VerificationMethod A public key of a DID document.
+type VerificationMethod struct {
+	ID                 string `json:"id"`
+	Type               string `json:"type"`
+	Controller         string `json:"controller"`
+	PublicKeyBase58    string `json:"publicKeyBase58,omitempty"`    // Bls12381G2Key2020
+	PublicKeyMultibase string `json:"publicKeyMultibase,omitempty"` // Multikey
+}
+
+// VerificationRelationship An entry of a verification relationship: either a reference to a verification method
+// of the document or an embedded verification method.
+type VerificationRelationship struct {
+	Reference string
+	Embedded  *VerificationMethod
+}
+
+// MarshalJSON Serialize the reference as a string or the embedded verification method as an object.
+func (r VerificationRelationship) MarshalJSON() ([]byte, error) {
+	if r.Embedded != nil {
+		return json.Marshal(r.Embedded)
+	}
+
+	return json.Marshal(r.Reference)
+}
+
+// UnmarshalJSON Parse a reference or an embedded verification method.
+func (r *VerificationRelationship) UnmarshalJSON(data []byte) error {
+	var reference string
+	if err := json.Unmarshal(data, &reference); err == nil {
+		r.Reference = reference
+		r.Embedded = nil
+
+		return nil
+	}
+
+	var embedded VerificationMethod
+	if err := json.Unmarshal(data, &embedded); err != nil {
+		return fmt.Errorf("verification relationship is neither a reference nor a verification method: %w", err)
+	}
+	r.Reference = ""
+	r.Embedded = &embedded
+
+	return nil
+}
+
+// DidDocumentKey A BBS+ public key to publish in a DID document.
+type DidDocumentKey struct {
+	PublicKey     []byte
+	Type          string   // constants.VerificationMethodTypeBls12381G2Key2020 (default) or constants.VerificationMethodTypeMultikey
+	Fragment      string   // fragment of the verification method id, default: the multibase encoded key, as in did:key
+	Relationships []string // proof purposes the key is authorized for, default: assertionMethod and authentication
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"errors"
+)
+
+var (
+	ErrNonceNotFound        = errors.New("nonce not found or already consumed")
+	ErrNonceExpired         = errors.New("nonce expired")
+	ErrNonceSessionMismatch = errors.New("nonce was issued for a different session")
+	ErrNonceMismatch        = errors.New("derived proofs do not share the same nonce")
+
+	ErrDocumentTooLarge               = errors.New("document exceeds the maximum size")
+	ErrDocumentTooDeep                = errors.New("document exceeds the maximum nesting depth")
+	ErrTooManyNQuads                  = errors.New("document exceeds the maximum number of N-Quads")
+	ErrTooManyBlankNodes              = errors.New("document exceeds the maximum number of blank nodes")
+	ErrTooManyContextFetches          = errors.New("document exceeds the maximum number of context fetches")
+	ErrContextTooLarge                = errors.New("context exceeds the maximum size")
+	ErrCanonicalizationBudgetExceeded = errors.New("canonicalization exceeds the work budget")
+
+	ErrNoSigner                = errors.New("no signer has been configured: the suite can only verify")
+	ErrInvalidSignerSignature  = errors.New("signature returned by the signer does not verify with its public key")
+	ErrKeystoreWrongPassphrase = errors.New("wrong passphrase or corrupted keystore")
+	ErrKeyPairMismatch         = errors.New("private key does not match the public key")
+	ErrEmptyPassphrase         = errors.New("passphrase is empty")
+	ErrKeystoreKDFParams       = errors.New("KDF parameters out of range")
+
+	ErrUnsupportedDidMethod            = errors.New("unsupported DID method")
+	ErrDidNotFound                     = errors.New("DID not found")
+	ErrVerificationMethodNotFound      = errors.New("verification method not found")
+	ErrUnsupportedVerificationMethod   = errors.New("unsupported verification method")
+	ErrVerificationMethodNotAuthorized = errors.New("verification method not authorized for the proof purpose")
+
+	ErrIssuerBindingFailed = errors.New("proof verification method is not controlled by the credential issuer")
+	ErrDidResolverRequired = errors.New("DID resolver required to bind the verification method to the issuer")
+
+	ErrPolicyViolation              = errors.New("credential does not satisfy the verification policy")
+	ErrTrustedIssuersWithoutBinding = errors.New("trusted issuers require the issuer binding, trust the issuer keys instead")
+
+	ErrBlindSigningUnsupported     = errors.New("signer does not support blind signing")
+	ErrInvalidHolderBindingRequest = errors.New("invalid holder binding request")
+	ErrHolderBindingRequired       = errors.New("derived proof is not bound to a holder secret")
+
+	ErrLinkedClaimNotFound    = errors.New("linked claim not found among the undisclosed messages")
+	ErrLinkedClaimsNotEqual   = errors.New("linked claims are not equal")
+	ErrLinkedClaimRequired    = errors.New("required claim link not proven")
+	ErrLinkedClaimUnsupported = errors.New("only the holder secret can be linked")
+
+	ErrPredicateClaimNotEncoded = errors.New("claim is not signed with a predicate encoding")
+	ErrPredicateNotSatisfied    = errors.New("claim does not satisfy the predicate")
+	ErrPredicateNotProven       = errors.New("required predicate is not proven")
+
+	ErrPseudonymRequired         = errors.New("derived proof does not contain a pseudonym for the verifier")
+	ErrPseudonymVerifierMismatch = errors.New("pseudonym has been derived for another verifier")
+	ErrPseudonymMismatch         = errors.New("derived proofs contain different pseudonyms")
+
+	ErrPrivacyWarningsNotAcknowledged = errors.New("privacy warnings not acknowledged")
+
+	ErrMalformedProofValue = errors.New("malformed proof value")
+
+	ErrRandomnessUnavailable = errors.New("source of randomness failed")
+
+	ErrInvalidCredential   = errors.New("invalid verifiable credential")
+	ErrInvalidPresentation = errors.New("invalid verifiable presentation")
+
+	ErrInvalidClaimBinding = errors.New("invalid JSON-LD term tags")
+	ErrClaimNotDisclosed   = errors.New("required claim not disclosed")
+
+	ErrUnknownCiphersuite       = errors.New("unknown BBS ciphersuite")
+	ErrUnsupportedByCiphersuite = errors.New("operation is not supported by the BBS ciphersuite")
+	ErrCiphersuiteMismatch      = errors.New("proof was created with another BBS ciphersuite")
+)
@@ -0,0 +1,33 @@
+package model
+
+import "context"
+
+// HolderSecret The secret of a holder, signed as a hidden message of the credentials bound to the holder.
+// It is never disclosed, neither to the issuer nor to the verifiers.
+type HolderSecret []byte
+
+// HolderBindingRequest Sent by the holder to the issuer to obtain a credential bound to its secret.
+// The issuer learns a hiding commitment to the secret, together with a proof of knowledge of its opening
+// bound to the nonce of the issuer.
+type HolderBindingRequest struct {
+	MessagesCount int    `json:"messagesCount"` // number of messages of the credential, including the holder binding messages
+	Commitment    []byte `json:"commitment"`    // commitment to the secret and to a blinding factor
+	Proof         []byte `json:"proof"`         // proof of knowledge of the committed values
+}
+
+// BlindSigner A Signer able to sign messages together with messages committed by the holder, without learning them.
+type BlindSigner interface {
+	Signer
+	// SignBlind Create a BBS+ signature over messagesCount messages: the known messages first,
+	// then the messages committed in commitment.
+	SignBlind(ctx context.Context, messages [][]byte, commitment []byte, messagesCount int) ([]byte, error)
+}
+
+// DeriveProofOptions Set of options to use to customize the derivation of a proof.
+type DeriveProofOptions struct {
+	HolderSecret HolderSecret // required to derive a proof from a credential bound to the holder
+	Predicates   []Predicate  // predicates to prove on undisclosed claims signed with a predicate encoding
+	VerifierID   string       // if not empty, the proof carries the pseudonym of the holder for this verifier; requires the holder secret
+
+	PrivacyLint *PrivacyLintOptions // optional, checks that the disclosed claims do not make the presentations correlatable
+}
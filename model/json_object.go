@@ -0,0 +1,153 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// The typed documents keep the properties they do not model in an Extra map, so that a document
+// unmarshalled and marshalled again produces the same statements, hence the same signature.
+
+// splitObject Split a JSON object into its known properties, kept raw, and the other ones.
+//
+//	data []byte The JSON object.
+//	known ...string The names of the known properties.
+//
+// returns:
+//
+//	fields map[string]json.RawMessage the known properties present in the object
+//	extra map[string]interface{} nil if there is no other property
+//	err error
+func splitObject(data []byte, known ...string) (map[string]json.RawMessage, map[string]interface{}, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, err
+	}
+
+	fields := make(map[string]json.RawMessage, len(known))
+	for _, name := range known {
+		if value, ok := raw[name]; ok {
+			fields[name] = value
+			delete(raw, name)
+		}
+	}
+
+	var extra map[string]interface{}
+	for name, value := range raw {
+		var decoded interface{}
+		if err := json.Unmarshal(value, &decoded); err != nil {
+			return nil, nil, err
+		}
+		if extra == nil {
+			extra = make(map[string]interface{}, len(raw))
+		}
+		extra[name] = decoded
+	}
+
+	return fields, extra, nil
+}
+
+// decodeField Decode a known property, if present.
+func decodeField(fields map[string]json.RawMessage, name string, target interface{}) error {
+	value, ok := fields[name]
+	if !ok {
+		return nil
+	}
+	if err := json.Unmarshal(value, target); err != nil {
+		return fmt.Errorf("property '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+// mergeObject Marshal the known properties of a document together with the other ones.
+// The empty known properties are omitted.
+func mergeObject(known map[string]interface{}, extra map[string]interface{}) ([]byte, error) {
+	object := make(map[string]interface{}, len(known)+len(extra))
+	for name, value := range extra {
+		object[name] = value
+	}
+	for name, value := range known {
+		if !isEmptyValue(value) {
+			object[name] = value
+		}
+	}
+
+	return json.Marshal(object)
+}
+
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []string:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	case []*Proof:
+		return len(v) == 0
+	case []*CredentialSubject:
+		return len(v) == 0
+	case []*VerifiableCredential:
+		return len(v) == 0
+	case *Issuer:
+		return v == nil
+	}
+
+	return false
+}
+
+// decodeOneOrMany Decode a property that is either a single value or an array of values.
+func decodeOneOrMany[T any](fields map[string]json.RawMessage, name string) ([]T, error) {
+	value, ok := fields[name]
+	if !ok {
+		return nil, nil
+	}
+
+	var many []T
+	if err := json.Unmarshal(value, &many); err == nil {
+		return many, nil
+	}
+	var one T
+	if err := json.Unmarshal(value, &one); err != nil {
+		return nil, fmt.Errorf("property '%s': %w", name, err)
+	}
+
+	return []T{one}, nil
+}
+
+// oneOrMany Marshal a single value as is, and several values as an array.
+func oneOrMany[T any](values []T) interface{} {
+	if len(values) == 1 {
+		return values[0]
+	}
+
+	return values
+}
+
+// toMap Convert a typed document to its map representation.
+func toMap(document json.Marshaler) (map[string]interface{}, error) {
+	data, err := document.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// fromMap Convert the map representation of a document to a typed document.
+func fromMap(m map[string]interface{}, document json.Unmarshaler) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	return document.UnmarshalJSON(data)
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+)
+
+// Keystore An encrypted BLS12-381 private key together with its metadata, as stored in a keystore file.
+// The metadata is authenticated by the encryption, so that it cannot be altered without the passphrase.
+type Keystore struct {
+	Version   int            `json:"version"`
+	KeyID     string         `json:"keyId"`
+	DidKey    string         `json:"didKey"`
+	Created   time.Time      `json:"created"`
+	PublicKey string         `json:"publicKey"` // hex encoded BBS+ public key
+	Crypto    KeystoreCrypto `json:"crypto"`
+}
+
+// KeystoreCrypto The parameters needed to decrypt the private key of a Keystore.
+type KeystoreCrypto struct {
+	KDF        string            `json:"kdf"` // constants.KeystoreKDFScrypt or constants.KeystoreKDFArgon2id
+	KDFParams  KeystoreKDFParams `json:"kdfParams"`
+	Cipher     string            `json:"cipher"` // constants.KeystoreCipherAES256GCM
+	Nonce      string            `json:"nonce"`  // hex encoded
+	Ciphertext string            `json:"ciphertext"`
+}
+
+// KeystoreKDFParams The parameters of the key derivation function deriving the encryption key from the passphrase.
+type KeystoreKDFParams struct {
+	Salt    string `json:"salt"` // hex encoded
+	KeyLen  int    `json:"keyLen"`
+	N       int    `json:"n,omitempty"`       // scrypt CPU/memory cost
+	R       int    `json:"r,omitempty"`       // scrypt block size
+	P       int    `json:"p,omitempty"`       // scrypt parallelization
+	Time    uint32 `json:"time,omitempty"`    // argon2id number of passes
+	Memory  uint32 `json:"memory,omitempty"`  // argon2id memory in KiB
+	Threads uint8  `json:"threads,omitempty"` // argon2id parallelism
+}
+
+// KeystoreOptions Set of options to use to customize the encryption of a private key.
+// Zero values are replaced with the defaults.
+type KeystoreOptions struct {
+	KeyID     string            // default: the did:key verification method of the public key
+	KDF       string            // default: constants.KeystoreKDFScrypt
+	KDFParams KeystoreKDFParams // cost parameters of the KDF, the salt and key length are always generated
+}
@@ -0,0 +1,32 @@
+package model
+
+// LinkedClaimHolderSecret Designates the holder secret of credentials bound to the same holder, i.e. a link secret.
+const LinkedClaimHolderSecret = "holderSecret"
+
+// LinkedCredential A signed credential to present in a linked proof.
+type LinkedCredential struct {
+	Credential   JsonLdCredential // the signed credential
+	Frame        JsonLdFrame      // the frame of the claims to disclose
+	PublicKey    []byte           // the key of the issuer, nil for the key of the suite
+	HolderSecret HolderSecret     // required if the credential is bound to its holder
+}
+
+// ClaimLink The undisclosed messages proven equal across the credentials of a linked proof.
+type ClaimLink struct {
+	Claim       string `json:"claim"`       // LinkedClaimHolderSecret
+	Credentials []int  `json:"credentials"` // the indexes of the linked credentials, at least two
+	Messages    []int  `json:"messages"`    // the index of the undisclosed message in each linked credential
+}
+
+// RequiredLink A claim that the verifier requires to be linked across the credentials of a linked proof.
+type RequiredLink struct {
+	Claim       string `json:"claim"`       // LinkedClaimHolderSecret
+	Credentials []int  `json:"credentials"` // the indexes of the credentials sharing the claim, all of them if empty
+}
+
+// LinkedProof Derived credentials whose proofs share a challenge and a blinding of the linked messages,
+// so that the verifier can check that the linked messages are equal without learning them.
+type LinkedProof struct {
+	Credentials []JsonLdCredential `json:"credentials"`
+	Links       []ClaimLink        `json:"links"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"io"
+	"time"
+)
+
+// NonceRecord The information stored by a verifier for every nonce it has issued.
+type NonceRecord struct {
+	SessionID string    // the verifier session the nonce has been issued for
+	ExpiresAt time.Time // instant after which the nonce cannot be consumed anymore
+}
+
+// NonceStore Storage backend used to keep track of the outstanding nonces issued by a verifier.
+// Implementations must be safe for concurrent use.
+type NonceStore interface {
+	// Save Store a newly issued nonce.
+	Save(nonce string, record NonceRecord) error
+	// Consume Atomically remove a nonce from the store and return its record.
+	// It returns ErrNonceNotFound if the nonce is unknown or has already been consumed.
+	Consume(nonce string) (*NonceRecord, error)
+}
+
+// NonceChecker Check that a nonce embedded in a derived proof has been issued by the verifier and mark it as consumed.
+type NonceChecker interface {
+	ConsumeNonce(sessionID string, nonce []byte) error
+}
+
+// NonceManagerOptions Set of options to use to customize the nonce manager behavior.
+type NonceManagerOptions struct {
+	// optional clock giving the expiration of the issued nonces and the current time of their checks.
+	// If not provided, time.Now will be used
+	Clock func() time.Time
+	// optional source of the issued nonces. If not provided, crypto/rand.Reader will be used.
+	// The reads of the nonces issued concurrently are serialized
+	Rand io.Reader
+}
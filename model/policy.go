@@ -0,0 +1,74 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// VerificationPolicy Set of rules evaluated on a credential after its cryptographic verification.
+// Every rule matching one of the types of the credential must be satisfied, and at least one rule must match.
+type VerificationPolicy struct {
+	Rules []PolicyRule `json:"rules" yaml:"rules"`
+}
+
+// PolicyRule Requirements on the credentials of a given type. Empty fields are not checked.
+type PolicyRule struct {
+	Name              string   `json:"name,omitempty" yaml:"name,omitempty"`                           // reported when the rule fails, defaults to "rules[<index>]"
+	CredentialType    string   `json:"credentialType,omitempty" yaml:"credentialType,omitempty"`       // type the rule applies to, all the credentials if empty
+	TrustedIssuers    []string `json:"trustedIssuers,omitempty" yaml:"trustedIssuers,omitempty"`       // issuer ids
+	TrustedKeys       []string `json:"trustedKeys,omitempty" yaml:"trustedKeys,omitempty"`             // verification keys, as did:key or multibase
+	RequiredContexts  []string `json:"requiredContexts,omitempty" yaml:"requiredContexts,omitempty"`   // contexts the credential must use
+	RequiredClaims    []string `json:"requiredClaims,omitempty" yaml:"requiredClaims,omitempty"`       // dot-separated paths, e.g. "credentialSubject.givenName"
+	MaxAge            Duration `json:"maxAge,omitempty" yaml:"maxAge,omitempty"`                       // maximum time elapsed since the issuance date
+	AllowedProofTypes []string `json:"allowedProofTypes,omitempty" yaml:"allowedProofTypes,omitempty"` // e.g. "BbsBlsSignatureProof2020" to accept only derived proofs
+}
+
+// Policy checks reported by a PolicyViolation.
+const (
+	PolicyCheckCredentialType   = "credentialType"
+	PolicyCheckTrustedIssuers   = "trustedIssuers"
+	PolicyCheckRequiredContexts = "requiredContexts"
+	PolicyCheckRequiredClaims   = "requiredClaims"
+	PolicyCheckMaxAge           = "maxAge"
+	PolicyCheckAllowedProofType = "allowedProofTypes"
+)
+
+// PolicyViolation Error reporting the rule and the check that a credential does not satisfy.
+// It wraps ErrPolicyViolation.
+type PolicyViolation struct {
+	Rule   string // name of the failed rule, empty if no rule matches the credential
+	Check  string // one of the PolicyCheck* constants
+	Reason string
+}
+
+func (v *PolicyViolation) Error() string {
+	if v.Rule == "" {
+		return fmt.Sprintf("%s: %s: %s", ErrPolicyViolation.Error(), v.Check, v.Reason)
+	}
+
+	return fmt.Sprintf("%s: rule '%s', %s: %s", ErrPolicyViolation.Error(), v.Rule, v.Check, v.Reason)
+}
+
+func (v *PolicyViolation) Unwrap() error {
+	return ErrPolicyViolation
+}
+
+// Duration A time.Duration written as a string in policy files, e.g. "720h".
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	if parsed < 0 {
+		return fmt.Errorf("negative duration '%s'", text)
+	}
+	*d = Duration(parsed)
+
+	return nil
+}
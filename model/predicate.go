@@ -0,0 +1,26 @@
+package model
+
+// PredicateOperator The comparison of a predicate.
+type PredicateOperator string
+
+const (
+	PredicateLessThan       PredicateOperator = "<"
+	PredicateLessOrEqual    PredicateOperator = "<="
+	PredicateGreaterThan    PredicateOperator = ">"
+	PredicateGreaterOrEqual PredicateOperator = ">="
+)
+
+// Predicate A comparison of an undisclosed claim with a bound, e.g. birthDate < 2008-10-17.
+type Predicate struct {
+	Claim    string            `json:"claim"`    // IRI of the predicate of the claim, e.g. http://schema.org/birthDate
+	Operator PredicateOperator `json:"operator"` // comparison of the claim with the bound
+	Value    string            `json:"value"`    // bound, in the lexical form of the datatype of the claim
+}
+
+// PredicateProof A proof that an undisclosed claim satisfies a predicate, embedded in a derived proof.
+type PredicateProof struct {
+	Predicate
+	Datatype string `json:"datatype"` // datatype of the claim: xsd:integer, xsd:date or xsd:dateTime
+	Message  int    `json:"message"`  // index of the hidden integer encoding of the claim among the signed messages
+	Proof    []byte `json:"proof"`    // range proof linked to the proof of knowledge of the signature
+}
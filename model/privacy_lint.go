@@ -0,0 +1,61 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PrivacyWarningKind The reason why a disclosed value makes the presentations of a credential correlatable.
+type PrivacyWarningKind string
+
+const (
+	PrivacyWarningIdentifier PrivacyWarningKind = "identifier" // a unique identifier, e.g. the id of the credential or of its subject
+	PrivacyWarningTimestamp  PrivacyWarningKind = "timestamp"  // an exact timestamp, e.g. the creation time of the proof
+	PrivacyWarningRareValue  PrivacyWarningKind = "rareValue"  // a value shared by few holders
+	// a verification method not controlled by the issuer, e.g. a key per holder
+	PrivacyWarningVerificationMethod PrivacyWarningKind = "verificationMethod"
+)
+
+// PrivacyWarning A disclosed value that makes the presentations of a credential correlatable.
+type PrivacyWarning struct {
+	Kind  PrivacyWarningKind `json:"kind"`
+	Path  string             `json:"path"` // dot-separated path of the value, e.g. "credentialSubject.id" or "proof.created"
+	Value string             `json:"value"`
+}
+
+func (w PrivacyWarning) String() string {
+	return fmt.Sprintf("%s '%s' disclosed at %s", w.Kind, w.Value, w.Path)
+}
+
+// PrivacyLintOptions Set of options of the privacy linter, run on the disclosed claims before deriving a proof.
+type PrivacyLintOptions struct {
+	// if true, the derivation fails with a *PrivacyLintError until every warning is acknowledged
+	RequireAcknowledgement bool
+	// paths of the warnings acknowledged by the holder
+	Acknowledged []string
+	// optional, receives the warnings of every derivation, acknowledged or not
+	OnWarnings func(warnings []PrivacyWarning)
+	// optional, returns the frequency of a disclosed value among the holders, between 0 and 1, if known
+	ValueFrequency func(path string, value interface{}) (float64, bool)
+	// values less frequent are reported as rare, defaults to constants.DefaultRareValueThreshold
+	RareValueThreshold float64
+}
+
+// PrivacyLintError Error reporting the privacy warnings not acknowledged by the holder.
+// It wraps ErrPrivacyWarningsNotAcknowledged.
+type PrivacyLintError struct {
+	Warnings []PrivacyWarning
+}
+
+func (e *PrivacyLintError) Error() string {
+	warnings := make([]string, len(e.Warnings))
+	for i, warning := range e.Warnings {
+		warnings[i] = warning.String()
+	}
+
+	return fmt.Sprintf("%s: %s", ErrPrivacyWarningsNotAcknowledged.Error(), strings.Join(warnings, ", "))
+}
+
+func (e *PrivacyLintError) Unwrap() error {
+	return ErrPrivacyWarningsNotAcknowledged
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"fmt"
 	"time"
 
 	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
@@ -38,8 +39,21 @@ func DeleteContextFromJsonLdProof(proof JsonLdProof) {
 //
 //	proof JsonLdProof The JSON-LD proof.
 func CreateDefaultJsonLDProof(verificationMethod string, compact bool) JsonLdProof {
+	return CreateDefaultJsonLDProofAt(verificationMethod, time.Now(), compact)
+}
+
+// CreateDefaultJsonLDProofAt Create a default JSON-LD Proof object created at a given time.
+//
+//	verificationMethod string The verification method to embed in the proof.
+//	created time.Time The creation time of the proof, truncated to the second.
+//	compact bool If true, skip the addition of the '@context' in the proof object.
+//
+// returns:
+//
+//	proof JsonLdProof The JSON-LD proof.
+func CreateDefaultJsonLDProofAt(verificationMethod string, created time.Time, compact bool) JsonLdProof {
 	defaultProof := JsonLdProof{
-		c.CredentialFieldCreated:            time.Now().UTC().Format(c.ProofTimestampFormat),
+		c.CredentialFieldCreated:            created.UTC().Format(c.ProofTimestampFormat),
 		c.CredentialFieldVerificationMethod: verificationMethod,
 		c.CredentialFieldType:               c.CredentialProofTypeBbsBlsSig2020,
 		c.CredentialFieldProofPurpose:       c.CredentialProofPurpose,
@@ -51,3 +65,82 @@ func CreateDefaultJsonLDProof(verificationMethod string, compact bool) JsonLdPro
 
 	return defaultProof
 }
+
+// Proof A typed proof of a credential or of a presentation.
+// The properties that are not modeled are kept in Extra, e.g. the predicate proofs or the pseudonym of a derived proof.
+type Proof struct {
+	Context            []interface{} // empty if the proof is compacted
+	Type               string
+	Created            string // kept as signed, parsed by Validate
+	VerificationMethod string
+	ProofPurpose       string
+	ProofValue         string // base64
+	Nonce              string // base64, only for a derived proof
+	Extra              map[string]interface{}
+}
+
+var proofFields = []string{
+	c.CredentialFieldContext, c.CredentialFieldType, c.CredentialFieldCreated, c.CredentialFieldVerificationMethod,
+	c.CredentialFieldProofPurpose, c.CredentialFieldProofValue, c.CredentialFieldNonce,
+}
+
+// ToMap Convert the proof to its map representation, as used by the suites.
+func (p *Proof) ToMap() (JsonLdProof, error) {
+	return toMap(p)
+}
+
+func (p *Proof) UnmarshalJSON(data []byte) error {
+	fields, extra, err := splitObject(data, proofFields...)
+	if err != nil {
+		return err
+	}
+
+	*p = Proof{Extra: extra}
+	if p.Context, err = decodeOneOrMany[interface{}](fields, c.CredentialFieldContext); err != nil {
+		return err
+	}
+	for name, target := range map[string]*string{
+		c.CredentialFieldType:               &p.Type,
+		c.CredentialFieldCreated:            &p.Created,
+		c.CredentialFieldVerificationMethod: &p.VerificationMethod,
+		c.CredentialFieldProofPurpose:       &p.ProofPurpose,
+		c.CredentialFieldProofValue:         &p.ProofValue,
+		c.CredentialFieldNonce:              &p.Nonce,
+	} {
+		if err := decodeField(fields, name, target); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Proof) MarshalJSON() ([]byte, error) {
+	return mergeObject(map[string]interface{}{
+		c.CredentialFieldContext:            p.Context,
+		c.CredentialFieldType:               p.Type,
+		c.CredentialFieldCreated:            p.Created,
+		c.CredentialFieldVerificationMethod: p.VerificationMethod,
+		c.CredentialFieldProofPurpose:       p.ProofPurpose,
+		c.CredentialFieldProofValue:         p.ProofValue,
+		c.CredentialFieldNonce:              p.Nonce,
+	}, p.Extra)
+}
+
+// Validate Check that the proof has a type, a verification method and a well-formed creation date.
+func (p *Proof) Validate() error {
+	if p == nil {
+		return fmt.Errorf("empty '%s'", c.CredentialFieldProof)
+	}
+	if p.Type == "" {
+		return fmt.Errorf("missing proof '%s'", c.CredentialFieldType)
+	}
+	if p.VerificationMethod == "" {
+		return fmt.Errorf("missing proof '%s'", c.CredentialFieldVerificationMethod)
+	}
+	if _, err := time.Parse(time.RFC3339Nano, p.Created); p.Created != "" && err != nil {
+		return fmt.Errorf("proof '%s' is not a date-time: %s", c.CredentialFieldCreated, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package model
+
+// ProofComponent A component of a decoded proof value.
+type ProofComponent struct {
+	Name   string `json:"name"`
+	Offset int    `json:"offset"` // in bytes, from the start of the proof value
+	Size   int    `json:"size"`   // in bytes
+}
+
+// InspectedStatement A signed message disclosed by a proof, mapped to the N-Quad of the presented credential.
+type InspectedStatement struct {
+	Index     int    `json:"index"` // index of the message among the signed messages
+	Statement string `json:"statement"`
+}
+
+// ProofInspection The decoded proof value of a BbsBlsSignature2020 signature or of a BbsBlsSignatureProof2020 derived proof.
+type ProofInspection struct {
+	Type       string           `json:"type"`
+	Size       int              `json:"size"` // in bytes
+	Components []ProofComponent `json:"components"`
+	// the number of signed messages; for a signature, the number of messages verified by the suite,
+	// including the holder binding marker and the holder secret of a credential bound to its holder
+	MessagesCount int `json:"messagesCount"`
+	// the indexes of the disclosed messages, all the messages but the holder secret for a signature
+	RevealedIndexes []int `json:"revealedIndexes"`
+	// one character per signed message, '1' if the message is disclosed and '0' otherwise
+	RevealedBitmap string `json:"revealedBitmap"`
+	// the nonce of the verifier, only for a derived proof
+	Nonce []byte `json:"nonce,omitempty"`
+	// the disclosed messages mapped to the statements of the presented credential
+	Statements []InspectedStatement `json:"statements"`
+	// only for a signature, whether it verifies over the inspected messages;
+	// nil if it is not checked, for a credential bound to a holder secret that is not provided
+	Verified *bool `json:"verified,omitempty"`
+}
+
+// InspectProofOptions The holder binding layout of the signature to inspect.
+// A BBS+ signature does not record its number of messages: the signature of a credential bound to its holder
+// is only inspected with the holder binding marker and the holder secret if the layout is given.
+type InspectProofOptions struct {
+	// the secret the credential is bound to, with which the signature is verified
+	HolderSecret HolderSecret
+	// true if the credential is bound to a holder secret that is not provided, the signature being then not verified
+	HolderBound bool
+}
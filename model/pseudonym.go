@@ -0,0 +1,10 @@
+package model
+
+// Pseudonym The pseudonym of the holder of a credential for a verifier, embedded in a derived proof.
+// It is computed from the hidden holder secret and the identifier of the verifier: the proofs presented
+// to the same verifier carry the same pseudonym, while the pseudonyms for different verifiers cannot be linked.
+type Pseudonym struct {
+	VerifierID string `json:"verifierId"` // identifier of the verifier, e.g. its origin
+	Value      []byte `json:"value"`      // the pseudonym
+	Proof      []byte `json:"proof"`      // proof that the pseudonym is computed from the signed holder secret
+}
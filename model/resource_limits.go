@@ -0,0 +1,27 @@
+package model
+
+// ResourceLimits Limits protecting the normalization against malicious JSON-LD documents.
+// A zero value means that the corresponding limit is not enforced.
+type ResourceLimits struct {
+	MaxDocumentBytes         int // size of the JSON serialization of a document
+	MaxNestingDepth          int // nesting of the objects and arrays of a document
+	MaxNQuads                int // number of N-Quads produced by the normalization of a document
+	MaxBlankNodes            int // number of blank nodes produced by the normalization of a document
+	MaxContextFetches        int // number of contexts requested from the document loader, preloaded contexts excluded
+	MaxContextBytes          int // size of the JSON serialization of a loaded context
+	MaxCanonicalizationSteps int // work budget of the URDNA2015 canonicalization
+}
+
+// DefaultResourceLimits Return the recommended limits for the documents received from third parties.
+// They are not enforced unless provided in the SignatureSuiteOptions. They leave ample room for real-world credentials while bounding the work done on adversarial inputs.
+func DefaultResourceLimits() *ResourceLimits {
+	return &ResourceLimits{
+		MaxDocumentBytes:         2 << 20,
+		MaxNestingDepth:          64,
+		MaxNQuads:                10000,
+		MaxBlankNodes:            1000,
+		MaxContextFetches:        32,
+		MaxContextBytes:          2 << 20,
+		MaxCanonicalizationSteps: 100000,
+	}
+}
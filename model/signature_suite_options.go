@@ -1,6 +1,10 @@
 package model
 
 import (
+	"context"
+	"io"
+	"time"
+
 	"github.com/piprate/json-gold/ld"
 )
 
@@ -8,4 +12,37 @@ import (
 type SignatureSuiteOptions struct {
 	DocumentLoader ld.DocumentLoader                 // optional custom document loader. If not provided, default will be used
 	Contexts       map[string]map[string]interface{} // additional credential contexts, will be merges in the defaults
+	Limits         *ResourceLimits                   // optional resource limits, e.g. DefaultResourceLimits. If not provided, no limit is enforced
+
+	// optional resolver used to check that the verification method of a proof is controlled by the credential issuer.
+	// If not provided, the verification method must be the did:key of the issuer
+	DidResolver DidResolver
+	// optional opt-out of the check that the verification method of a proof is controlled by the credential issuer,
+	// for the callers binding the key to the issuer by other means, e.g. a pinned issuer key;
+	// the policy rules can then trust the issuer keys but not the issuer ids, asserted by the credentials
+	SkipIssuerBinding bool
+	// optional verification method written in the created proofs. If not provided, the did:key of the public key will be used
+	VerificationMethod string
+	// optional policy evaluated on the credentials after their cryptographic verification
+	Policy *VerificationPolicy
+	// optional IRIs of the integer and date claims signed with an encoding allowing predicate proofs, e.g. birthDate.
+	// The issuer, the holder and the verifiers of the signed credentials must use the same claims
+	PredicateClaims []string
+	// optional clock giving the creation time of the proofs and of the issued credentials, and the current time
+	// of the verification policies. If not provided, time.Now will be used
+	Clock func() time.Time
+	// optional source of the random factors of the signatures, of the derived proofs and of the holder binding requests
+	// of a Holder, e.g. a seeded generator to produce reproducible test vectors. If not provided, crypto/rand.Reader will be used.
+	// The reads of the operations running concurrently are serialized, in no particular order
+	Rand io.Reader
+	// optional BBS ciphersuite of the signatures and of the derived proofs. If not provided, BbsCiphersuiteBBSPlus
+	// will be used. The issuer, the holder and the verifiers of the signed credentials must use the same ciphersuite
+	Ciphersuite BbsCiphersuite
+}
+
+// ContextDocumentLoader A document loader supporting cancellation.
+// If the custom document loader implements this interface, the context of the suite operation is passed to it.
+type ContextDocumentLoader interface {
+	ld.DocumentLoader
+	LoadDocumentContext(ctx context.Context, u string) (*ld.RemoteDocument, error)
 }
@@ -0,0 +1,13 @@
+package model
+
+import (
+	"context"
+)
+
+// Signer Creates BBS+ signatures with a private key that may be held outside the process, e.g. in a KMS or an HSM.
+type Signer interface {
+	// Sign Create a BBS+ signature over a vector of messages.
+	Sign(ctx context.Context, messages [][]byte) ([]byte, error)
+	// PublicKey Return the BBS+ public key matching the private key used to sign.
+	PublicKey(ctx context.Context) ([]byte, error)
+}
@@ -2,6 +2,7 @@ package model
 
 // VerificationResult Wrapper to contain the error in case the verification failed.
 type VerificationResult struct {
-	Success bool
-	Error   error
+	Success   bool
+	Error     error
+	Pseudonym []byte // the pseudonym of the holder for the verifier, if the derived proofs carry one
 }
@@ -0,0 +1,240 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+)
+
+// VerifiableCredential A typed verifiable credential, signed or not.
+// The properties that are not modeled are kept in Extra, e.g. "name", "expirationDate" or "credentialStatus".
+type VerifiableCredential struct {
+	Context           []interface{} // the URLs or the embedded contexts, in order
+	ID                string
+	Type              []string
+	Issuer            *Issuer
+	IssuanceDate      string // kept as signed, parsed by Validate
+	ValidFrom         string
+	CredentialSubject []*CredentialSubject
+	Proof             []*Proof
+	Extra             map[string]interface{}
+}
+
+// Issuer The issuer of a credential, marshalled as its identifier unless it has other properties.
+type Issuer struct {
+	ID    string
+	Extra map[string]interface{}
+}
+
+// CredentialSubject A subject of a credential and the claims about it.
+type CredentialSubject struct {
+	ID     string
+	Claims map[string]interface{} // the other properties, including the type of the subject
+}
+
+var credentialFields = []string{
+	c.CredentialFieldContext, c.CredentialFieldId, c.CredentialFieldType, c.CredentialFieldIssuer, c.CredentialFieldIssuanceDate,
+	c.CredentialFieldValidFrom, c.CredentialFieldCredentialSubject, c.CredentialFieldProof,
+}
+
+// ParseVerifiableCredential Parse the JSON representation of a credential.
+//
+//	data []byte
+//
+// returns:
+//
+//	credential *VerifiableCredential not validated
+//	err error
+func ParseVerifiableCredential(data []byte) (*VerifiableCredential, error) {
+	credential := &VerifiableCredential{}
+	if err := credential.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+
+	return credential, nil
+}
+
+// VerifiableCredentialFromMap Convert the map representation of a credential, as used by the suites.
+//
+//	credential JsonLdCredential
+//
+// returns:
+//
+//	credential *VerifiableCredential not validated
+//	err error
+func VerifiableCredentialFromMap(credential JsonLdCredential) (*VerifiableCredential, error) {
+	typed := &VerifiableCredential{}
+	if err := fromMap(credential, typed); err != nil {
+		return nil, err
+	}
+
+	return typed, nil
+}
+
+// ToMap Convert the credential to its map representation, as used by the suites.
+func (vc *VerifiableCredential) ToMap() (JsonLdCredential, error) {
+	return toMap(vc)
+}
+
+func (vc *VerifiableCredential) UnmarshalJSON(data []byte) error {
+	fields, extra, err := splitObject(data, credentialFields...)
+	if err != nil {
+		return err
+	}
+
+	*vc = VerifiableCredential{Extra: extra}
+	if vc.Context, err = decodeOneOrMany[interface{}](fields, c.CredentialFieldContext); err != nil {
+		return err
+	}
+	if vc.Type, err = decodeOneOrMany[string](fields, c.CredentialFieldType); err != nil {
+		return err
+	}
+	if vc.CredentialSubject, err = decodeOneOrMany[*CredentialSubject](fields, c.CredentialFieldCredentialSubject); err != nil {
+		return err
+	}
+	if vc.Proof, err = decodeOneOrMany[*Proof](fields, c.CredentialFieldProof); err != nil {
+		return err
+	}
+	for name, target := range map[string]interface{}{
+		c.CredentialFieldId:           &vc.ID,
+		c.CredentialFieldIssuer:       &vc.Issuer,
+		c.CredentialFieldIssuanceDate: &vc.IssuanceDate,
+		c.CredentialFieldValidFrom:    &vc.ValidFrom,
+	} {
+		if err := decodeField(fields, name, target); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (vc *VerifiableCredential) MarshalJSON() ([]byte, error) {
+	return mergeObject(map[string]interface{}{
+		c.CredentialFieldContext:           vc.Context,
+		c.CredentialFieldId:                vc.ID,
+		c.CredentialFieldType:              vc.Type,
+		c.CredentialFieldIssuer:            vc.Issuer,
+		c.CredentialFieldIssuanceDate:      vc.IssuanceDate,
+		c.CredentialFieldValidFrom:         vc.ValidFrom,
+		c.CredentialFieldCredentialSubject: oneOrMany(vc.CredentialSubject),
+		c.CredentialFieldProof:             oneOrMany(vc.Proof),
+	}, vc.Extra)
+}
+
+// Validate Check the properties required by the Verifiable Credentials Data Model:
+// the base context first, the VerifiableCredential type, an issuer, an issuance date for the version 1.1
+// of the data model, at least a subject, and well-formed dates and proofs.
+//
+// returns:
+//
+//	err error wrapping ErrInvalidCredential
+func (vc *VerifiableCredential) Validate() error {
+	return vc.validate(false)
+}
+
+// ValidateDerived Check the properties of a derived credential, which the frame may have left undisclosed:
+// as Validate, but the issuer and the issuance date are only checked when present.
+//
+// returns:
+//
+//	err error wrapping ErrInvalidCredential
+func (vc *VerifiableCredential) ValidateDerived() error {
+	return vc.validate(true)
+}
+
+// validate Check the properties of a credential, the issuer and the issuance date being optional if derived.
+func (vc *VerifiableCredential) validate(derived bool) error {
+	if vc == nil {
+		return fmt.Errorf("%w: empty document", ErrInvalidCredential)
+	}
+	if err := validateBaseContext(vc.Context); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidCredential, err.Error())
+	}
+	if !slices.Contains(vc.Type, "VerifiableCredential") {
+		return fmt.Errorf("%w: type must include 'VerifiableCredential'", ErrInvalidCredential)
+	}
+	if vc.ID != "" && !strings.Contains(vc.ID, ":") {
+		return fmt.Errorf("%w: '%s' must be a URI", ErrInvalidCredential, c.CredentialFieldId)
+	}
+	if (vc.Issuer != nil || !derived) && (vc.Issuer == nil || !strings.Contains(vc.Issuer.ID, ":")) {
+		return fmt.Errorf("%w: '%s' must be a URI or an object with a URI identifier", ErrInvalidCredential, c.CredentialFieldIssuer)
+	}
+	if vc.IssuanceDate == "" && vc.Context[0] == c.ContextCredentialV1 && !derived {
+		return fmt.Errorf("%w: missing '%s'", ErrInvalidCredential, c.CredentialFieldIssuanceDate)
+	}
+	for name, date := range map[string]string{c.CredentialFieldIssuanceDate: vc.IssuanceDate, c.CredentialFieldValidFrom: vc.ValidFrom} {
+		if _, err := time.Parse(time.RFC3339Nano, date); date != "" && err != nil {
+			return fmt.Errorf("%w: '%s' is not a date-time: %s", ErrInvalidCredential, name, err.Error())
+		}
+	}
+	if len(vc.CredentialSubject) == 0 {
+		return fmt.Errorf("%w: missing '%s'", ErrInvalidCredential, c.CredentialFieldCredentialSubject)
+	}
+	for _, subject := range vc.CredentialSubject {
+		if subject == nil {
+			return fmt.Errorf("%w: empty '%s'", ErrInvalidCredential, c.CredentialFieldCredentialSubject)
+		}
+	}
+	for _, proof := range vc.Proof {
+		if err := proof.Validate(); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidCredential, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// validateBaseContext Check that a list of contexts starts with a base context of the data model.
+func validateBaseContext(contexts []interface{}) error {
+	if len(contexts) == 0 {
+		return fmt.Errorf("missing '%s'", c.CredentialFieldContext)
+	}
+	if contexts[0] != c.ContextCredentialV1 && contexts[0] != c.ContextCredentialV2 {
+		return fmt.Errorf("the first '%s' must be %s or %s", c.CredentialFieldContext, c.ContextCredentialV1, c.ContextCredentialV2)
+	}
+
+	return nil
+}
+
+func (i *Issuer) UnmarshalJSON(data []byte) error {
+	var id string
+	if err := json.Unmarshal(data, &id); err == nil {
+		*i = Issuer{ID: id}
+		return nil
+	}
+
+	fields, extra, err := splitObject(data, c.CredentialFieldId)
+	if err != nil {
+		return err
+	}
+	*i = Issuer{Extra: extra}
+
+	return decodeField(fields, c.CredentialFieldId, &i.ID)
+}
+
+func (i *Issuer) MarshalJSON() ([]byte, error) {
+	if len(i.Extra) == 0 {
+		return json.Marshal(i.ID)
+	}
+
+	return mergeObject(map[string]interface{}{c.CredentialFieldId: i.ID}, i.Extra)
+}
+
+func (s *CredentialSubject) UnmarshalJSON(data []byte) error {
+	fields, claims, err := splitObject(data, c.CredentialFieldId)
+	if err != nil {
+		return err
+	}
+	*s = CredentialSubject{Claims: claims}
+
+	return decodeField(fields, c.CredentialFieldId, &s.ID)
+}
+
+func (s *CredentialSubject) MarshalJSON() ([]byte, error) {
+	return mergeObject(map[string]interface{}{c.CredentialFieldId: s.ID}, s.Claims)
+}
@@ -0,0 +1,127 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+
+	c "github.com/hyperledger-labs/jsonld-vc-bbs-go/constants"
+)
+
+// VerifiablePresentation A typed verifiable presentation of credentials, e.g. derived proofs, to a verifier.
+type VerifiablePresentation struct {
+	Context              []interface{}
+	ID                   string
+	Type                 []string
+	Holder               string
+	VerifiableCredential []*VerifiableCredential
+	Proof                []*Proof
+	Extra                map[string]interface{}
+}
+
+var presentationFields = []string{
+	c.CredentialFieldContext, c.CredentialFieldId, c.CredentialFieldType, c.CredentialFieldHolder,
+	c.CredentialFieldVerifiableCredential, c.CredentialFieldProof,
+}
+
+// ParseVerifiablePresentation Parse the JSON representation of a presentation.
+//
+//	data []byte
+//
+// returns:
+//
+//	presentation *VerifiablePresentation not validated
+//	err error
+func ParseVerifiablePresentation(data []byte) (*VerifiablePresentation, error) {
+	presentation := &VerifiablePresentation{}
+	if err := presentation.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+
+	return presentation, nil
+}
+
+// VerifiablePresentationFromMap Convert the map representation of a presentation.
+func VerifiablePresentationFromMap(presentation map[string]interface{}) (*VerifiablePresentation, error) {
+	typed := &VerifiablePresentation{}
+	if err := fromMap(presentation, typed); err != nil {
+		return nil, err
+	}
+
+	return typed, nil
+}
+
+// ToMap Convert the presentation to its map representation.
+func (vp *VerifiablePresentation) ToMap() (map[string]interface{}, error) {
+	return toMap(vp)
+}
+
+func (vp *VerifiablePresentation) UnmarshalJSON(data []byte) error {
+	fields, extra, err := splitObject(data, presentationFields...)
+	if err != nil {
+		return err
+	}
+
+	*vp = VerifiablePresentation{Extra: extra}
+	if vp.Context, err = decodeOneOrMany[interface{}](fields, c.CredentialFieldContext); err != nil {
+		return err
+	}
+	if vp.Type, err = decodeOneOrMany[string](fields, c.CredentialFieldType); err != nil {
+		return err
+	}
+	if vp.VerifiableCredential, err = decodeOneOrMany[*VerifiableCredential](fields, c.CredentialFieldVerifiableCredential); err != nil {
+		return err
+	}
+	if vp.Proof, err = decodeOneOrMany[*Proof](fields, c.CredentialFieldProof); err != nil {
+		return err
+	}
+	if err := decodeField(fields, c.CredentialFieldId, &vp.ID); err != nil {
+		return err
+	}
+
+	return decodeField(fields, c.CredentialFieldHolder, &vp.Holder)
+}
+
+func (vp *VerifiablePresentation) MarshalJSON() ([]byte, error) {
+	return mergeObject(map[string]interface{}{
+		c.CredentialFieldContext:              vp.Context,
+		c.CredentialFieldId:                   vp.ID,
+		c.CredentialFieldType:                 vp.Type,
+		c.CredentialFieldHolder:               vp.Holder,
+		c.CredentialFieldVerifiableCredential: vp.VerifiableCredential,
+		c.CredentialFieldProof:                oneOrMany(vp.Proof),
+	}, vp.Extra)
+}
+
+// Validate Check the properties required by the Verifiable Credentials Data Model:
+// the base context first, the VerifiablePresentation type, and valid credentials and proofs.
+// The credentials of a presentation being derived, they are checked with ValidateDerived.
+//
+// returns:
+//
+//	err error wrapping ErrInvalidPresentation, and ErrInvalidCredential for an invalid credential
+func (vp *VerifiablePresentation) Validate() error {
+	if vp == nil {
+		return fmt.Errorf("%w: empty document", ErrInvalidPresentation)
+	}
+	if err := validateBaseContext(vp.Context); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidPresentation, err.Error())
+	}
+	if !slices.Contains(vp.Type, "VerifiablePresentation") {
+		return fmt.Errorf("%w: type must include 'VerifiablePresentation'", ErrInvalidPresentation)
+	}
+	for i, credential := range vp.VerifiableCredential {
+		if credential == nil {
+			return fmt.Errorf("%w: empty credential %d", ErrInvalidPresentation, i)
+		}
+		if err := credential.ValidateDerived(); err != nil {
+			return fmt.Errorf("%w: credential %d: %w", ErrInvalidPresentation, i, err)
+		}
+	}
+	for _, proof := range vp.Proof {
+		if err := proof.Validate(); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidPresentation, err.Error())
+		}
+	}
+
+	return nil
+}
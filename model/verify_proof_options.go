@@ -0,0 +1,13 @@
+package model
+
+// VerifyProofOptions Set of options to use to customize the verification of a derived proof.
+type VerifyProofOptions struct {
+	NonceChecker NonceChecker // optional, if provided the nonce embedded in the proof must be outstanding and will be consumed
+	SessionID    string       // the verifier session the nonce is expected to be bound to
+
+	RequireHolderBinding bool // if true, every derived proof must prove the knowledge of the holder secret
+
+	RequiredPredicates []Predicate // predicates that must be implied by the predicates proven by the derived proofs
+
+	VerifierID string // if not empty, every derived proof must carry a pseudonym of the holder for this verifier
+}
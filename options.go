@@ -0,0 +1,275 @@
+package jsonldbbs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+	"github.com/piprate/json-gold/ld"
+)
+
+// An Option configures an Issuer, a Holder or a Verifier. The options that do not apply to a role are ignored.
+type Option func(*config) error
+
+// config holds the configuration shared by the roles.
+type config struct {
+	publicKey    []byte
+	privateKey   []byte
+	signer       model.Signer
+	suiteOptions model.SignatureSuiteOptions
+	holderSecret model.HolderSecret
+	holderID     string
+	nonceChecker model.NonceChecker
+}
+
+// newConfig Apply the options to an empty configuration.
+func newConfig(options []Option) (*config, error) {
+	cfg := &config{}
+	for _, option := range options {
+		if err := option(cfg); err != nil {
+			return nil, err
+		}
+	}
+	if err := core.CheckIssuerBindingPolicy(&cfg.suiteOptions); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// WithKeyPair configures the BBS+ key pair of the issuer.
+// arguments:
+//
+//	publicKey []byte
+//	privateKey []byte
+func WithKeyPair(publicKey, privateKey []byte) Option {
+	return func(cfg *config) error {
+		if len(publicKey) == 0 || len(privateKey) == 0 {
+			return errors.New("empty key pair")
+		}
+		cfg.publicKey = publicKey
+		cfg.privateKey = privateKey
+
+		return nil
+	}
+}
+
+// WithSigner configures the signer holding the private key of the issuer, e.g. backed by a KMS or an HSM.
+// arguments:
+//
+//	signer model.Signer
+func WithSigner(signer model.Signer) Option {
+	return func(cfg *config) error {
+		if signer == nil {
+			return errors.New("nil signer")
+		}
+		cfg.signer = signer
+
+		return nil
+	}
+}
+
+// WithIssuerPublicKey configures the BBS+ public key of the issuer of the credentials held or verified.
+// arguments:
+//
+//	publicKey []byte
+func WithIssuerPublicKey(publicKey []byte) Option {
+	return func(cfg *config) error {
+		if len(publicKey) == 0 {
+			return errors.New("empty public key")
+		}
+		cfg.publicKey = publicKey
+
+		return nil
+	}
+}
+
+// WithDocumentLoader configures the loader of the JSON-LD contexts, instead of the default one.
+// arguments:
+//
+//	loader ld.DocumentLoader
+func WithDocumentLoader(loader ld.DocumentLoader) Option {
+	return func(cfg *config) error {
+		cfg.suiteOptions.DocumentLoader = loader
+		return nil
+	}
+}
+
+// WithContexts adds preloaded JSON-LD contexts, by URL.
+// arguments:
+//
+//	contexts map[string]map[string]interface{}
+func WithContexts(contexts map[string]map[string]interface{}) Option {
+	return func(cfg *config) error {
+		if cfg.suiteOptions.Contexts == nil {
+			cfg.suiteOptions.Contexts = make(map[string]map[string]interface{}, len(contexts))
+		}
+		for url, context := range contexts {
+			cfg.suiteOptions.Contexts[url] = context
+		}
+
+		return nil
+	}
+}
+
+// WithResourceLimits configures the limits enforced while normalizing the documents, e.g. model.DefaultResourceLimits().
+// No limit is enforced unless configured.
+// arguments:
+//
+//	limits *model.ResourceLimits
+func WithResourceLimits(limits *model.ResourceLimits) Option {
+	return func(cfg *config) error {
+		cfg.suiteOptions.Limits = limits
+		return nil
+	}
+}
+
+// WithDidResolver configures the resolver used to check that the verification methods are controlled by the issuers.
+// arguments:
+//
+//	resolver model.DidResolver
+func WithDidResolver(resolver model.DidResolver) Option {
+	return func(cfg *config) error {
+		cfg.suiteOptions.DidResolver = resolver
+		return nil
+	}
+}
+
+// WithoutIssuerBinding disables the check that the verification methods of the proofs are controlled by the issuers,
+// for the callers binding the issuer public key to the issuer by other means.
+// The issuer id of a credential is then only asserted by the credential: a policy can trust the issuer keys
+// (TrustedKeys) but not the issuer ids (TrustedIssuers), the options are rejected with model.ErrTrustedIssuersWithoutBinding.
+func WithoutIssuerBinding() Option {
+	return func(cfg *config) error {
+		cfg.suiteOptions.SkipIssuerBinding = true
+		return nil
+	}
+}
+
+// WithVerificationMethod configures the verification method written by the issuer in the proofs,
+// instead of the did:key of its public key.
+// arguments:
+//
+//	verificationMethod string
+func WithVerificationMethod(verificationMethod string) Option {
+	return func(cfg *config) error {
+		cfg.suiteOptions.VerificationMethod = verificationMethod
+		return nil
+	}
+}
+
+// WithPolicy configures the policy evaluated by the verifier on the credentials after their cryptographic verification.
+// arguments:
+//
+//	policy *model.VerificationPolicy
+func WithPolicy(policy *model.VerificationPolicy) Option {
+	return func(cfg *config) error {
+		cfg.suiteOptions.Policy = policy
+		return nil
+	}
+}
+
+// WithPredicateClaims configures the IRIs of the claims signed with an encoding allowing predicate proofs.
+// The issuer, the holders and the verifiers must use the same claims.
+// arguments:
+//
+//	claims ...string
+func WithPredicateClaims(claims ...string) Option {
+	return func(cfg *config) error {
+		cfg.suiteOptions.PredicateClaims = append(cfg.suiteOptions.PredicateClaims, claims...)
+		return nil
+	}
+}
+
+// WithClock configures the clock giving the creation time of the proofs and of the issued credentials,
+// and the current time of the verification policies, instead of time.Now.
+// arguments:
+//
+//	clock func() time.Time
+func WithClock(clock func() time.Time) Option {
+	return func(cfg *config) error {
+		if clock == nil {
+			return errors.New("nil clock")
+		}
+		cfg.suiteOptions.Clock = clock
+
+		return nil
+	}
+}
+
+// WithRand configures the source of the random factors of the signatures, of the derived proofs and of the holder binding requests,
+// instead of crypto/rand.Reader, e.g. a seeded generator to produce reproducible test vectors.
+// A signer configured with WithSigner draws its own randomness.
+// arguments:
+//
+//	rand io.Reader
+func WithRand(rand io.Reader) Option {
+	return func(cfg *config) error {
+		if rand == nil {
+			return errors.New("nil source of randomness")
+		}
+		cfg.suiteOptions.Rand = rand
+
+		return nil
+	}
+}
+
+// WithCiphersuite configures the BBS ciphersuite of the signatures and of the derived proofs, instead of the BBS+ scheme.
+// The ciphersuites of the IETF draft do not support the predicate proofs, the holder binding, the pseudonyms
+// and the linked proofs. The issuer, the holders and the verifiers must use the same ciphersuite,
+// and a signer configured with WithSigner must create the signatures of this ciphersuite.
+// arguments:
+//
+//	ciphersuite model.BbsCiphersuite e.g. model.BbsCiphersuiteBLS12381Sha256
+func WithCiphersuite(ciphersuite model.BbsCiphersuite) Option {
+	return func(cfg *config) error {
+		switch ciphersuite {
+		case model.BbsCiphersuiteBBSPlus, model.BbsCiphersuiteBLS12381Sha256, model.BbsCiphersuiteBLS12381Shake256:
+			cfg.suiteOptions.Ciphersuite = ciphersuite
+			return nil
+		default:
+			return fmt.Errorf("%w: '%s'", model.ErrUnknownCiphersuite, ciphersuite)
+		}
+	}
+}
+
+// WithHolderSecret configures the secret of the holder, to which its credentials are bound.
+// arguments:
+//
+//	secret model.HolderSecret
+func WithHolderSecret(secret model.HolderSecret) Option {
+	return func(cfg *config) error {
+		if len(secret) == 0 {
+			return errors.New("empty holder secret")
+		}
+		cfg.holderSecret = secret
+
+		return nil
+	}
+}
+
+// WithHolderID configures the identifier of the holder written in its presentations.
+// arguments:
+//
+//	holderID string
+func WithHolderID(holderID string) Option {
+	return func(cfg *config) error {
+		cfg.holderID = holderID
+		return nil
+	}
+}
+
+// WithNonceChecker configures the checker of the nonces of the derived proofs, e.g. a NonceManager,
+// used by the verifier unless the options of a verification provide another one.
+// arguments:
+//
+//	checker model.NonceChecker
+func WithNonceChecker(checker model.NonceChecker) Option {
+	return func(cfg *config) error {
+		cfg.nonceChecker = checker
+		return nil
+	}
+}
@@ -0,0 +1,150 @@
+package jsonldbbs
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/internal/core"
+	"github.com/hyperledger-labs/jsonld-vc-bbs-go/model"
+)
+
+// A Verifier verifies the credentials signed by an issuer, and the proofs derived from them.
+// A Verifier is safe for concurrent use, provided that its document loader, resolver and nonce checker are.
+type Verifier struct {
+	nonceChecker   model.NonceChecker // nil if the nonces are not checked by default
+	signatureSuite *core.SignatureSuite2020
+	proofSuite     *core.SignatureProofSuite2020
+}
+
+// NewVerifier creates new verifier
+// arguments:
+//
+//	options ...Option WithIssuerPublicKey is required
+//
+// returns:
+//
+//	verifier *Verifier
+//	err error
+func NewVerifier(options ...Option) (*Verifier, error) {
+	cfg, err := newConfig(options)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.publicKey == nil {
+		return nil, errors.New("the public key of the issuer is required")
+	}
+
+	return &Verifier{
+		nonceChecker:   cfg.nonceChecker,
+		signatureSuite: core.NewSignatureSuite2020(cfg.publicKey, nil, &cfg.suiteOptions),
+		proofSuite:     core.NewSignatureProofSuite2020(cfg.publicKey, &cfg.suiteOptions),
+	}, nil
+}
+
+// VerifyCredential verifies a signed credential.
+// arguments:
+//
+//	ctx context.Context
+//	credential model.JsonLdCredential
+//
+// returns:
+//
+//	result *model.VerificationResult
+func (v *Verifier) VerifyCredential(ctx context.Context, credential model.JsonLdCredential) *model.VerificationResult {
+	return v.signatureSuite.VerifyContext(ctx, credential)
+}
+
+// VerifyProof verifies a derived credential.
+// arguments:
+//
+//	ctx context.Context
+//	credential model.JsonLdCredential the framed credential together with its derived proofs
+//	options *model.VerifyProofOptions nullable, the nonce checker of the verifier is used if none is provided
+//
+// returns:
+//
+//	result *model.VerificationResult
+func (v *Verifier) VerifyProof(ctx context.Context, credential model.JsonLdCredential, options *model.VerifyProofOptions) *model.VerificationResult {
+	return v.proofSuite.VerifyProofContext(ctx, credential, v.verifyOptions(options))
+}
+
+// VerifyPresentation verifies the derived credentials of a presentation.
+// The derived proofs must share the same nonce, which is consumed once, and the same pseudonym, if any.
+// arguments:
+//
+//	ctx context.Context
+//	presentation *model.VerifiablePresentation
+//	options *model.VerifyProofOptions nullable, the nonce checker of the verifier is used if none is provided
+//
+// returns:
+//
+//	result *model.VerificationResult
+func (v *Verifier) VerifyPresentation(
+	ctx context.Context,
+	presentation *model.VerifiablePresentation,
+	options *model.VerifyProofOptions,
+) *model.VerificationResult {
+	if err := presentation.Validate(); err != nil {
+		return &model.VerificationResult{Success: false, Error: err}
+	}
+	if len(presentation.VerifiableCredential) == 0 {
+		return &model.VerificationResult{Success: false, Error: fmt.Errorf("%w: no credential", model.ErrInvalidPresentation)}
+	}
+
+	// the nonce is consumed once all the credentials are verified
+	options = v.verifyOptions(options)
+	credentialOptions := model.VerifyProofOptions{}
+	if options != nil {
+		credentialOptions = *options
+		credentialOptions.NonceChecker = nil
+	}
+
+	var nonce, pseudonym []byte
+	for i, credential := range presentation.VerifiableCredential {
+		result := v.proofSuite.VerifyProofCredential(ctx, credential, &credentialOptions)
+		if !result.Success {
+			return &model.VerificationResult{Success: false, Error: fmt.Errorf("credential %d: %w", i, result.Error)}
+		}
+		if i > 0 && !bytes.Equal(pseudonym, result.Pseudonym) {
+			return &model.VerificationResult{Success: false, Error: model.ErrPseudonymMismatch}
+		}
+		pseudonym = result.Pseudonym
+
+		for _, proof := range credential.Proof {
+			proofNonce, err := base64.StdEncoding.DecodeString(proof.Nonce)
+			if err != nil {
+				return &model.VerificationResult{Success: false, Error: fmt.Errorf("The nonce is not in base64: %w", err)}
+			}
+			if nonce != nil && !bytes.Equal(nonce, proofNonce) {
+				return &model.VerificationResult{Success: false, Error: model.ErrNonceMismatch}
+			}
+			nonce = proofNonce
+		}
+	}
+
+	if options != nil && options.NonceChecker != nil {
+		if err := options.NonceChecker.ConsumeNonce(options.SessionID, nonce); err != nil {
+			return &model.VerificationResult{Success: false, Error: err}
+		}
+	}
+
+	return &model.VerificationResult{Success: true, Pseudonym: pseudonym}
+}
+
+// verifyOptions Add the nonce checker of the verifier to the options of a verification, if none is provided.
+func (v *Verifier) verifyOptions(options *model.VerifyProofOptions) *model.VerifyProofOptions {
+	if v.nonceChecker == nil || options != nil && options.NonceChecker != nil {
+		return options
+	}
+
+	withChecker := model.VerifyProofOptions{}
+	if options != nil {
+		withChecker = *options
+	}
+	withChecker.NonceChecker = v.nonceChecker
+
+	return &withChecker
+}